@@ -8,7 +8,13 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 	"pcstakehometest/database/postgres"
+	"pcstakehometest/package/exchange"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/package/payment"
+	"pcstakehometest/package/shipping"
+	"pcstakehometest/package/storage"
+	"pcstakehometest/package/tracking"
 )
 
 var app = &cobra.Command{
@@ -19,9 +25,16 @@ var app = &cobra.Command{
 			fx.Provide(router.NewRouter),
 			fx.Provide(postgres.NewPostgres),
 			fx.Provide(logger.NewLogRus),
+			fx.Provide(storage.NewStorage),
+			fx.Provide(exchange.NewProvider),
+			fx.Provide(notifier.NewNotifier),
+			fx.Provide(payment.NewGateway),
+			fx.Provide(shipping.NewCarrier),
+			fx.Provide(tracking.NewTracker),
 			module.BundleRepository,
 			module.BundleLogic,
 			module.BundleRoute,
+			module.BundleWorker,
 			fx.Invoke(registerHooks),
 		).Run()
 	},
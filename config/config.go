@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"pcstakehometest/package/money"
+
+	"github.com/labstack/gommon/bytes"
 	"github.com/spf13/viper"
 	str2duration "github.com/xhit/go-str2duration/v2"
 )
@@ -13,6 +16,9 @@ var c *Config
 const (
 	Development = "Development"
 	Production  = "Production"
+
+	BodyLimit      = "10M"
+	BodyLimitBytes = 10 * 1024 * 1024
 )
 
 type Config struct {
@@ -33,6 +39,77 @@ type Config struct {
 		ExpireAccessTokenDuration  time.Duration
 		ExpireRefreshTokenDuration time.Duration
 	} `yaml:"auth"`
+	Inventory struct {
+		ReservationTimeout         string `yaml:"reservationTimeout"`
+		ReservationTimeoutDuration time.Duration
+	} `yaml:"inventory"`
+	Transaction struct {
+		CancelWindow         string `yaml:"cancelWindow"`
+		CancelWindowDuration time.Duration
+	} `yaml:"transaction"`
+	Idempotency struct {
+		Retention         string `yaml:"retention"`
+		Lease             string `yaml:"lease"`
+		RetentionDuration time.Duration
+		LeaseDuration     time.Duration
+	} `yaml:"idempotency"`
+	Payment struct {
+		Provider      string `yaml:"provider"`
+		WebhookSecret string `yaml:"webhookSecret"`
+		Mock          struct {
+			Outcome string `yaml:"outcome"`
+		} `yaml:"mock"`
+	} `yaml:"payment"`
+	Storage struct {
+		Driver            string `yaml:"driver"`
+		MaxImageSize      string `yaml:"maxImageSize"`
+		MaxImageSizeBytes int64
+		MaxImagePixels    int `yaml:"maxImagePixels"`
+		Local             struct {
+			Path    string `yaml:"path"`
+			BaseURL string `yaml:"baseURL"`
+		} `yaml:"local"`
+		S3 struct {
+			Endpoint  string `yaml:"endpoint"`
+			Region    string `yaml:"region"`
+			Bucket    string `yaml:"bucket"`
+			AccessKey string `yaml:"accessKey"`
+			SecretKey string `yaml:"secretKey"`
+			BaseURL   string `yaml:"baseURL"`
+		} `yaml:"s3"`
+	} `yaml:"storage"`
+	Currency struct {
+		Base      string `yaml:"base"`
+		Provider  string `yaml:"provider"`
+		RatesFile string `yaml:"ratesFile"`
+	} `yaml:"currency"`
+	Shipping struct {
+		Provider      string `yaml:"provider"`
+		RatesFile     string `yaml:"ratesFile"`
+		DefaultWeight int    `yaml:"defaultWeight"`
+	} `yaml:"shipping"`
+	Coupon struct {
+		Value                  string `yaml:"value"`
+		Expiry                 string `yaml:"expiry"`
+		ExpireInterval         string `yaml:"expireInterval"`
+		ValueAmount            money.Amount
+		ExpiryDuration         time.Duration
+		ExpireIntervalDuration time.Duration
+	} `yaml:"coupon"`
+	Tracking struct {
+		Provider             string `yaml:"provider"`
+		WebhookSecret        string `yaml:"webhookSecret"`
+		PollInterval         string `yaml:"pollInterval"`
+		PollIntervalDuration time.Duration
+	} `yaml:"tracking"`
+	Notifier struct {
+		Driver  string `yaml:"driver"`
+		Webhook struct {
+			URL             string `yaml:"url"`
+			Timeout         string `yaml:"timeout"`
+			TimeoutDuration time.Duration
+		} `yaml:"webhook"`
+	} `yaml:"notifier"`
 }
 
 func Get() *Config {
@@ -64,5 +141,63 @@ func SetConfig() {
 		panic(fmt.Sprintf("config auth refresh token duration string not valid: %s", err.Error()))
 	}
 
+	c.Inventory.ReservationTimeoutDuration, err = str2duration.ParseDuration(c.Inventory.ReservationTimeout)
+	if err != nil {
+		panic(fmt.Sprintf("config inventory reservation timeout duration string not valid: %s", err.Error()))
+	}
+
+	c.Transaction.CancelWindowDuration, err = str2duration.ParseDuration(c.Transaction.CancelWindow)
+	if err != nil {
+		panic(fmt.Sprintf("config transaction cancel window duration string not valid: %s", err.Error()))
+	}
+
+	c.Idempotency.RetentionDuration, err = str2duration.ParseDuration(c.Idempotency.Retention)
+	if err != nil {
+		panic(fmt.Sprintf("config idempotency retention duration string not valid: %s", err.Error()))
+	}
+
+	c.Idempotency.LeaseDuration, err = str2duration.ParseDuration(c.Idempotency.Lease)
+	if err != nil {
+		panic(fmt.Sprintf("config idempotency lease duration string not valid: %s", err.Error()))
+	}
+
+	c.Tracking.PollIntervalDuration, err = str2duration.ParseDuration(c.Tracking.PollInterval)
+	if err != nil {
+		panic(fmt.Sprintf("config tracking poll interval duration string not valid: %s", err.Error()))
+	}
+
+	c.Coupon.ValueAmount, err = money.Parse(c.Coupon.Value)
+	if err != nil || !c.Coupon.ValueAmount.IsPositive() {
+		panic(fmt.Sprintf("config coupon value must be positive amount: %s", c.Coupon.Value))
+	}
+
+	c.Coupon.ExpiryDuration, err = str2duration.ParseDuration(c.Coupon.Expiry)
+	if err != nil {
+		panic(fmt.Sprintf("config coupon expiry duration string not valid: %s", err.Error()))
+	}
+
+	c.Coupon.ExpireIntervalDuration, err = str2duration.ParseDuration(c.Coupon.ExpireInterval)
+	if err != nil {
+		panic(fmt.Sprintf("config coupon expire interval duration string not valid: %s", err.Error()))
+	}
+
+	c.Storage.MaxImageSizeBytes, err = bytes.Parse(c.Storage.MaxImageSize)
+	if err != nil {
+		panic(fmt.Sprintf("config storage max image size string not valid: %s", err.Error()))
+	}
+
+	if c.Storage.MaxImageSizeBytes >= BodyLimitBytes {
+		panic(fmt.Sprintf("config storage max image size must be below body limit %s", BodyLimit))
+	}
+
+	if c.Storage.MaxImagePixels <= 0 {
+		panic("config storage max image pixels must be positive")
+	}
+
+	c.Notifier.Webhook.TimeoutDuration, err = str2duration.ParseDuration(c.Notifier.Webhook.Timeout)
+	if err != nil {
+		panic(fmt.Sprintf("config notifier webhook timeout duration string not valid: %s", err.Error()))
+	}
+
 	viper.WatchConfig()
 }
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type CancellationStatusType int
+
+const (
+	CancellationStatusTypeRequested CancellationStatusType = 1
+	CancellationStatusTypeApproved  CancellationStatusType = 2
+	CancellationStatusTypeDeclined  CancellationStatusType = 3
+)
+
+func (t CancellationStatusType) String() string {
+	switch t {
+	case CancellationStatusTypeRequested:
+		return "Requested"
+	case CancellationStatusTypeApproved:
+		return "Approved"
+	case CancellationStatusTypeDeclined:
+		return "Declined"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t CancellationStatusType) IsValid() error {
+	switch t {
+	case CancellationStatusTypeRequested, CancellationStatusTypeApproved, CancellationStatusTypeDeclined:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Pembatalan")
+}
@@ -0,0 +1,39 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type CouponLedgerType int
+
+const (
+	CouponLedgerTypeEarn   CouponLedgerType = 1
+	CouponLedgerTypeRedeem CouponLedgerType = 2
+	CouponLedgerTypeExpire CouponLedgerType = 3
+	CouponLedgerTypeRevert CouponLedgerType = 4
+)
+
+func (t CouponLedgerType) String() string {
+	switch t {
+	case CouponLedgerTypeEarn:
+		return "Earn"
+	case CouponLedgerTypeRedeem:
+		return "Redeem"
+	case CouponLedgerTypeExpire:
+		return "Expire"
+	case CouponLedgerTypeRevert:
+		return "Revert"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t CouponLedgerType) IsValid() error {
+	switch t {
+	case CouponLedgerTypeEarn, CouponLedgerTypeRedeem, CouponLedgerTypeExpire, CouponLedgerTypeRevert:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Mutasi Kupon")
+}
@@ -0,0 +1,39 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type CouponRuleType int
+
+const (
+	CouponRuleTypeItemPriceBand      CouponRuleType = 1
+	CouponRuleTypeTotalThreshold     CouponRuleType = 2
+	CouponRuleTypeSellerMultiplier   CouponRuleType = 3
+	CouponRuleTypeCategoryMultiplier CouponRuleType = 4
+)
+
+func (t CouponRuleType) String() string {
+	switch t {
+	case CouponRuleTypeItemPriceBand:
+		return "Item Price Band"
+	case CouponRuleTypeTotalThreshold:
+		return "Total Threshold"
+	case CouponRuleTypeSellerMultiplier:
+		return "Seller Multiplier"
+	case CouponRuleTypeCategoryMultiplier:
+		return "Category Multiplier"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t CouponRuleType) IsValid() error {
+	switch t {
+	case CouponRuleTypeItemPriceBand, CouponRuleTypeTotalThreshold, CouponRuleTypeSellerMultiplier, CouponRuleTypeCategoryMultiplier:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Aturan Kupon")
+}
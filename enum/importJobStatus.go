@@ -0,0 +1,39 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ImportJobStatusType int
+
+const (
+	ImportJobStatusTypePending    ImportJobStatusType = 1
+	ImportJobStatusTypeProcessing ImportJobStatusType = 2
+	ImportJobStatusTypeCompleted  ImportJobStatusType = 3
+	ImportJobStatusTypeFailed     ImportJobStatusType = 4
+)
+
+func (t ImportJobStatusType) String() string {
+	switch t {
+	case ImportJobStatusTypePending:
+		return "Pending"
+	case ImportJobStatusTypeProcessing:
+		return "Processing"
+	case ImportJobStatusTypeCompleted:
+		return "Completed"
+	case ImportJobStatusTypeFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ImportJobStatusType) IsValid() error {
+	switch t {
+	case ImportJobStatusTypePending, ImportJobStatusTypeProcessing, ImportJobStatusTypeCompleted, ImportJobStatusTypeFailed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Import")
+}
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type InventoryMovementType int
+
+const (
+	InventoryMovementTypeAdjustment InventoryMovementType = 1
+	InventoryMovementTypeReserve    InventoryMovementType = 2
+	InventoryMovementTypeRelease    InventoryMovementType = 3
+)
+
+func (t InventoryMovementType) String() string {
+	switch t {
+	case InventoryMovementTypeAdjustment:
+		return "Adjustment"
+	case InventoryMovementTypeReserve:
+		return "Reserve"
+	case InventoryMovementTypeRelease:
+		return "Release"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t InventoryMovementType) IsValid() error {
+	switch t {
+	case InventoryMovementTypeAdjustment, InventoryMovementTypeReserve, InventoryMovementTypeRelease:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Pergerakan Stok")
+}
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type PaymentStatusType int
+
+const (
+	PaymentStatusTypePending   PaymentStatusType = 1
+	PaymentStatusTypeSucceeded PaymentStatusType = 2
+	PaymentStatusTypeFailed    PaymentStatusType = 3
+)
+
+func (t PaymentStatusType) String() string {
+	switch t {
+	case PaymentStatusTypePending:
+		return "Pending"
+	case PaymentStatusTypeSucceeded:
+		return "Succeeded"
+	case PaymentStatusTypeFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t PaymentStatusType) IsValid() error {
+	switch t {
+	case PaymentStatusTypePending, PaymentStatusTypeSucceeded, PaymentStatusTypeFailed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Pembayaran")
+}
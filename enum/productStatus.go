@@ -0,0 +1,39 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ProductStatusType int
+
+const (
+	ProductStatusTypeDraft     ProductStatusType = 1
+	ProductStatusTypeScheduled ProductStatusType = 2
+	ProductStatusTypePublished ProductStatusType = 3
+	ProductStatusTypeArchived  ProductStatusType = 4
+)
+
+func (t ProductStatusType) String() string {
+	switch t {
+	case ProductStatusTypeDraft:
+		return "Draft"
+	case ProductStatusTypeScheduled:
+		return "Scheduled"
+	case ProductStatusTypePublished:
+		return "Published"
+	case ProductStatusTypeArchived:
+		return "Archived"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ProductStatusType) IsValid() error {
+	switch t {
+	case ProductStatusTypeDraft, ProductStatusTypeScheduled, ProductStatusTypePublished, ProductStatusTypeArchived:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Produk")
+}
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type RefundStatusType int
+
+const (
+	RefundStatusTypePending   RefundStatusType = 1
+	RefundStatusTypeSucceeded RefundStatusType = 2
+	RefundStatusTypeFailed    RefundStatusType = 3
+)
+
+func (t RefundStatusType) String() string {
+	switch t {
+	case RefundStatusTypePending:
+		return "Pending"
+	case RefundStatusTypeSucceeded:
+		return "Succeeded"
+	case RefundStatusTypeFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t RefundStatusType) IsValid() error {
+	switch t {
+	case RefundStatusTypePending, RefundStatusTypeSucceeded, RefundStatusTypeFailed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Pengembalian Dana")
+}
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ReservationStatusType int
+
+const (
+	ReservationStatusTypeReserved  ReservationStatusType = 1
+	ReservationStatusTypeCommitted ReservationStatusType = 2
+	ReservationStatusTypeReleased  ReservationStatusType = 3
+)
+
+func (t ReservationStatusType) String() string {
+	switch t {
+	case ReservationStatusTypeReserved:
+		return "Reserved"
+	case ReservationStatusTypeCommitted:
+		return "Committed"
+	case ReservationStatusTypeReleased:
+		return "Released"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ReservationStatusType) IsValid() error {
+	switch t {
+	case ReservationStatusTypeReserved, ReservationStatusTypeCommitted, ReservationStatusTypeReleased:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Reservasi")
+}
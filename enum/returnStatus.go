@@ -0,0 +1,55 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ReturnStatusType int
+
+const (
+	ReturnStatusTypeRequested ReturnStatusType = 1
+	ReturnStatusTypeApproved  ReturnStatusType = 2
+	ReturnStatusTypeRejected  ReturnStatusType = 3
+	ReturnStatusTypeShipped   ReturnStatusType = 4
+	ReturnStatusTypeReceived  ReturnStatusType = 5
+	ReturnStatusTypeRefunded  ReturnStatusType = 6
+)
+
+// ReturnStatusOpen status of return still holding item quantity of the transaction
+var ReturnStatusOpen = []ReturnStatusType{
+	ReturnStatusTypeRequested,
+	ReturnStatusTypeApproved,
+	ReturnStatusTypeShipped,
+	ReturnStatusTypeReceived,
+	ReturnStatusTypeRefunded,
+}
+
+func (t ReturnStatusType) String() string {
+	switch t {
+	case ReturnStatusTypeRequested:
+		return "Requested"
+	case ReturnStatusTypeApproved:
+		return "Approved"
+	case ReturnStatusTypeRejected:
+		return "Rejected"
+	case ReturnStatusTypeShipped:
+		return "Shipped"
+	case ReturnStatusTypeReceived:
+		return "Received"
+	case ReturnStatusTypeRefunded:
+		return "Refunded"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ReturnStatusType) IsValid() error {
+	switch t {
+	case ReturnStatusTypeRequested, ReturnStatusTypeApproved, ReturnStatusTypeRejected,
+		ReturnStatusTypeShipped, ReturnStatusTypeReceived, ReturnStatusTypeRefunded:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Retur")
+}
@@ -0,0 +1,36 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ReviewReportStatusType int
+
+const (
+	ReviewReportStatusTypeOpen      ReviewReportStatusType = 1
+	ReviewReportStatusTypeHidden    ReviewReportStatusType = 2
+	ReviewReportStatusTypeDismissed ReviewReportStatusType = 3
+)
+
+func (t ReviewReportStatusType) String() string {
+	switch t {
+	case ReviewReportStatusTypeOpen:
+		return "Open"
+	case ReviewReportStatusTypeHidden:
+		return "Hidden"
+	case ReviewReportStatusTypeDismissed:
+		return "Dismissed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ReviewReportStatusType) IsValid() error {
+	switch t {
+	case ReviewReportStatusTypeOpen, ReviewReportStatusTypeHidden, ReviewReportStatusTypeDismissed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Laporan")
+}
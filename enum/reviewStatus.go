@@ -0,0 +1,33 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type ReviewStatusType int
+
+const (
+	ReviewStatusTypePublished ReviewStatusType = 1
+	ReviewStatusTypeHidden    ReviewStatusType = 2
+)
+
+func (t ReviewStatusType) String() string {
+	switch t {
+	case ReviewStatusTypePublished:
+		return "Published"
+	case ReviewStatusTypeHidden:
+		return "Hidden"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t ReviewStatusType) IsValid() error {
+	switch t {
+	case ReviewStatusTypePublished, ReviewStatusTypeHidden:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Ulasan")
+}
@@ -11,6 +11,7 @@ type RoleType int
 const (
 	RoleTypeSeller RoleType = 1
 	RoleTypeBuyer  RoleType = 2
+	RoleTypeAdmin  RoleType = 3
 )
 
 func (t RoleType) String() string {
@@ -19,6 +20,8 @@ func (t RoleType) String() string {
 		return "Seller"
 	case RoleTypeBuyer:
 		return "Buyer"
+	case RoleTypeAdmin:
+		return "Admin"
 	default:
 		return "Unknown"
 	}
@@ -26,7 +29,7 @@ func (t RoleType) String() string {
 
 func (t RoleType) IsValid() error {
 	switch t {
-	case RoleTypeSeller, RoleTypeBuyer:
+	case RoleTypeSeller, RoleTypeBuyer, RoleTypeAdmin:
 		return nil
 	}
 	return fmt.Errorf(static.DataNotFound, "Role")
@@ -0,0 +1,42 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type TrackingStatusType int
+
+const (
+	TrackingStatusTypePickedUp       TrackingStatusType = 1
+	TrackingStatusTypeInTransit      TrackingStatusType = 2
+	TrackingStatusTypeOutForDelivery TrackingStatusType = 3
+	TrackingStatusTypeDelivered      TrackingStatusType = 4
+	TrackingStatusTypeFailed         TrackingStatusType = 5
+)
+
+func (t TrackingStatusType) String() string {
+	switch t {
+	case TrackingStatusTypePickedUp:
+		return "Picked Up"
+	case TrackingStatusTypeInTransit:
+		return "In Transit"
+	case TrackingStatusTypeOutForDelivery:
+		return "Out For Delivery"
+	case TrackingStatusTypeDelivered:
+		return "Delivered"
+	case TrackingStatusTypeFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t TrackingStatusType) IsValid() error {
+	switch t {
+	case TrackingStatusTypePickedUp, TrackingStatusTypeInTransit, TrackingStatusTypeOutForDelivery, TrackingStatusTypeDelivered, TrackingStatusTypeFailed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Status Pengiriman")
+}
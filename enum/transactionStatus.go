@@ -9,16 +9,46 @@ import (
 type TransactionStatusType int
 
 const (
-	TransactionStatusTypePending TransactionStatusType = 1
-	TransactionStatusTypeAccept  TransactionStatusType = 2
+	TransactionStatusTypePending   TransactionStatusType = 1
+	TransactionStatusTypeAccept    TransactionStatusType = 2
+	TransactionStatusTypeRejected  TransactionStatusType = 3
+	TransactionStatusTypePacked    TransactionStatusType = 4
+	TransactionStatusTypeShipped   TransactionStatusType = 5
+	TransactionStatusTypeDelivered TransactionStatusType = 6
+	TransactionStatusTypeCompleted TransactionStatusType = 7
+	TransactionStatusTypeCancelled TransactionStatusType = 8
+	TransactionStatusTypeRefunded  TransactionStatusType = 9
 )
 
+// TransactionStatusFulfilled status of order seller has accepted and not cancelled, rejected or refunded
+var TransactionStatusFulfilled = []TransactionStatusType{
+	TransactionStatusTypeAccept,
+	TransactionStatusTypePacked,
+	TransactionStatusTypeShipped,
+	TransactionStatusTypeDelivered,
+	TransactionStatusTypeCompleted,
+}
+
 func (t TransactionStatusType) String() string {
 	switch t {
 	case TransactionStatusTypePending:
 		return "Pending"
 	case TransactionStatusTypeAccept:
 		return "Accept"
+	case TransactionStatusTypeRejected:
+		return "Rejected"
+	case TransactionStatusTypePacked:
+		return "Packed"
+	case TransactionStatusTypeShipped:
+		return "Shipped"
+	case TransactionStatusTypeDelivered:
+		return "Delivered"
+	case TransactionStatusTypeCompleted:
+		return "Completed"
+	case TransactionStatusTypeCancelled:
+		return "Cancelled"
+	case TransactionStatusTypeRefunded:
+		return "Refunded"
 	default:
 		return "Unknown"
 	}
@@ -26,7 +56,9 @@ func (t TransactionStatusType) String() string {
 
 func (t TransactionStatusType) IsValid() error {
 	switch t {
-	case TransactionStatusTypePending, TransactionStatusTypeAccept:
+	case TransactionStatusTypePending, TransactionStatusTypeAccept, TransactionStatusTypeRejected,
+		TransactionStatusTypePacked, TransactionStatusTypeShipped, TransactionStatusTypeDelivered,
+		TransactionStatusTypeCompleted, TransactionStatusTypeCancelled, TransactionStatusTypeRefunded:
 		return nil
 	}
 	return fmt.Errorf(static.DataNotFound, "Tipe Status Transaksi")
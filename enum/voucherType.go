@@ -0,0 +1,33 @@
+package enum
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+type VoucherType int
+
+const (
+	VoucherTypePercentage VoucherType = 1
+	VoucherTypeFixed      VoucherType = 2
+)
+
+func (t VoucherType) String() string {
+	switch t {
+	case VoucherTypePercentage:
+		return "Percentage"
+	case VoucherTypeFixed:
+		return "Fixed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (t VoucherType) IsValid() error {
+	switch t {
+	case VoucherTypePercentage, VoucherTypeFixed:
+		return nil
+	}
+	return fmt.Errorf(static.DataNotFound, "Tipe Voucher")
+}
@@ -1,12 +1,17 @@
 package main_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	authRoute "pcstakehometest/module/auth/route"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -15,11 +20,31 @@ import (
 	"pcstakehometest/database/postgres"
 	"pcstakehometest/enum"
 	"pcstakehometest/module"
+	cartRoute "pcstakehometest/module/cart/route"
+	categoryRoute "pcstakehometest/module/category/route"
+	couponRoute "pcstakehometest/module/coupon/route"
+	imageRoute "pcstakehometest/module/image/route"
+	inventoryRoute "pcstakehometest/module/inventory/route"
+	paymentRoute "pcstakehometest/module/payment/route"
 	productRoute "pcstakehometest/module/product/route"
+	reviewRoute "pcstakehometest/module/review/route"
+	rmaRoute "pcstakehometest/module/rma/route"
+	shippingRoute "pcstakehometest/module/shipping/route"
 	transactionRoute "pcstakehometest/module/transaction/route"
+	voucherRoute "pcstakehometest/module/voucher/route"
+	wishlistRoute "pcstakehometest/module/wishlist/route"
+	"pcstakehometest/package/exchange"
 	"pcstakehometest/package/jwt"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/package/payment"
+	"pcstakehometest/package/shipping"
+	"pcstakehometest/package/storage"
+	"pcstakehometest/package/tracking"
 	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
 )
 
 func TestMain(m *testing.M) {
@@ -28,6 +53,12 @@ func TestMain(m *testing.M) {
 		fx.Provide(router.NewRouter),
 		fx.Provide(postgres.NewPostgres),
 		fx.Provide(logger.NewLogRus),
+		fx.Provide(storage.NewStorage),
+		fx.Provide(exchange.NewProvider),
+		fx.Provide(notifier.NewNotifier),
+		fx.Provide(payment.NewGateway),
+		fx.Provide(shipping.NewCarrier),
+		fx.Provide(tracking.NewTracker),
 		module.BundleRepository,
 		module.BundleLogic,
 		module.BundleRoute,
@@ -43,6 +74,19 @@ type RouteTest struct {
 	AuthHandler        authRoute.Handler
 	TransactionHandler transactionRoute.Handler
 	ProductHandler     productRoute.Handler
+	InventoryHandler   inventoryRoute.Handler
+	ImageHandler       imageRoute.Handler
+	ReviewHandler      reviewRoute.Handler
+	WishlistHandler    wishlistRoute.Handler
+	CategoryHandler    categoryRoute.Handler
+	CartHandler        cartRoute.Handler
+	PaymentHandler     paymentRoute.Handler
+	RmaHandler         rmaRoute.Handler
+	ShippingHandler    shippingRoute.Handler
+	CouponHandler      couponRoute.Handler
+	VoucherHandler     voucherRoute.Handler
+	Gateway            payment.PaymentGateway
+	Tracker            tracking.Tracker
 }
 
 var r RouteTest
@@ -56,7 +100,8 @@ func TestTransaction(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
 			"Name":"Testes Product",
     		"Description":"Failed Tested",
-    		"Price":999999
+    		"Price":999999,
+    		"Stock":10
 		}`))
 
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -82,7 +127,8 @@ func TestTransaction(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
 			"Name":"Testes Product",
     		"Description":"Success Tested",
-    		"Price":999999
+    		"Price":999999,
+    		"Stock":10
 		}`))
 
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -155,8 +201,13 @@ func TestTransaction(t *testing.T) {
 	})
 
 	t.Run("SuccessAcceptOrder", func(t *testing.T) {
+		gateway, ok := r.Gateway.(*payment.Mock)
+		if !ok {
+			t.Skip("payment provider is not mock")
+		}
+
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
-			"TransactionID":1
+			"Items":[{"ProductID":1,"Quantity":1}]
 		}`))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
@@ -166,6 +217,58 @@ func TestTransaction(t *testing.T) {
 
 		ctx := c.Request().Context()
 		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		if !assert.NoError(t, r.TransactionHandler.Checkout(c)) || !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					ID           int
+					Transactions []struct{ ID int }
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+
+		// Buyer pay the checkout before seller accept it
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"CheckoutID":`+strconv.Itoa(checkout.Data.Checkout.ID)+`}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		if !assert.NoError(t, r.PaymentHandler.Create(c)) || !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var intent struct {
+			Data struct{ ProviderReference string }
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &intent))
+
+		payload, signature, err := gateway.Webhook(intent.Data.ProviderReference)
+		assert.NoError(t, err)
+
+		req = httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(payment.HeaderSignature, signature)
+		rec = httptest.NewRecorder()
+		if !assert.NoError(t, r.PaymentHandler.Webhook(e.NewContext(req, rec))) || !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"TransactionID":`+strconv.Itoa(checkout.Data.Checkout.Transactions[0].ID)+`
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		ctx = context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, jwt.InternalClaimData{
 			UserID: 1,
 			Role:   enum.RoleTypeSeller,
 		})
@@ -177,6 +280,55 @@ func TestTransaction(t *testing.T) {
 		}
 	})
 
+	t.Run("FailedAcceptOrderNotPaid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Items":[{"ProductID":1,"Quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		if !assert.NoError(t, r.TransactionHandler.Checkout(c)) || !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					Transactions []struct{ ID int }
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"TransactionID":`+strconv.Itoa(checkout.Data.Checkout.Transactions[0].ID)+`
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		ctx = context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.AcceptOrder(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+			assert.Contains(t, rec.Body.String(), static.OrderNotPaid)
+		}
+	})
+
 	t.Run("FailedAcceptOrder", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/accept", strings.NewReader(`{
 			"TransactionID":9999
@@ -376,6 +528,49 @@ func TestTransaction(t *testing.T) {
 		}
 	})
 
+	t.Run("SuccessFindAllForBuyerDisplayCurrency", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/list?seller=1&currency=USD", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.FindAllForBuyer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"DisplayCurrency":"USD"`)
+		}
+	})
+
+	t.Run("FailedFindAllForBuyerCurrencyNotSupported", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/list?seller=1&currency=XXX", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.FindAllForBuyer(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
 	t.Run("FailedFindAllForBuyerUnauthorized", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/list?seller=1", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -392,4 +587,2297 @@ func TestTransaction(t *testing.T) {
 			assert.Equal(t, http.StatusUnauthorized, rec.Code)
 		}
 	})
+
+	t.Run("SuccessAdjustStock", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/inventory/adjustment", strings.NewReader(`{
+			"ProductID":1,
+			"Quantity":5,
+			"Note":"restock"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.InventoryHandler.Adjust(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedAdjustStockBelowZero", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/inventory/adjustment", strings.NewReader(`{
+			"ProductID":1,
+			"Quantity":-999999
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.InventoryHandler.Adjust(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindMovements", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/inventory/movement?product=1", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.InventoryHandler.FindMovements(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedUploadImageNotSupported", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("image", "image.png")
+		part.Write([]byte("not an image"))
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/1/images", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ImageHandler.Upload(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessImportProductDryRun", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/import?dryRun=true", strings.NewReader(
+			"sku,name,description,price,stock\nSKU-1,Imported Product,Imported Tested,15000,3\n",
+		))
+		req.Header.Set(echo.HeaderContentType, "text/csv")
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Import(c)) {
+			assert.Equal(t, http.StatusAccepted, rec.Code)
+		}
+	})
+
+	t.Run("FailedImportProductInvalidHeader", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/import", strings.NewReader(
+			"name,price\nImported Product,15000\n",
+		))
+		req.Header.Set(echo.HeaderContentType, "text/csv")
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Import(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessExportProduct", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product/export", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Export(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, strings.HasPrefix(rec.Body.String(), "sku,name,description,price,currency,stock"))
+		}
+	})
+
+	t.Run("FailedCreateReviewInvalidRating", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/review", strings.NewReader(`{
+			"ProductID":1,
+			"Rating":6
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ReviewHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateReview", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/review", strings.NewReader(`{
+			"ProductID":1,
+			"Rating":5,
+			"Content":"barang sesuai"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ReviewHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateReviewNotEligible", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/review", strings.NewReader(`{
+			"ProductID":1,
+			"Rating":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ReviewHandler.Create(c)) {
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindAllReview", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/review?product=1", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ReviewHandler.FindAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedFindAllReportNotAdmin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/review/report", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ReviewHandler.FindAllReport(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateWishlistSeller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/wishlist", strings.NewReader(`{
+			"ProductID":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.WishlistHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateWishlist", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/wishlist", strings.NewReader(`{
+			"ProductID":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.WishlistHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("SuccessUpdateProductPriceDrop", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/v1/product/1", strings.NewReader(`{
+			"Name":"Tested Product",
+			"Description":"Price Drop",
+			"Price":1000
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Update(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindAllWishlist", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/wishlist", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.WishlistHandler.FindAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"PriceDropped":true`)
+		}
+	})
+
+	t.Run("FailedDeleteWishlistNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/wishlist/9999", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.WishlistHandler.Delete(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateProductDraft", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product", strings.NewReader(`{
+			"Name":"Draft Product",
+			"Description":"Draft Tested",
+			"Price":15000,
+			"Stock":5,
+			"Status":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateProductPublishAtPast", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product", strings.NewReader(`{
+			"Name":"Scheduled Product",
+			"Description":"Scheduled Tested",
+			"Price":15000,
+			"Status":2,
+			"PublishAt":"2020-01-01T00:00:00Z"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindAllDraftForSeller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product?status=1", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.FindAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"StatusProduct":"Draft"`)
+		}
+	})
+
+	t.Run("SuccessFindAllForBuyerHideDraft", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/list?seller=1", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.FindAllForBuyer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.NotContains(t, rec.Body.String(), `"StatusProduct":"Draft"`)
+		}
+	})
+
+	t.Run("FailedUpdateProductStatusNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/v1/product/9999/status", strings.NewReader(`{
+			"Status":4
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.UpdateStatus(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateSaleAboveRegularPrice", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/1/sale", strings.NewReader(`{
+			"SalePrice":99999999,
+			"StartAt":"2020-01-01T00:00:00Z",
+			"EndAt":"2099-01-01T00:00:00Z"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.CreateSale(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateSale", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/1/sale", strings.NewReader(`{
+			"SalePrice":500,
+			"StartAt":"2020-01-01T00:00:00Z",
+			"EndAt":"2099-01-01T00:00:00Z"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.CreateSale(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateSaleOverlap", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product/1/sale", strings.NewReader(`{
+			"SalePrice":400,
+			"StartAt":"2030-01-01T00:00:00Z",
+			"EndAt":"2031-01-01T00:00:00Z"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.CreateSale(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedUpdateProductCurrencyDuringSale", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/v1/product/1", strings.NewReader(`{
+			"Name":"Tested Product",
+			"Description":"Currency Change",
+			"Price":1000,
+			"Currency":"USD"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Update(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("SuccessPriceHistory", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product/1/price-history", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.PriceHistory(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCancelSaleNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/product/1/sale/9999", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "saleId")
+		c.SetParamValues("1", "9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.CancelSale(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateCategoryNotAdmin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/category", strings.NewReader(`{
+			"Name":"Furniture",
+			"Attributes":[]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CategoryHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateCategoryInvalidSchema", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/category", strings.NewReader(`{
+			"Name":"Furniture",
+			"Attributes":[{"Key":"color","Label":"Warna","Type":"enum"}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 3,
+			Role:   enum.RoleTypeAdmin,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CategoryHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateCategory", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/category", strings.NewReader(`{
+			"Name":"Furniture",
+			"Attributes":[
+				{"Key":"weight","Label":"Berat","Type":"number","Required":true,"Unit":"gram"},
+				{"Key":"color","Label":"Warna","Type":"enum","Options":["red","blue"]}
+			]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 3,
+			Role:   enum.RoleTypeAdmin,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CategoryHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateProductMissingAttribute", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product", strings.NewReader(`{
+			"Name":"Kursi Kayu",
+			"Description":"Kursi kayu jati",
+			"Price":150000,
+			"CategoryID":1,
+			"Attributes":{"color":"red"}
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateProductWithAttribute", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/product", strings.NewReader(`{
+			"Name":"Meja Kayu",
+			"Description":"Meja kayu jati",
+			"Price":250000,
+			"Stock":5,
+			"CategoryID":1,
+			"Attributes":{"weight":12000,"color":"Red"}
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindAllForBuyerAttributeFilter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product/list?seller=1&attr.color=red", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 2,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.FindAllForBuyer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "Meja Kayu")
+		}
+	})
+
+	t.Run("SuccessProductDetail", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product/1", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Detail(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "Store")
+			assert.Contains(t, rec.Body.String(), "Related")
+		}
+	})
+
+	t.Run("FailedProductDetailNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/product/9999", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		// Assertions
+		if assert.NoError(t, r.ProductHandler.Detail(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateCartSeller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/cart", strings.NewReader(`{
+			"ProductID":1,
+			"Quantity":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateCart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/cart", strings.NewReader(`{
+			"ProductID":1,
+			"Quantity":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.Create(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("SuccessFindAllCart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/cart", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.FindAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "Totals")
+		}
+	})
+
+	t.Run("FailedUpdateCartNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/v1/cart/9999", strings.NewReader(`{
+			"Quantity":2
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.Update(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessClearCart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/cart", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.Clear(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCheckoutEmptyCart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/cart/checkout", strings.NewReader(`{
+			"SellerID":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CartHandler.Checkout(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateOrderInvalidQuantity", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction", strings.NewReader(`{
+			"SellerID":1,
+			"Items":[{"productId":1,"quantity":0}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateOrderLineItem", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction", strings.NewReader(`{
+			"SellerID":1,
+			"Items":[{"productId":1,"quantity":2}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedCheckoutProductNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/checkout", strings.NewReader(`{
+			"Items":[{"productId":9999,"quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Checkout(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCheckout", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/checkout", strings.NewReader(`{
+			"Items":[{"productId":1,"quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Checkout(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "PaymentReference")
+		}
+	})
+
+	t.Run("FailedFindCheckoutNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/checkout/9999", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.FindCheckout(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedShipOrderInvalidTransition", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Ship(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("FailedCompleteOrderNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Complete(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessTransactionStatusHistory", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.StatusHistory(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedRejectOrderWithoutReason", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":" "
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.RejectOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedRejectOrderNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":"stok habis"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.RejectOrder(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessBulkOrderPartialFailure", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Action":"accept",
+			"TransactionIDs":[9999]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.BulkOrder(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"Failed":1`)
+		}
+	})
+
+	t.Run("FailedBulkOrderInvalidAction", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Action":"ship",
+			"TransactionIDs":[1]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.BulkOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCancelOrderWithoutReason", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":""
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CancelOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCancelOrderNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":"salah pesan"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CancelOrder(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedApproveCancellationNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.ApproveCancellation(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessCreateOrderIdempotentReplay", func(t *testing.T) {
+		key := "test-" + utilities.RandomString(16)
+		createOrder := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(router.HeaderIdempotencyKey, key)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+
+			ctx := c.Request().Context()
+			ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+				UserID: 1,
+				Role:   enum.RoleTypeBuyer,
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			assert.NoError(t, r.TransactionHandler.EchoRoute.Idempotency(r.TransactionHandler.CreateOrder)(c))
+			return rec
+		}
+
+		first := createOrder(`{"SellerID":1,"Items":[1]}`)
+		second := createOrder(`{ "SellerID": 1, "Items": [1] }`)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, first.Code, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(router.HeaderIdempotentReplayed))
+
+		conflict := createOrder(`{"SellerID":1,"Items":[1,1]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, conflict.Code)
+	})
+
+	t.Run("FailedPaymentWebhookInvalidSignature", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", strings.NewReader(`{"ProviderReference":"mock_1","Status":"succeeded"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(payment.HeaderSignature, payment.Sign("wrong", []byte(`{}`)))
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		// Assertions
+		if assert.NoError(t, r.PaymentHandler.Webhook(c)) {
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreatePaymentCheckoutNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment", strings.NewReader(`{
+			"CheckoutID":9999
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.PaymentHandler.Create(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessPaymentBeforeAcceptOrder", func(t *testing.T) {
+		gateway, ok := r.Gateway.(*payment.Mock)
+		if !ok {
+			t.Skip("payment provider is not mock")
+		}
+		defer gateway.SetOutcome(payment.OutcomeSucceed)
+
+		call := func(handler echo.HandlerFunc, body string, id string, claim jwt.InternalClaimData) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			if id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(id)
+			}
+
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, claim)))
+
+			assert.NoError(t, handler(c))
+			return rec
+		}
+		buyer := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeBuyer}
+		seller := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeSeller}
+
+		rec := call(r.TransactionHandler.Checkout, `{"Items":[{"ProductID":1,"Quantity":1}]}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					ID           int
+					Transactions []struct{ ID int }
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+		checkoutID := strconv.Itoa(checkout.Data.Checkout.ID)
+		transactionID := strconv.Itoa(checkout.Data.Checkout.Transactions[0].ID)
+
+		// Seller cannot accept unpaid order
+		rec = call(r.TransactionHandler.AcceptOrder, `{}`, transactionID, seller)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		gateway.SetOutcome(payment.OutcomeTimeout)
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+checkoutID+`}`, "", buyer)
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+
+		gateway.SetOutcome(payment.OutcomeSucceed)
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+checkoutID+`}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var intent struct {
+			Data struct{ ProviderReference string }
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &intent))
+
+		payload, signature, err := gateway.Webhook(intent.Data.ProviderReference)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(payment.HeaderSignature, signature)
+		webhook := httptest.NewRecorder()
+		if assert.NoError(t, r.PaymentHandler.Webhook(echo.New().NewContext(req, webhook))) {
+			assert.Equal(t, http.StatusOK, webhook.Code)
+			assert.Contains(t, webhook.Body.String(), `"StatusPayment":"Succeeded"`)
+		}
+
+		rec = call(r.TransactionHandler.AcceptOrder, `{}`, transactionID, seller)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("SuccessWebhookAfterOrderCancelled", func(t *testing.T) {
+		gateway, ok := r.Gateway.(*payment.Mock)
+		if !ok {
+			t.Skip("payment provider is not mock")
+		}
+
+		call := func(handler echo.HandlerFunc, body string, id string, claim jwt.InternalClaimData) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			if id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(id)
+			}
+
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, claim)))
+
+			assert.NoError(t, handler(c))
+			return rec
+		}
+		buyer := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeBuyer}
+
+		rec := call(r.TransactionHandler.Checkout, `{"Items":[{"ProductID":1,"Quantity":1}]}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					ID           int
+					Transactions []struct{ ID int }
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+		checkoutID := strconv.Itoa(checkout.Data.Checkout.ID)
+		transactionID := strconv.Itoa(checkout.Data.Checkout.Transactions[0].ID)
+
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+checkoutID+`}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var intent struct {
+			Data struct{ ProviderReference string }
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &intent))
+
+		// Order cancelled while buyer still paying, collected money returned instead of callback rejected forever
+		rec = call(r.TransactionHandler.CancelOrder, `{"Reason":"berubah pikiran"}`, transactionID, buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+
+		payload, signature, err := gateway.Webhook(intent.Data.ProviderReference)
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", bytes.NewReader(payload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(payment.HeaderSignature, signature)
+			webhook := httptest.NewRecorder()
+			if assert.NoError(t, r.PaymentHandler.Webhook(echo.New().NewContext(req, webhook))) {
+				assert.Equal(t, http.StatusOK, webhook.Code)
+				assert.Contains(t, webhook.Body.String(), `"StatusPayment":"Succeeded"`)
+			}
+		}
+
+		// Nothing left to pay on the checkout
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+checkoutID+`}`, "", buyer)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("FailedCreateReturnWithoutItems", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"TransactionID":1,"Reason":"barang rusak","Items":[]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.RmaHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateReturnTransactionNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"TransactionID":9999,"Reason":"barang rusak","Items":[{"ProductID":1,"Quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.RmaHandler.Create(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessReturnDiscountedOrder", func(t *testing.T) {
+		gateway, ok := r.Gateway.(*payment.Mock)
+		if !ok {
+			t.Skip("payment provider is not mock")
+		}
+
+		call := func(handler echo.HandlerFunc, body string, id string, claim jwt.InternalClaimData) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			if id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(id)
+			}
+
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, claim)))
+
+			assert.NoError(t, handler(c))
+			return rec
+		}
+		buyer := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeBuyer}
+		seller := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeSeller}
+		code := "RETUR" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+		rec := call(r.VoucherHandler.Create, `{"Code":"`+code+`","Name":"Retur","Type":2,"Currency":"IDR","Value":1000}`, "", seller)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+
+		rec = call(r.TransactionHandler.Checkout, `{"Items":[{"ProductID":1,"Quantity":1}],"Vouchers":[{"SellerID":1,"Code":"`+code+`"}]}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					ID           int
+					Transactions []struct {
+						ID              int
+						GrandTotal      money.Amount
+						ShippingFee     money.Amount
+						VoucherDiscount money.Amount
+					}
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+		transaction := checkout.Data.Checkout.Transactions[0]
+		transactionID := strconv.Itoa(transaction.ID)
+		assert.True(t, transaction.VoucherDiscount.IsPositive())
+
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+strconv.Itoa(checkout.Data.Checkout.ID)+`}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var intent struct {
+			Data struct{ ProviderReference string }
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &intent))
+
+		payload, signature, err := gateway.Webhook(intent.Data.ProviderReference)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(payment.HeaderSignature, signature)
+		webhook := httptest.NewRecorder()
+		if !assert.NoError(t, r.PaymentHandler.Webhook(echo.New().NewContext(req, webhook))) || !assert.Equal(t, http.StatusOK, webhook.Code) {
+			return
+		}
+
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.AcceptOrder, `{}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Pack, `{}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Ship, `{"Carrier":"JNE","TrackingNumber":"`+code+`"}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Deliver, `{}`, transactionID, seller).Code)
+
+		// Full return refund what the items cost after voucher and coupon, not their list price
+		rec = call(r.RmaHandler.Create, `{"TransactionID":`+transactionID+`,"Reason":"barang rusak","Items":[{"ProductID":1,"Quantity":1}]}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var result struct {
+			Data struct {
+				ID           int
+				RefundAmount money.Amount
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Equal(t, transaction.GrandTotal.Sub(transaction.ShippingFee), result.Data.RefundAmount)
+		returnID := strconv.Itoa(result.Data.ID)
+
+		assert.Equal(t, http.StatusOK, call(r.RmaHandler.Approve, `{}`, returnID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.RmaHandler.Ship, `{"Courier":"JNE","TrackingNumber":"`+code+`"}`, returnID, buyer).Code)
+
+		rec = call(r.RmaHandler.Receive, `{}`, returnID, seller)
+		if assert.Equal(t, http.StatusOK, rec.Code) {
+			assert.Contains(t, rec.Body.String(), `"StatusReturn":"Refunded"`)
+		}
+
+		// Every item returned, order refunded even though shipping fee kept
+		rec = call(r.TransactionHandler.StatusHistory, ``, transactionID, seller)
+		if assert.Equal(t, http.StatusOK, rec.Code) {
+			assert.Contains(t, rec.Body.String(), `"To":"Refunded"`)
+		}
+	})
+
+	t.Run("SuccessAdminRefundIdempotentReplay", func(t *testing.T) {
+		gateway, ok := r.Gateway.(*payment.Mock)
+		if !ok {
+			t.Skip("payment provider is not mock")
+		}
+
+		call := func(handler echo.HandlerFunc, body string, id string, claim jwt.InternalClaimData) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			if id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(id)
+			}
+
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, claim)))
+
+			assert.NoError(t, handler(c))
+			return rec
+		}
+		buyer := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeBuyer}
+		seller := jwt.InternalClaimData{UserID: 1, Role: enum.RoleTypeSeller}
+		admin := jwt.InternalClaimData{UserID: 3, Role: enum.RoleTypeAdmin}
+
+		rec := call(r.TransactionHandler.Checkout, `{"Items":[{"ProductID":1,"Quantity":1}]}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var checkout struct {
+			Data struct {
+				Checkout struct {
+					ID           int
+					Transactions []struct{ ID int }
+				}
+			}
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &checkout))
+		transactionID := strconv.Itoa(checkout.Data.Checkout.Transactions[0].ID)
+
+		rec = call(r.PaymentHandler.Create, `{"CheckoutID":`+strconv.Itoa(checkout.Data.Checkout.ID)+`}`, "", buyer)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		var intent struct {
+			Data struct{ ProviderReference string }
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &intent))
+
+		payload, signature, err := gateway.Webhook(intent.Data.ProviderReference)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/webhook", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(payment.HeaderSignature, signature)
+		webhook := httptest.NewRecorder()
+		if !assert.NoError(t, r.PaymentHandler.Webhook(echo.New().NewContext(req, webhook))) || !assert.Equal(t, http.StatusOK, webhook.Code) {
+			return
+		}
+
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.AcceptOrder, `{}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Pack, `{}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Ship, `{"Carrier":"JNE","TrackingNumber":"ADM`+transactionID+`"}`, transactionID, seller).Code)
+		assert.Equal(t, http.StatusOK, call(r.TransactionHandler.Deliver, `{}`, transactionID, seller).Code)
+
+		// Retried refund replayed instead of returning the money twice, gateway called only after commit
+		key := "test-" + utilities.RandomString(16)
+		refund := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"Amount":1,"Reason":"kompensasi"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(router.HeaderIdempotencyKey, key)
+			rec := httptest.NewRecorder()
+
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(transactionID)
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), jwt.InternalClaimData{}, admin)))
+
+			assert.NoError(t, r.TransactionHandler.EchoRoute.Idempotency(r.TransactionHandler.Refund)(c))
+			return rec
+		}
+
+		first := refund()
+		second := refund()
+		if assert.Equal(t, http.StatusOK, first.Code) {
+			assert.Contains(t, first.Body.String(), `"StatusRefund":"Pending"`)
+		}
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(router.HeaderIdempotentReplayed))
+	})
+
+	t.Run("FailedRejectReturnWithoutReason", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":" "
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.RmaHandler.Reject(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedFindReturnNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.RmaHandler.Find(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("SuccessShippingQuote", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Origin":"Jakarta","Destination":"Bandung","Items":[{"ProductID":1,"Quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ShippingHandler.Quote(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedShippingQuoteWithoutOrigin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Destination":"Bandung","Items":[{"ProductID":1,"Quantity":1}]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.ShippingHandler.Quote(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateOrderShippingWithoutDestination", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"SellerID":1,"Items":[1],"Origin":"Jakarta","Carrier":"JNE","Service":"REG"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateOrderShippingServiceNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"SellerID":1,"Items":[1],"Origin":"Jakarta","Destination":"Bandung","Carrier":"JNE","Service":"KILAT"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedTrackingWebhookInvalidSignature", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/tracking/webhook", strings.NewReader(`{"Events":[]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(tracking.HeaderSignature, tracking.Sign("wrong", []byte(`{}`)))
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.TrackingWebhook(c)) {
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("SuccessTrackingWebhookUnknownTrackingNumber", func(t *testing.T) {
+		fake, ok := r.Tracker.(*tracking.Fake)
+		if !ok {
+			t.Skip("tracker is not fake")
+		}
+		payload, signature, err := fake.Webhook(&tracking.Event{
+			Carrier:        "JNE",
+			TrackingNumber: "UNKNOWN-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+			Status:         tracking.StatusInTransit,
+			Description:    "Transit",
+			OccurredAt:     time.Now(),
+		})
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/tracking/webhook", strings.NewReader(string(payload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(tracking.HeaderSignature, signature)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.TrackingWebhook(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedTrackingNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Tracking(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedCreateCouponRuleNotAdmin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Name":"Band","Type":1,"Currency":"IDR","MinAmount":"50000","MaxAmount":"100000","Coupons":1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CouponHandler.CreateRule(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedCouponDryRunWithoutItems", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Items":[]
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CouponHandler.DryRun(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedBuyerCreateOrderNegativeRedeemCoupons", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"SellerID":1,"Items":[1,2],"RedeemCoupons":-1
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedBuyerCreateOrderRedeemCouponsExceedBalance", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"SellerID":1,"Items":[1,2],"RedeemCoupons":1000000
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("SuccessBuyerCouponWallet", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CouponHandler.Wallet(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("FailedSellerCouponWallet", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.CouponHandler.Wallet(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedBuyerCreateVoucher", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Code":"HEMAT10","Type":1,"Currency":"IDR","Value":"10"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.VoucherHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedSellerCreateVoucherPercentageAboveHundred", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Code":"HEMAT150","Type":1,"Currency":"IDR","Value":"150"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.VoucherHandler.Create(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedSellerFindVoucherNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeSeller,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.VoucherHandler.Find(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedBuyerCreateOrderUnknownVoucher", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"SellerID":1,"Items":[1,2],"VoucherCode":"TIDAKADA"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeBuyer,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.CreateOrder(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("FailedRefundNegativeAmount", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Amount":-1000
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeAdmin,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Refund(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("FailedRefundNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+			"Reason":"barang tidak sampai"
+		}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("9999")
+
+		ctx := c.Request().Context()
+		ctx = context.WithValue(ctx, jwt.InternalClaimData{}, jwt.InternalClaimData{
+			UserID: 1,
+			Role:   enum.RoleTypeAdmin,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		// Assertions
+		if assert.NoError(t, r.TransactionHandler.Refund(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
 }
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"pcstakehometest/enum"
+	"time"
+)
+
+type Cancellations struct {
+	ID            int
+	TransactionID int
+	BuyerID       int `json:"-"`
+	SellerID      int `json:"-"`
+	Reason        string
+	Status        enum.CancellationStatusType `json:"-"`
+	Note          *string
+	ResolvedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Attribute
+	StatusCancellation string `gorm:"<-:false;-;"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+type Carts struct {
+	ID            int
+	BuyerID       int `json:"-"`
+	ProductID     int
+	Quantity      int
+	PriceSnapshot money.Amount
+	Currency      string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
+
+	// Relations
+	Product *Products `json:",omitempty" gorm:"<-:false;foreignKey:ProductID;references:ID;"`
+
+	// Attribute
+	Price        money.Amount `gorm:"<-:false;-;"`
+	LineTotal    money.Amount `gorm:"<-:false;-;"`
+	Available    bool         `gorm:"<-:false;-;"`
+	PriceChanged bool         `gorm:"<-:false;-;"`
+	Warning      string       `json:",omitempty" gorm:"<-:false;-;"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/package/attribute"
+
+	"gorm.io/gorm"
+)
+
+type Categories struct {
+	ID         int
+	Name       string
+	Attributes attribute.Schema
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `json:"-"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+type Checkouts struct {
+	ID               int
+	BuyerID          int `json:"-"`
+	PaymentReference string
+	GrandTotal       money.Amount
+	Currency         string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `json:"-"`
+
+	// Relations
+	Transactions []*Transactions `json:",omitempty" gorm:"<-:false;foreignKey:CheckoutID;references:ID;"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/enum"
+)
+
+// CouponLedgers mutation of buyer coupon wallet, credit entry keep Remaining coupon until spent or expired
+type CouponLedgers struct {
+	ID            int
+	BuyerID       int                   `json:"-"`
+	TransactionID *int                  `json:",omitempty"`
+	Type          enum.CouponLedgerType `json:"-"`
+	Amount        int
+	Remaining     int        `json:"-"`
+	ExpiresAt     *time.Time `json:",omitempty"`
+	CreatedAt     time.Time
+
+	// Attribute
+	TypeLedger string `gorm:"<-:false;-;"`
+}
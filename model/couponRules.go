@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+// CouponRules rule of coupon rules engine, bounds in Currency
+type CouponRules struct {
+	ID         int
+	Name       string
+	Type       enum.CouponRuleType `json:"-"`
+	Currency   *string             `json:",omitempty"`
+	MinAmount  *money.Amount       `json:",omitempty"`
+	MaxAmount  *money.Amount       `json:",omitempty"`
+	Step       *money.Amount       `json:",omitempty"`
+	Coupons    int
+	Multiplier float64
+	SellerID   *int `json:",omitempty"`
+	CategoryID *int `json:",omitempty"`
+	Active     bool
+	StartAt    *time.Time `json:",omitempty"`
+	EndAt      *time.Time `json:",omitempty"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `json:"-"`
+
+	// Attribute
+	TypeRule string `gorm:"<-:false;-;"`
+}
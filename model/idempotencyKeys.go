@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// IdempotencyKeys StatusCode and Response empty while the first request still running
+type IdempotencyKeys struct {
+	ID          int
+	UserID      int
+	Key         string
+	Fingerprint string
+	StatusCode  *int
+	Response    []byte
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"pcstakehometest/enum"
+	"time"
+)
+
+type InventoryMovements struct {
+	ID            int
+	ProductID     int
+	SellerID      int `json:"-"`
+	TransactionID *int
+	Type          enum.InventoryMovementType `json:"-"`
+	Quantity      int
+	StockAfter    int
+	Note          string
+	CreatedAt     time.Time
+
+	// Attribute
+	TypeMovement string `gorm:"<-:false;-;"`
+}
+
+type StockReservations struct {
+	ID            int
+	TransactionID int
+	ProductID     int
+	Quantity      int
+	Status        enum.ReservationStatusType
+	ExpiredAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
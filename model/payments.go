@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+)
+
+type Payments struct {
+	ID                int
+	CheckoutID        int
+	BuyerID           int `json:"-"`
+	Amount            money.Amount
+	Currency          string
+	Provider          string
+	ProviderReference string
+	Status            enum.PaymentStatusType `json:"-"`
+	FailureReason     *string
+	PaidAt            *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// Attribute
+	StatusPayment string `gorm:"<-:false;-;"`
+	PaymentURL    string `json:",omitempty" gorm:"<-:false;-;"`
+}
+
+// Refunds money returned to buyer, PaymentID empty for order paid before payment gateway existed,
+// refund through the gateway stay pending until it is sent after the transaction recording it committed
+type Refunds struct {
+	ID                int
+	PaymentID         *int
+	TransactionID     int
+	ReturnID          *int
+	Amount            money.Amount
+	Currency          string
+	Provider          string
+	ProviderReference *string
+	Reason            *string
+	Status            enum.RefundStatusType `json:"-"`
+	FailureReason     *string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// Attribute
+	StatusRefund string `gorm:"<-:false;-;"`
+}
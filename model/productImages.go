@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProductImages struct {
+	ID           int
+	ProductID    int
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	URL          string
+	ThumbnailURL string
+	ContentType  string
+	Size         int64
+	Width        int
+	Height       int
+	Position     int
+	IsPrimary    bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `json:"-"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"pcstakehometest/enum"
+	"time"
+
+	"pcstakehometest/static"
+)
+
+type ProductImportJobs struct {
+	ID          int
+	SellerID    int                      `json:"-"`
+	Status      enum.ImportJobStatusType `json:"-"`
+	DryRun      bool
+	TotalRows   int
+	CreatedRows int
+	UpdatedRows int
+	FailedRows  int
+	Errors      ImportErrors
+	FinishedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Attribute
+	StatusJob string `gorm:"<-:false;-;"`
+}
+
+type ImportErrors []ImportRowError
+
+type ImportRowError struct {
+	Row   int
+	SKU   string
+	Error string
+}
+
+func (j ImportErrors) Value() (driver.Value, error) {
+	if j == nil {
+		j = ImportErrors{}
+	}
+	return json.Marshal(j)
+}
+
+func (j *ImportErrors) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf(static.SomethingWrong)
+	}
+
+	result := ImportErrors{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
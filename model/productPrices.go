@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+type ProductPriceHistories struct {
+	ID        int
+	ProductID int
+	Price     money.Amount
+	Currency  string
+	ChangedBy int `json:"-"`
+	CreatedAt time.Time
+}
+
+type ProductSales struct {
+	ID        int
+	ProductID int
+	SellerID  int `json:"-"`
+	SalePrice money.Amount
+	StartAt   time.Time
+	EndAt     time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
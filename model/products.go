@@ -3,19 +3,56 @@ package model
 import (
 	"time"
 
+	"pcstakehometest/enum"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/money"
+
 	"gorm.io/gorm"
 )
 
 type Products struct {
-	ID          int
-	Name        string
-	Description string
-	Price       float64
-	SellerID    int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `json:"-"`
+	ID            int
+	SKU           string
+	Name          string
+	Description   string
+	Price         money.Amount
+	Currency      string
+	Stock         int
+	SellerID      int
+	CategoryID    *int
+	Attributes    attribute.Values
+	RatingAverage float64
+	RatingCount   int
+	Status        enum.ProductStatusType `json:"-"`
+	PublishAt     *time.Time
+	PublishedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
 
 	// Relations
-	Seller *Users `json:",omitempty" gorm:"<-:false;foreignKey:SellerID;references:ID;"`
+	Seller   *Users          `json:",omitempty" gorm:"<-:false;foreignKey:SellerID;references:ID;"`
+	Category *Categories     `json:",omitempty" gorm:"<-:false;foreignKey:CategoryID;references:ID;"`
+	Images   []ProductImages `json:",omitempty" gorm:"<-:false;foreignKey:ProductID;references:ID;"`
+
+	// Attribute
+	SalePrice       *money.Amount `json:",omitempty" gorm:"<-:false;-;"`
+	SaleEndAt       *time.Time    `json:",omitempty" gorm:"<-:false;-;"`
+	DisplayPrice    *money.Amount `json:",omitempty" gorm:"<-:false;-;"`
+	DisplayCurrency string        `json:",omitempty" gorm:"<-:false;-;"`
+	StatusProduct   string        `gorm:"<-:false;-;"`
+}
+
+// EffectivePrice price buyer pay at the moment, sale price when a running sale is cheaper
+func (p *Products) EffectivePrice() money.Amount {
+	if p.SalePrice != nil && p.SalePrice.Cmp(p.Price) < 0 {
+		return *p.SalePrice
+	}
+	return p.Price
+}
+
+// ProductFrequency how often a product bought together with another product
+type ProductFrequency struct {
+	ProductID int
+	Frequency int
 }
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+type Returns struct {
+	ID             int
+	TransactionID  int
+	BuyerID        int `json:"-"`
+	SellerID       int `json:"-"`
+	Reason         string
+	Items          ItemsReturn
+	Status         enum.ReturnStatusType `json:"-"`
+	RejectReason   *string
+	Courier        *string
+	TrackingNumber *string
+	RefundAmount   money.Amount
+	Currency       string
+	RefundID       *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// Relations
+	Refund    *Refunds           `json:",omitempty" gorm:"<-:false;foreignKey:RefundID;references:ID;"`
+	Histories []*ReturnHistories `json:",omitempty" gorm:"<-:false;foreignKey:ReturnID;references:ID;"`
+
+	// Attribute
+	StatusReturn string `gorm:"<-:false;-;"`
+}
+
+type ItemsReturn []ProductReturn
+
+// ProductReturn returned quantity of one line of the transaction snapshot, LineTotal after its share of order discount
+type ProductReturn struct {
+	ID        int
+	Name      string
+	Price     money.Amount
+	Quantity  int
+	LineTotal money.Amount
+}
+
+func (j ItemsReturn) Value() (driver.Value, error) {
+	return json.Marshal(j)
+}
+
+func (j *ItemsReturn) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf(static.SomethingWrong)
+	}
+
+	result := ItemsReturn{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+type ReturnHistories struct {
+	ID         int
+	ReturnID   int
+	FromStatus *enum.ReturnStatusType `json:"-"`
+	ToStatus   enum.ReturnStatusType  `json:"-"`
+	ActorID    *int
+	ActorRole  *enum.RoleType `json:"-"`
+	Note       *string
+	CreatedAt  time.Time
+
+	// Attribute
+	From string `json:",omitempty" gorm:"<-:false;-;"`
+	To   string `gorm:"<-:false;-;"`
+	Role string `json:",omitempty" gorm:"<-:false;-;"`
+}
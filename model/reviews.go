@@ -0,0 +1,91 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"pcstakehometest/enum"
+	"time"
+
+	"gorm.io/gorm"
+	"pcstakehometest/static"
+)
+
+type Reviews struct {
+	ID            int
+	ProductID     int
+	SellerID      int
+	BuyerID       int
+	TransactionID int `json:"-"`
+	Rating        int
+	Content       string
+	Photos        ReviewPhotos
+	Reply         *string
+	RepliedAt     *time.Time
+	Status        enum.ReviewStatusType `json:"-"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
+
+	// Relations
+	Buyer *Users `json:",omitempty" gorm:"<-:false;foreignKey:BuyerID;references:ID;"`
+
+	// Attribute
+	StatusReview string `gorm:"<-:false;-;"`
+}
+
+type ReviewPhotos []ReviewPhoto
+
+type ReviewPhoto struct {
+	StorageKey   string
+	ThumbnailKey string
+	URL          string
+	ThumbnailURL string
+}
+
+func (j ReviewPhotos) Value() (driver.Value, error) {
+	if j == nil {
+		j = ReviewPhotos{}
+	}
+	return json.Marshal(j)
+}
+
+func (j *ReviewPhotos) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf(static.SomethingWrong)
+	}
+
+	result := ReviewPhotos{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+type ReviewReports struct {
+	ID         int
+	ReviewID   int
+	ReporterID int
+	Reason     string
+	Status     enum.ReviewReportStatusType `json:"-"`
+	Note       *string
+	ResolvedBy *int
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Relations
+	Review *Reviews `json:",omitempty" gorm:"<-:false;foreignKey:ReviewID;references:ID;"`
+
+	// Attribute
+	StatusReport string `gorm:"<-:false;-;"`
+}
+
+type RatingDistribution struct {
+	Rating int
+	Total  int
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/enum"
+)
+
+// TrackingEvents checkpoint of a shipped transaction reported by carrier
+type TrackingEvents struct {
+	ID             int
+	TransactionID  int
+	Carrier        string
+	TrackingNumber string
+	Status         enum.TrackingStatusType `json:"-"`
+	Description    string
+	Location       *string `json:",omitempty"`
+	OccurredAt     time.Time
+	CreatedAt      time.Time
+
+	// Attribute
+	StatusTracking string `gorm:"<-:false;-;"`
+}
@@ -8,22 +8,45 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/money"
 	"pcstakehometest/static"
 )
 
 type Transactions struct {
-	ID          int
-	BuyerID     int `json:"-"`
-	SellerID    int `json:"-"`
-	Origin      string
-	Destination string
-	Items       ItemsTransaction
-	GrandTotal  float64
-	Status      enum.TransactionStatusType `json:"-"`
-	Coupons     int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `json:"-"`
+	ID                int
+	CheckoutID        *int
+	BuyerID           int `json:"-"`
+	SellerID          int `json:"-"`
+	Origin            string
+	Destination       string
+	Items             ItemsTransaction
+	Subtotal          money.Amount
+	ShippingCarrier   string
+	ShippingService   string
+	ShippingWeight    int
+	ShippingFee       money.Amount
+	TrackingNumber    string
+	GrandTotal        money.Amount
+	Currency          string
+	Status            enum.TransactionStatusType `json:"-"`
+	StatusReason      *string                    `json:",omitempty"`
+	Coupons           int
+	CouponsEarned     *int `json:",omitempty"`
+	CouponsRedeemed   int
+	CouponDiscount    money.Amount
+	VoucherID         *int `json:",omitempty"`
+	VoucherCode       string
+	VoucherDiscount   money.Amount
+	PaidAt            *time.Time
+	RefundedAmount    money.Amount
+	DisplayCurrency   string
+	DisplayGrandTotal money.Amount
+	ExchangeRate      money.Rate
+	RateAt            *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `json:"-"`
 
 	// Relations
 	Seller *Users `json:",omitempty" gorm:"<-:false;foreignKey:SellerID;references:ID;"`
@@ -36,10 +59,15 @@ type Transactions struct {
 type ItemsTransaction []ProductTransaction
 
 type ProductTransaction struct {
-	ID          int
-	Name        string
-	Description string
-	Price       float64
+	ID            int
+	Name          string
+	Description   string
+	Price         money.Amount
+	Quantity      int
+	LineTotal     money.Amount
+	OriginalPrice *money.Amount    `json:",omitempty"`
+	CategoryID    *int             `json:",omitempty"`
+	Attributes    []attribute.Item `json:",omitempty"`
 }
 
 func (j ItemsTransaction) Value() (driver.Value, error) {
@@ -57,7 +85,31 @@ func (j *ItemsTransaction) Scan(value interface{}) error {
 		return err
 	}
 
+	// Item ordered before quantity stored always has one unit
+	for i := range result {
+		if result[i].Quantity == 0 {
+			result[i].Quantity = 1
+			result[i].LineTotal = result[i].Price
+		}
+	}
+
 	*j = result
 
 	return nil
 }
+
+type TransactionStatusHistories struct {
+	ID            int
+	TransactionID int
+	FromStatus    *enum.TransactionStatusType `json:"-"`
+	ToStatus      enum.TransactionStatusType  `json:"-"`
+	ActorID       *int
+	ActorRole     *enum.RoleType `json:"-"`
+	Reason        *string
+	CreatedAt     time.Time
+
+	// Attribute
+	From string `json:",omitempty" gorm:"<-:false;-;"`
+	To   string `gorm:"<-:false;-;"`
+	Role string `json:",omitempty" gorm:"<-:false;-;"`
+}
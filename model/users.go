@@ -8,11 +8,14 @@ import (
 )
 
 type Users struct {
-	ID        int
-	Username  string
-	Password  string        `json:"-"`
-	Role      enum.RoleType `json:"-"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `json:"-"`
+	ID            int
+	Username      string
+	Password      string        `json:"-"`
+	Role          enum.RoleType `json:"-"`
+	RatingAverage float64
+	RatingCount   int
+	StoreClosedAt *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
 }
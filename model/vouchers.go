@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+// Vouchers discount code, seller funded when SellerID set otherwise platform wide, amount in Currency and Value percent for percentage type
+type Vouchers struct {
+	ID            int
+	Code          string
+	Name          string
+	SellerID      *int             `json:",omitempty"`
+	Type          enum.VoucherType `json:"-"`
+	Currency      string
+	Value         money.Amount
+	MinSpend      *money.Amount `json:",omitempty"`
+	MaxDiscount   *money.Amount `json:",omitempty"`
+	UsageLimit    *int          `json:",omitempty"`
+	PerBuyerLimit *int          `json:",omitempty"`
+	Used          int
+	Active        bool
+	StartAt       *time.Time `json:",omitempty"`
+	EndAt         *time.Time `json:",omitempty"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
+
+	// Attribute
+	TypeVoucher string `gorm:"<-:false;-;"`
+}
+
+// VoucherRedemptions voucher used by one transaction, released usage no longer counted toward the caps
+type VoucherRedemptions struct {
+	ID            int
+	VoucherID     int
+	BuyerID       int
+	TransactionID int
+	Discount      money.Amount
+	Currency      string
+	ReleasedAt    *time.Time
+	CreatedAt     time.Time
+}
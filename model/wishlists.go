@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"pcstakehometest/package/money"
+
+	"gorm.io/gorm"
+)
+
+type Wishlists struct {
+	ID            int
+	BuyerID       int `json:"-"`
+	ProductID     int
+	PriceSnapshot money.Amount
+	Currency      string
+	NotifiedPrice *money.Amount `json:"-"`
+	NotifiedAt    *time.Time    `json:"-"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `json:"-"`
+
+	// Relations
+	Product *Products `json:",omitempty" gorm:"<-:false;foreignKey:ProductID;references:ID;"`
+
+	// Attribute
+	Available      bool `gorm:"<-:false;-;"`
+	ProductDeleted bool `gorm:"<-:false;-;"`
+	StoreClosed    bool `gorm:"<-:false;-;"`
+	PriceDropped   bool `gorm:"<-:false;-;"`
+}
@@ -3,19 +3,62 @@ package module
 import (
 	//Route
 	authRoute "pcstakehometest/module/auth/route"
+	cartRoute "pcstakehometest/module/cart/route"
+	categoryRoute "pcstakehometest/module/category/route"
+	couponRoute "pcstakehometest/module/coupon/route"
+	imageRoute "pcstakehometest/module/image/route"
+	inventoryRoute "pcstakehometest/module/inventory/route"
+	paymentRoute "pcstakehometest/module/payment/route"
 	productRoute "pcstakehometest/module/product/route"
+	reviewRoute "pcstakehometest/module/review/route"
+	rmaRoute "pcstakehometest/module/rma/route"
+	shippingRoute "pcstakehometest/module/shipping/route"
 	transactionRoute "pcstakehometest/module/transaction/route"
+	userRoute "pcstakehometest/module/user/route"
+	voucherRoute "pcstakehometest/module/voucher/route"
+	wishlistRoute "pcstakehometest/module/wishlist/route"
 
 	//Logic
 	authLogic "pcstakehometest/module/auth/logic"
+	cartLogic "pcstakehometest/module/cart/logic"
+	categoryLogic "pcstakehometest/module/category/logic"
+	couponLogic "pcstakehometest/module/coupon/logic"
+	idempotencyLogic "pcstakehometest/module/idempotency/logic"
+	imageLogic "pcstakehometest/module/image/logic"
+	inventoryLogic "pcstakehometest/module/inventory/logic"
+	paymentLogic "pcstakehometest/module/payment/logic"
 	productLogic "pcstakehometest/module/product/logic"
+	refundLogic "pcstakehometest/module/refund/logic"
+	reviewLogic "pcstakehometest/module/review/logic"
+	rmaLogic "pcstakehometest/module/rma/logic"
+	shippingLogic "pcstakehometest/module/shipping/logic"
 	transactionLogic "pcstakehometest/module/transaction/logic"
 	userLogic "pcstakehometest/module/user/logic"
+	voucherLogic "pcstakehometest/module/voucher/logic"
+	wishlistLogic "pcstakehometest/module/wishlist/logic"
 
 	//Repository
+	cartRepository "pcstakehometest/module/cart/repository"
+	categoryRepository "pcstakehometest/module/category/repository"
+	couponRepository "pcstakehometest/module/coupon/repository"
+	idempotencyRepository "pcstakehometest/module/idempotency/repository"
+	imageRepository "pcstakehometest/module/image/repository"
+	inventoryRepository "pcstakehometest/module/inventory/repository"
+	paymentRepository "pcstakehometest/module/payment/repository"
 	productRepository "pcstakehometest/module/product/repository"
+	reviewRepository "pcstakehometest/module/review/repository"
+	rmaRepository "pcstakehometest/module/rma/repository"
 	transactionRepository "pcstakehometest/module/transaction/repository"
 	userRepository "pcstakehometest/module/user/repository"
+	voucherRepository "pcstakehometest/module/voucher/repository"
+	wishlistRepository "pcstakehometest/module/wishlist/repository"
+
+	//Worker
+	couponWorker "pcstakehometest/module/coupon/worker"
+	idempotencyWorker "pcstakehometest/module/idempotency/worker"
+	productWorker "pcstakehometest/module/product/worker"
+	refundWorker "pcstakehometest/module/refund/worker"
+	transactionWorker "pcstakehometest/module/transaction/worker"
 
 	"go.uber.org/fx"
 )
@@ -25,6 +68,18 @@ var BundleRoute = fx.Options(
 	fx.Invoke(transactionRoute.NewRoute),
 	fx.Invoke(productRoute.NewRoute),
 	fx.Invoke(authRoute.NewRoute),
+	fx.Invoke(inventoryRoute.NewRoute),
+	fx.Invoke(imageRoute.NewRoute),
+	fx.Invoke(reviewRoute.NewRoute),
+	fx.Invoke(userRoute.NewRoute),
+	fx.Invoke(wishlistRoute.NewRoute),
+	fx.Invoke(categoryRoute.NewRoute),
+	fx.Invoke(cartRoute.NewRoute),
+	fx.Invoke(paymentRoute.NewRoute),
+	fx.Invoke(rmaRoute.NewRoute),
+	fx.Invoke(shippingRoute.NewRoute),
+	fx.Invoke(couponRoute.NewRoute),
+	fx.Invoke(voucherRoute.NewRoute),
 )
 
 // Register logic
@@ -33,6 +88,19 @@ var BundleLogic = fx.Options(
 	fx.Provide(transactionLogic.NewLogic),
 	fx.Provide(productLogic.NewLogic),
 	fx.Provide(authLogic.NewLogic),
+	fx.Provide(inventoryLogic.NewLogic),
+	fx.Provide(imageLogic.NewLogic),
+	fx.Provide(reviewLogic.NewLogic),
+	fx.Provide(wishlistLogic.NewLogic),
+	fx.Provide(categoryLogic.NewLogic),
+	fx.Provide(cartLogic.NewLogic),
+	fx.Provide(idempotencyLogic.NewLogic),
+	fx.Provide(paymentLogic.NewLogic),
+	fx.Provide(rmaLogic.NewLogic),
+	fx.Provide(shippingLogic.NewLogic),
+	fx.Provide(couponLogic.NewLogic),
+	fx.Provide(voucherLogic.NewLogic),
+	fx.Provide(refundLogic.NewLogic),
 )
 
 // Register Repository
@@ -40,4 +108,24 @@ var BundleRepository = fx.Options(
 	fx.Provide(userRepository.NewRepository),
 	fx.Provide(transactionRepository.NewRepository),
 	fx.Provide(productRepository.NewRepository),
+	fx.Provide(inventoryRepository.NewRepository),
+	fx.Provide(imageRepository.NewRepository),
+	fx.Provide(reviewRepository.NewRepository),
+	fx.Provide(wishlistRepository.NewRepository),
+	fx.Provide(categoryRepository.NewRepository),
+	fx.Provide(cartRepository.NewRepository),
+	fx.Provide(idempotencyRepository.NewRepository),
+	fx.Provide(paymentRepository.NewRepository),
+	fx.Provide(rmaRepository.NewRepository),
+	fx.Provide(couponRepository.NewRepository),
+	fx.Provide(voucherRepository.NewRepository),
+)
+
+// Register Worker
+var BundleWorker = fx.Options(
+	fx.Invoke(productWorker.NewWorker),
+	fx.Invoke(idempotencyWorker.NewWorker),
+	fx.Invoke(transactionWorker.NewWorker),
+	fx.Invoke(couponWorker.NewWorker),
+	fx.Invoke(refundWorker.NewWorker),
 )
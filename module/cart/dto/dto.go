@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	transactionDto "pcstakehometest/module/transaction/dto"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+type CreateRequest struct {
+	ProductID int
+	Quantity  int
+	BuyerID   int
+	RoleID    enum.RoleType
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.Quantity <= 0 {
+		return fmt.Errorf(static.MinValue, "Quantity", 0)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	ID       int
+	Quantity int
+	BuyerID  int
+	RoleID   enum.RoleType
+}
+
+func (d *UpdateRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.Quantity <= 0 {
+		return fmt.Errorf(static.MinValue, "Quantity", 0)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type CartRequest struct {
+	ID      int
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *CartRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type CartResponse struct {
+	Items    []*model.Carts
+	Totals   map[string]money.Amount
+	Warnings []string
+}
+
+type CheckoutRequest struct {
+	SellerID           int
+	Currency           string
+	ConfirmPriceChange bool
+	Destination        string
+	Shipping           []transactionDto.ShippingOption
+	Vouchers           []transactionDto.VoucherOption
+	BuyerID            int
+	RoleID             enum.RoleType
+}
+
+func (d *CheckoutRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
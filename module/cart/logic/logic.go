@@ -0,0 +1,357 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/cart/dto"
+	"pcstakehometest/module/cart/repository"
+	productDto "pcstakehometest/module/product/dto"
+	productLogic "pcstakehometest/module/product/logic"
+	transactionDto "pcstakehometest/module/transaction/dto"
+	transactionLogic "pcstakehometest/module/transaction/logic"
+	userDto "pcstakehometest/module/user/dto"
+	userLogic "pcstakehometest/module/user/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// CartLogic
+type ICartLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Carts, error)
+	FindAll(context.Context, *dto.CartRequest) (*dto.CartResponse, error)
+	Update(context.Context, *dto.UpdateRequest, *gorm.DB) (*model.Carts, error)
+	Delete(context.Context, *dto.CartRequest, *gorm.DB) error
+	Clear(context.Context, *dto.CartRequest, *gorm.DB) error
+	Checkout(context.Context, *dto.CheckoutRequest, *gorm.DB) (*transactionDto.CheckoutResponse, error)
+}
+
+type CartLogic struct {
+	fx.In
+	Logger           *logger.LogRus
+	CartRepo         repository.ICartRepository
+	ProductLogic     productLogic.IProductLogic
+	UserLogic        userLogic.IUserLogic
+	TransactionLogic transactionLogic.ITransactionLogic
+}
+
+// NewLogic :
+func NewLogic(cartLogic CartLogic) ICartLogic {
+	return &cartLogic
+}
+
+// Create add product into cart, quantity added up when product already in cart
+func (l *CartLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Carts, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.findProduct(ctx, reqData.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := l.CartRepo.Find(ctx, &model.Carts{
+		BuyerID:   reqData.BuyerID,
+		ProductID: product.ID,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		cart = &model.Carts{
+			BuyerID:   reqData.BuyerID,
+			ProductID: product.ID,
+		}
+	}
+
+	cart.Quantity += reqData.Quantity
+	if product.Stock < cart.Quantity {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.OutOfStock, product.Name), http.StatusConflict)
+	}
+
+	cart.PriceSnapshot = product.EffectivePrice()
+	cart.Currency = product.Currency
+
+	if cart.ID == 0 {
+		_, err = l.CartRepo.Create(ctx, cart, tx)
+	} else {
+		err = l.CartRepo.Update(ctx, cart, tx)
+	}
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	cart.Product = product
+	revalidate(cart)
+
+	return cart, nil
+}
+
+// FindAll revalidate price and availability of every item against current product
+func (l *CartLogic) FindAll(ctx context.Context, reqData *dto.CartRequest) (*dto.CartResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	carts, err := l.findAll(ctx, reqData.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CartResponse{
+		Items:    carts,
+		Totals:   map[string]money.Amount{},
+		Warnings: []string{},
+	}
+	for _, cart := range carts {
+		if cart.Warning != "" {
+			response.Warnings = append(response.Warnings, cart.Warning)
+		}
+		if cart.Available {
+			response.Totals[cart.Currency] = response.Totals[cart.Currency].Add(cart.LineTotal)
+		}
+	}
+
+	return response, nil
+}
+
+// Update change quantity and refresh price snapshot
+func (l *CartLogic) Update(ctx context.Context, reqData *dto.UpdateRequest, tx *gorm.DB) (*model.Carts, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	cart, err := l.find(ctx, reqData.ID, reqData.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := l.findProduct(ctx, cart.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Stock < reqData.Quantity {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.OutOfStock, product.Name), http.StatusConflict)
+	}
+
+	cart.Quantity = reqData.Quantity
+	cart.PriceSnapshot = product.EffectivePrice()
+	cart.Currency = product.Currency
+	if err := l.CartRepo.Update(ctx, cart, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	cart.Product = product
+	revalidate(cart)
+
+	return cart, nil
+}
+
+// Delete
+func (l *CartLogic) Delete(ctx context.Context, reqData *dto.CartRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	cart, err := l.find(ctx, reqData.ID, reqData.BuyerID)
+	if err != nil {
+		return err
+	}
+
+	if err := l.CartRepo.Delete(ctx, cart, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Clear remove every item in buyer cart
+func (l *CartLogic) Clear(ctx context.Context, reqData *dto.CartRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if err := l.CartRepo.Delete(ctx, &model.Carts{
+		BuyerID: reqData.BuyerID,
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Checkout order cart items, every seller get its own transaction, SellerID limit checkout to one seller
+func (l *CartLogic) Checkout(ctx context.Context, reqData *dto.CheckoutRequest, tx *gorm.DB) (*transactionDto.CheckoutResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	carts, err := l.findAll(ctx, reqData.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		items   []transactionDto.OrderItem
+		cartIDs []int
+	)
+	for _, cart := range carts {
+		if reqData.SellerID != 0 && (cart.Product == nil || cart.Product.SellerID != reqData.SellerID) {
+			continue
+		}
+		if !cart.Available {
+			return nil, utilities.ErrorRequest(errors.New(cart.Warning), http.StatusBadRequest)
+		}
+		if cart.PriceChanged && !reqData.ConfirmPriceChange {
+			return nil, utilities.ErrorRequest(errors.New(static.CartConfirmPrice), http.StatusBadRequest)
+		}
+
+		items = append(items, transactionDto.OrderItem{
+			ProductID: cart.ProductID,
+			Quantity:  cart.Quantity,
+		})
+		cartIDs = append(cartIDs, cart.ID)
+	}
+
+	if len(items) == 0 {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.EmptyValue, "keranjang"), http.StatusBadRequest)
+	}
+
+	response, err := l.TransactionLogic.Checkout(ctx, &transactionDto.CheckoutRequest{
+		BuyerID:     reqData.BuyerID,
+		Items:       items,
+		RoleID:      reqData.RoleID,
+		Currency:    reqData.Currency,
+		Destination: reqData.Destination,
+		Shipping:    reqData.Shipping,
+		Vouchers:    reqData.Vouchers,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	if err := l.CartRepo.DeleteByIDs(ctx, reqData.BuyerID, cartIDs, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return response, nil
+}
+
+// findAll load buyer cart with running sale applied then revalidate every item
+func (l *CartLogic) findAll(ctx context.Context, buyerID int) ([]*model.Carts, error) {
+	carts, err := l.CartRepo.FindAll(ctx, &model.Carts{
+		BuyerID: buyerID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	products := []*model.Products{}
+	for _, cart := range carts {
+		if cart.Product != nil {
+			products = append(products, cart.Product)
+		}
+	}
+
+	if err := l.ProductLogic.ApplySale(ctx, products...); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	for _, cart := range carts {
+		revalidate(cart)
+	}
+
+	return carts, nil
+}
+
+func (l *CartLogic) find(ctx context.Context, id int, buyerID int) (*model.Carts, error) {
+	cart, err := l.CartRepo.Find(ctx, &model.Carts{
+		ID:      id,
+		BuyerID: buyerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "keranjang"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return cart, nil
+}
+
+// findProduct only published product from open store can be added into cart
+func (l *CartLogic) findProduct(ctx context.Context, productID int) (*model.Products, error) {
+	product, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+		ID:     productID,
+		Status: enum.ProductStatusTypePublished,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := l.UserLogic.Find(ctx, &userDto.FindRequest{
+		ID: product.SellerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if seller.StoreClosedAt != nil {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.StoreClosed, seller.Username), http.StatusBadRequest)
+	}
+
+	return product, nil
+}
+
+// revalidate compare cart item with current product price, status, store and stock
+func revalidate(cart *model.Carts) {
+	product := cart.Product
+	if product == nil {
+		cart.Warning = fmt.Sprintf(static.CartUnavailable, cart.ProductID)
+		return
+	}
+
+	cart.Price = product.EffectivePrice()
+	cart.LineTotal = cart.Price.Mul(int64(cart.Quantity))
+	cart.PriceChanged = product.Currency != cart.Currency || cart.Price.Cmp(cart.PriceSnapshot) != 0
+
+	switch {
+	case product.DeletedAt.Valid || product.Status != enum.ProductStatusTypePublished:
+		cart.Warning = fmt.Sprintf(static.CartUnavailable, product.Name)
+	case product.Seller != nil && product.Seller.StoreClosedAt != nil:
+		cart.Warning = fmt.Sprintf(static.StoreClosed, product.Seller.Username)
+	case product.Stock < cart.Quantity:
+		cart.Warning = fmt.Sprintf(static.OutOfStock, product.Name)
+	case cart.PriceChanged:
+		cart.Available = true
+		cart.Warning = fmt.Sprintf(static.CartPriceChanged, product.Name, cart.PriceSnapshot, cart.Price, product.Currency)
+	default:
+		cart.Available = true
+	}
+}
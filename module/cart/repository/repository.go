@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// CartRepository
+type ICartRepository interface {
+	Create(context.Context, *model.Carts, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.Carts) ([]*model.Carts, error)
+	Find(context.Context, *model.Carts) (*model.Carts, error)
+	Update(context.Context, *model.Carts, *gorm.DB) error
+	Delete(context.Context, *model.Carts, *gorm.DB) error
+	DeleteByIDs(context.Context, int, []int, *gorm.DB) error
+}
+
+type CartRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(cartRepository CartRepository) ICartRepository {
+	return &cartRepository
+}
+
+// Create
+func (l *CartRepository) Create(ctx context.Context, reqData *model.Carts, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll include deleted product so buyer can see it is no longer available
+func (l *CartRepository) FindAll(ctx context.Context, reqData *model.Carts) ([]*model.Carts, error) {
+	carts := []*model.Carts{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Carts{}).
+		Preload("Product", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Product.Seller").
+		Where(&model.Carts{
+			BuyerID: reqData.BuyerID,
+		}).
+		Order("id asc").
+		Find(&carts).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return carts, nil
+}
+
+// Find
+func (l *CartRepository) Find(ctx context.Context, reqData *model.Carts) (*model.Carts, error) {
+	cart := new(model.Carts)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Carts{
+			ID:        reqData.ID,
+			BuyerID:   reqData.BuyerID,
+			ProductID: reqData.ProductID,
+		}).First(&cart).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return cart, nil
+}
+
+// Update quantity along with refreshed price snapshot
+func (l *CartRepository) Update(ctx context.Context, reqData *model.Carts, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Carts{}).
+		Where("id = ?", reqData.ID).
+		Where("buyer_id = ?", reqData.BuyerID).
+		Updates(map[string]interface{}{
+			"quantity":       reqData.Quantity,
+			"price_snapshot": reqData.PriceSnapshot,
+			"currency":       reqData.Currency,
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Delete remove one item, or every item of buyer when ID is empty
+func (l *CartRepository) Delete(ctx context.Context, reqData *model.Carts, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where(&model.Carts{
+			ID:      reqData.ID,
+			BuyerID: reqData.BuyerID,
+		}).
+		Delete(&model.Carts{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteByIDs
+func (l *CartRepository) DeleteByIDs(ctx context.Context, buyerID int, ids []int, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("buyer_id = ?", buyerID).
+		Where("id in ?", ids).
+		Delete(&model.Carts{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/cart/dto"
+	"pcstakehometest/module/cart/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.ICartLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	cart := h.EchoRoute.Group("/v1/cart", m...)
+	cart.POST("", h.Create, h.EchoRoute.Authentication)
+	cart.GET("", h.FindAll, h.EchoRoute.Authentication)
+	cart.DELETE("", h.Clear, h.EchoRoute.Authentication)
+	cart.POST("/checkout", h.Checkout, h.EchoRoute.Authentication, h.EchoRoute.Idempotency)
+	cart.PUT("/:id", h.Update, h.EchoRoute.Authentication)
+	cart.DELETE("/:id", h.Delete, h.EchoRoute.Authentication)
+}
+
+// Create
+func (h *Handler) Create(c echo.Context) error {
+	var reqData = new(dto.CreateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+	if reqData.Quantity == 0 {
+		reqData.Quantity = 1
+	}
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Create(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindAll
+func (h *Handler) FindAll(c echo.Context) error {
+	var reqData = new(dto.CartRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindAll(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Update
+func (h *Handler) Update(c echo.Context) error {
+	var reqData = new(dto.UpdateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Update(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Delete
+func (h *Handler) Delete(c echo.Context) error {
+	var reqData = new(dto.CartRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Delete(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// Clear
+func (h *Handler) Clear(c echo.Context) error {
+	var reqData = new(dto.CartRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Clear(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// Checkout
+func (h *Handler) Checkout(c echo.Context) error {
+	var reqData = new(dto.CheckoutRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Checkout(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/static"
+)
+
+type CreateRequest struct {
+	Name       string
+	Attributes attribute.Schema
+	RoleID     enum.RoleType
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf(static.EmptyValue, "Name")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	ID         int
+	Name       string
+	Attributes attribute.Schema
+	RoleID     enum.RoleType
+}
+
+func (d *UpdateRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.Name == "" {
+		return fmt.Errorf(static.EmptyValue, "Name")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindRequest struct {
+	ID int
+}
+
+type AttributeRequest struct {
+	CategoryID int
+	Values     attribute.Values
+}
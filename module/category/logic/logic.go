@@ -0,0 +1,186 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pcstakehometest/model"
+	"pcstakehometest/module/category/dto"
+	"pcstakehometest/module/category/repository"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// CategoryLogic
+type ICategoryLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Categories, error)
+	FindAll(context.Context) ([]*model.Categories, error)
+	Find(context.Context, *dto.FindRequest) (*model.Categories, error)
+	Update(context.Context, *dto.UpdateRequest, *gorm.DB) (*model.Categories, error)
+	ValidateAttributes(context.Context, *dto.AttributeRequest) (attribute.Values, error)
+}
+
+type CategoryLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	CategoryRepo repository.ICategoryRepository
+}
+
+// NewLogic :
+func NewLogic(categoryLogic CategoryLogic) ICategoryLogic {
+	return &categoryLogic
+}
+
+// Create
+func (l *CategoryLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Categories, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if err := reqData.Attributes.Check(); err != nil {
+		l.Logger.Error(err)
+		return nil, attributeError(err)
+	}
+
+	name := strings.TrimSpace(reqData.Name)
+	if err := l.unique(ctx, name, 0); err != nil {
+		return nil, err
+	}
+
+	category := &model.Categories{
+		Name:       name,
+		Attributes: reqData.Attributes,
+	}
+	if _, err := l.CategoryRepo.Create(ctx, category, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return category, nil
+}
+
+// FindAll
+func (l *CategoryLogic) FindAll(ctx context.Context) ([]*model.Categories, error) {
+	categories, err := l.CategoryRepo.FindAll(ctx)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return categories, nil
+}
+
+// Find
+func (l *CategoryLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.Categories, error) {
+	category, err := l.CategoryRepo.Find(ctx, &model.Categories{
+		ID: reqData.ID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "kategori"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return category, nil
+}
+
+// Update replace category schema, existing product attributes validated again on their next update
+func (l *CategoryLogic) Update(ctx context.Context, reqData *dto.UpdateRequest, tx *gorm.DB) (*model.Categories, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if err := reqData.Attributes.Check(); err != nil {
+		l.Logger.Error(err)
+		return nil, attributeError(err)
+	}
+
+	category, err := l.Find(ctx, &dto.FindRequest{
+		ID: reqData.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(reqData.Name)
+	if err := l.unique(ctx, name, category.ID); err != nil {
+		return nil, err
+	}
+
+	category.Name = name
+	category.Attributes = reqData.Attributes
+	if err := l.CategoryRepo.Update(ctx, category, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return category, nil
+}
+
+// ValidateAttributes check product attributes against its category schema and return normalized values
+func (l *CategoryLogic) ValidateAttributes(ctx context.Context, reqData *dto.AttributeRequest) (attribute.Values, error) {
+	if reqData.CategoryID <= 0 {
+		if len(reqData.Values) > 0 {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.EmptyValue, "CategoryID"), http.StatusBadRequest)
+		}
+		return attribute.Values{}, nil
+	}
+
+	category, err := l.Find(ctx, &dto.FindRequest{
+		ID: reqData.CategoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := category.Attributes.Validate(reqData.Values)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, attributeError(err)
+	}
+
+	return values, nil
+}
+
+// unique category name must be unique ignoring case
+func (l *CategoryLogic) unique(ctx context.Context, name string, id int) error {
+	existing, err := l.CategoryRepo.FindByName(ctx, name)
+	if err == nil && existing.ID != id {
+		return utilities.ErrorRequest(fmt.Errorf(static.AlreadyExist, "Name"), http.StatusBadRequest)
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// attributeError translate attribute error into request error
+func attributeError(err error) error {
+	var attrErr *attribute.Error
+	if !errors.As(err, &attrErr) {
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	switch {
+	case errors.Is(err, attribute.ErrRequired):
+		err = fmt.Errorf(static.EmptyValue, attrErr.Key)
+	case errors.Is(err, attribute.ErrUnknown):
+		err = fmt.Errorf(static.UnknownAttribute, attrErr.Key)
+	case errors.Is(err, attribute.ErrInvalidSchema):
+		err = fmt.Errorf(static.InvalidAttributeSchema, attrErr.Key)
+	default:
+		err = fmt.Errorf(static.InvalidAttribute, attrErr.Key)
+	}
+
+	return utilities.ErrorRequest(err, http.StatusBadRequest)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// CategoryRepository
+type ICategoryRepository interface {
+	Create(context.Context, *model.Categories, *gorm.DB) (*int, error)
+	FindAll(context.Context) ([]*model.Categories, error)
+	Find(context.Context, *model.Categories) (*model.Categories, error)
+	FindByName(context.Context, string) (*model.Categories, error)
+	Update(context.Context, *model.Categories, *gorm.DB) error
+}
+
+type CategoryRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(categoryRepository CategoryRepository) ICategoryRepository {
+	return &categoryRepository
+}
+
+// Create
+func (l *CategoryRepository) Create(ctx context.Context, reqData *model.Categories, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll
+func (l *CategoryRepository) FindAll(ctx context.Context) ([]*model.Categories, error) {
+	categories := []*model.Categories{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Categories{}).
+		Order("name asc").
+		Find(&categories).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// Find
+func (l *CategoryRepository) Find(ctx context.Context, reqData *model.Categories) (*model.Categories, error) {
+	category := new(model.Categories)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Categories{
+			ID: reqData.ID,
+		}).First(&category).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return category, nil
+}
+
+// FindByName name is case insensitive
+func (l *CategoryRepository) FindByName(ctx context.Context, name string) (*model.Categories, error) {
+	category := new(model.Categories)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where("lower(name) = lower(?)", name).
+		First(&category).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return category, nil
+}
+
+// Update
+func (l *CategoryRepository) Update(ctx context.Context, reqData *model.Categories, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Categories{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"name":       reqData.Name,
+			"attributes": reqData.Attributes,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/category/dto"
+	"pcstakehometest/module/category/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.ICategoryLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	category := h.EchoRoute.Group("/v1/category", m...)
+	category.POST("", h.Create, h.EchoRoute.Authentication)
+	category.GET("", h.FindAll, h.EchoRoute.Authentication)
+	category.GET("/:id", h.Find, h.EchoRoute.Authentication)
+	category.PUT("/:id", h.Update, h.EchoRoute.Authentication)
+}
+
+// Create
+func (h *Handler) Create(c echo.Context) error {
+	var reqData = new(dto.CreateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Create(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindAll
+func (h *Handler) FindAll(c echo.Context) error {
+	_, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	resp, err := h.Logic.FindAll(c.Request().Context())
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Find
+func (h *Handler) Find(c echo.Context) error {
+	var reqData = new(dto.FindRequest)
+
+	_, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.Find(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Update
+func (h *Handler) Update(c echo.Context) error {
+	var reqData = new(dto.UpdateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Update(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
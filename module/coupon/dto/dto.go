@@ -0,0 +1,242 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/coupon"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+// Rule field of price band and threshold need Currency and Coupons, multiplier need Multiplier with SellerID or CategoryID
+type Rule struct {
+	Name       string
+	Type       enum.CouponRuleType
+	Currency   string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Step       *money.Amount
+	Coupons    int
+	Multiplier float64
+	SellerID   *int
+	CategoryID *int
+	Active     *bool
+	StartAt    *time.Time
+	EndAt      *time.Time
+}
+
+func (d *Rule) validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf(static.EmptyValue, "Name")
+	}
+	if err := d.Type.IsValid(); err != nil {
+		return err
+	}
+	if d.MinAmount != nil && d.MinAmount.IsNegative() {
+		return fmt.Errorf(static.NegativeValue, "MinAmount")
+	}
+	if d.MaxAmount != nil && d.MaxAmount.IsNegative() {
+		return fmt.Errorf(static.NegativeValue, "MaxAmount")
+	}
+
+	switch d.Type {
+	case enum.CouponRuleTypeItemPriceBand, enum.CouponRuleTypeTotalThreshold:
+		if strings.TrimSpace(d.Currency) == "" {
+			return fmt.Errorf(static.EmptyValue, "Currency")
+		}
+		if d.Coupons <= 0 {
+			return fmt.Errorf(static.MinValue, "Coupons", 0)
+		}
+	case enum.CouponRuleTypeSellerMultiplier, enum.CouponRuleTypeCategoryMultiplier:
+		if d.Multiplier <= 0 {
+			return fmt.Errorf(static.MinValue, "Multiplier", 0)
+		}
+	}
+
+	switch d.Type {
+	case enum.CouponRuleTypeItemPriceBand:
+		if d.MinAmount == nil && d.MaxAmount == nil {
+			return fmt.Errorf(static.EmptyValue, "MinAmount")
+		}
+		if d.MinAmount != nil && d.MaxAmount != nil && d.MinAmount.Cmp(*d.MaxAmount) >= 0 {
+			return errors.New(static.CouponBandInvalid)
+		}
+	case enum.CouponRuleTypeTotalThreshold:
+		if d.Step == nil || !d.Step.IsPositive() {
+			return fmt.Errorf(static.MinValue, "Step", 0)
+		}
+	case enum.CouponRuleTypeSellerMultiplier:
+		if d.SellerID == nil || *d.SellerID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "SellerID")
+		}
+	case enum.CouponRuleTypeCategoryMultiplier:
+		if d.CategoryID == nil || *d.CategoryID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "CategoryID")
+		}
+	}
+
+	if d.StartAt != nil && d.EndAt != nil && !d.EndAt.After(*d.StartAt) {
+		return errors.New(static.CouponPeriodInvalid)
+	}
+	return nil
+}
+
+type CreateRuleRequest struct {
+	Rule
+	RoleID enum.RoleType
+}
+
+func (d *CreateRuleRequest) Validate() error {
+	if err := d.Rule.validate(); err != nil {
+		return err
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type UpdateRuleRequest struct {
+	ID int
+	Rule
+	RoleID enum.RoleType
+}
+
+func (d *UpdateRuleRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if err := d.Rule.validate(); err != nil {
+		return err
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindRuleRequest struct {
+	ID     int
+	RoleID enum.RoleType
+}
+
+func (d *FindRuleRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindAllRuleRequest struct {
+	RoleID enum.RoleType
+}
+
+func (d *FindAllRuleRequest) Validate() error {
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// DryRunRequest cart split by seller like checkout, At default to now
+type DryRunRequest struct {
+	Items []DryRunItem
+	At    *time.Time
+}
+
+type DryRunItem struct {
+	ProductID int
+	Quantity  int
+}
+
+func (d *DryRunRequest) Validate() error {
+	if len(d.Items) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Items")
+	}
+	for _, item := range d.Items {
+		if item.ProductID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "ProductID")
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf(static.MinValue, "Quantity", 0)
+		}
+	}
+	return nil
+}
+
+type DryRunResponse struct {
+	Coupons int
+	Orders  []*DryRunOrder
+}
+
+type DryRunOrder struct {
+	SellerID int
+	Currency string
+	*coupon.Result
+}
+
+// LedgerRequest coupon mutation of buyer wallet caused by the transaction
+type LedgerRequest struct {
+	BuyerID       int
+	TransactionID int
+	Coupons       int
+}
+
+func (d *LedgerRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if d.Coupons <= 0 {
+		return fmt.Errorf(static.MinValue, "Coupons", 0)
+	}
+	return nil
+}
+
+type WalletRequest struct {
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *WalletRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// WalletResponse Value discount of one coupon in Currency
+type WalletResponse struct {
+	Balance  int
+	Value    money.Amount
+	Currency string
+	History  []*model.CouponLedgers
+}
@@ -0,0 +1,295 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/coupon/dto"
+	"pcstakehometest/module/coupon/repository"
+	productDto "pcstakehometest/module/product/dto"
+	productLogic "pcstakehometest/module/product/logic"
+	"pcstakehometest/package/coupon"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// CouponLogic
+type ICouponLogic interface {
+	CreateRule(context.Context, *dto.CreateRuleRequest, *gorm.DB) (*model.CouponRules, error)
+	FindAllRule(context.Context, *dto.FindAllRuleRequest) ([]*model.CouponRules, error)
+	FindRule(context.Context, *dto.FindRuleRequest) (*model.CouponRules, error)
+	UpdateRule(context.Context, *dto.UpdateRuleRequest, *gorm.DB) (*model.CouponRules, error)
+	DeleteRule(context.Context, *dto.FindRuleRequest, *gorm.DB) error
+	Evaluate(context.Context, *coupon.Order) (*coupon.Result, error)
+	DryRun(context.Context, *dto.DryRunRequest) (*dto.DryRunResponse, error)
+	Earn(context.Context, *dto.LedgerRequest, *gorm.DB) error
+	Redeem(context.Context, *dto.LedgerRequest, *gorm.DB) error
+	RevertEarn(context.Context, *dto.LedgerRequest, *gorm.DB) error
+	RevertRedeem(context.Context, *dto.LedgerRequest, *gorm.DB) error
+	Expire(context.Context) error
+	Wallet(context.Context, *dto.WalletRequest) (*dto.WalletResponse, error)
+}
+
+type CouponLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	Database     *postgres.DB
+	CouponRepo   repository.ICouponRepository
+	ProductLogic productLogic.IProductLogic
+}
+
+// NewLogic :
+func NewLogic(couponLogic CouponLogic) ICouponLogic {
+	return &couponLogic
+}
+
+// CreateRule
+func (l *CouponLogic) CreateRule(ctx context.Context, reqData *dto.CreateRuleRequest, tx *gorm.DB) (*model.CouponRules, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	rule := &model.CouponRules{}
+	fillRule(rule, &reqData.Rule)
+	if _, err := l.CouponRepo.CreateRule(ctx, rule, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	rule.TypeRule = rule.Type.String()
+
+	return rule, nil
+}
+
+// FindAllRule
+func (l *CouponLogic) FindAllRule(ctx context.Context, reqData *dto.FindAllRuleRequest) ([]*model.CouponRules, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	rules, err := l.CouponRepo.FindAllRule(ctx, &model.CouponRules{})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, rule := range rules {
+		rule.TypeRule = rule.Type.String()
+	}
+
+	return rules, nil
+}
+
+// FindRule
+func (l *CouponLogic) FindRule(ctx context.Context, reqData *dto.FindRuleRequest) (*model.CouponRules, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	return l.findRule(ctx, reqData.ID)
+}
+
+// UpdateRule replace every field of the rule, order already placed keep coupon evaluated at that time
+func (l *CouponLogic) UpdateRule(ctx context.Context, reqData *dto.UpdateRuleRequest, tx *gorm.DB) (*model.CouponRules, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	rule, err := l.findRule(ctx, reqData.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fillRule(rule, &reqData.Rule)
+	if err := l.CouponRepo.UpdateRule(ctx, rule, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	rule.TypeRule = rule.Type.String()
+
+	return rule, nil
+}
+
+// DeleteRule
+func (l *CouponLogic) DeleteRule(ctx context.Context, reqData *dto.FindRuleRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	rule, err := l.findRule(ctx, reqData.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := l.CouponRepo.DeleteRule(ctx, rule, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Evaluate coupon earned by one seller order using every active rule
+func (l *CouponLogic) Evaluate(ctx context.Context, order *coupon.Order) (*coupon.Result, error) {
+	rules, err := l.CouponRepo.FindAllRule(ctx, &model.CouponRules{
+		Active: true,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	engineRules := make([]*coupon.Rule, 0, len(rules))
+	for _, rule := range rules {
+		engineRule := &coupon.Rule{
+			ID:         rule.ID,
+			Name:       rule.Name,
+			Type:       rule.Type,
+			MinAmount:  rule.MinAmount,
+			MaxAmount:  rule.MaxAmount,
+			Step:       rule.Step,
+			Coupons:    rule.Coupons,
+			Multiplier: rule.Multiplier,
+			SellerID:   rule.SellerID,
+			CategoryID: rule.CategoryID,
+			StartAt:    rule.StartAt,
+			EndAt:      rule.EndAt,
+		}
+		if rule.Currency != nil {
+			engineRule.Currency = *rule.Currency
+		}
+		engineRules = append(engineRules, engineRule)
+	}
+
+	if order.At.IsZero() {
+		order.At = time.Now()
+	}
+
+	return coupon.Evaluate(engineRules, order), nil
+}
+
+// DryRun rule fired for the cart without placing any order, item priced like checkout would
+func (l *CouponLogic) DryRun(ctx context.Context, reqData *dto.DryRunRequest) (*dto.DryRunResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	at := time.Now()
+	if reqData.At != nil {
+		at = *reqData.At
+	}
+
+	orders := []*coupon.Order{}
+	sellers := map[int]*coupon.Order{}
+	for _, item := range reqData.Items {
+		productDetail, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+			ID:     item.ProductID,
+			Status: enum.ProductStatusTypePublished,
+		})
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, err
+		}
+
+		order, ok := sellers[productDetail.SellerID]
+		if !ok {
+			order = &coupon.Order{
+				SellerID: productDetail.SellerID,
+				Currency: productDetail.Currency,
+				At:       at,
+			}
+			sellers[productDetail.SellerID] = order
+			orders = append(orders, order)
+		}
+		order.Items = append(order.Items, coupon.Item{
+			ProductID:  productDetail.ID,
+			CategoryID: productDetail.CategoryID,
+			Price:      productDetail.EffectivePrice(),
+			Quantity:   item.Quantity,
+		})
+	}
+
+	response := &dto.DryRunResponse{}
+	for _, order := range orders {
+		result, err := l.Evaluate(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		response.Coupons += result.Coupons
+		response.Orders = append(response.Orders, &dto.DryRunOrder{
+			SellerID: order.SellerID,
+			Currency: order.Currency,
+			Result:   result,
+		})
+	}
+
+	return response, nil
+}
+
+func (l *CouponLogic) findRule(ctx context.Context, id int) (*model.CouponRules, error) {
+	rule, err := l.CouponRepo.FindRule(ctx, &model.CouponRules{
+		ID: id,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "aturan kupon"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	rule.TypeRule = rule.Type.String()
+
+	return rule, nil
+}
+
+// fillRule field not used by the rule type cleared so it never affect evaluation
+func fillRule(rule *model.CouponRules, data *dto.Rule) {
+	rule.Name = strings.TrimSpace(data.Name)
+	rule.Type = data.Type
+	rule.Currency = nil
+	rule.MinAmount = nil
+	rule.MaxAmount = nil
+	rule.Step = nil
+	rule.Coupons = 0
+	rule.Multiplier = 1
+	rule.SellerID = nil
+	rule.CategoryID = nil
+	rule.StartAt = data.StartAt
+	rule.EndAt = data.EndAt
+	rule.Active = data.Active == nil || *data.Active
+
+	switch data.Type {
+	case enum.CouponRuleTypeItemPriceBand, enum.CouponRuleTypeTotalThreshold:
+		currency := strings.ToUpper(strings.TrimSpace(data.Currency))
+		rule.Currency = &currency
+		rule.Coupons = data.Coupons
+		rule.MinAmount = data.MinAmount
+		if data.Type == enum.CouponRuleTypeItemPriceBand {
+			rule.MaxAmount = data.MaxAmount
+		} else {
+			rule.Step = data.Step
+		}
+	case enum.CouponRuleTypeSellerMultiplier:
+		rule.Multiplier = data.Multiplier
+		rule.SellerID = data.SellerID
+	case enum.CouponRuleTypeCategoryMultiplier:
+		rule.Multiplier = data.Multiplier
+		rule.CategoryID = data.CategoryID
+	}
+}
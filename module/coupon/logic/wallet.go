@@ -0,0 +1,220 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/coupon/dto"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// Earn credit coupon issued by accepted order, valid until configured expiry
+func (l *CouponLogic) Earn(ctx context.Context, reqData *dto.LedgerRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	return l.credit(ctx, reqData, enum.CouponLedgerTypeEarn, tx)
+}
+
+// Redeem spend coupon for the transaction discount, oldest expiry spent first
+func (l *CouponLogic) Redeem(ctx context.Context, reqData *dto.LedgerRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	lots, err := l.CouponRepo.FindAvailable(ctx, &model.CouponLedgers{
+		BuyerID: reqData.BuyerID,
+	}, time.Now(), tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	var available int
+	for _, lot := range lots {
+		available += lot.Remaining
+	}
+	if available < reqData.Coupons {
+		return utilities.ErrorRequest(fmt.Errorf(static.CouponInsufficient, available), http.StatusBadRequest)
+	}
+
+	if _, err := l.debit(ctx, reqData, lots, enum.CouponLedgerTypeRedeem, tx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevertEarn take back coupon issued by order later cancelled or refunded, coupon already spent can not be taken back
+func (l *CouponLogic) RevertEarn(ctx context.Context, reqData *dto.LedgerRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	lots, err := l.CouponRepo.FindAvailable(ctx, &model.CouponLedgers{
+		BuyerID:       reqData.BuyerID,
+		TransactionID: &reqData.TransactionID,
+	}, time.Now(), tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	taken, err := l.debit(ctx, reqData, lots, enum.CouponLedgerTypeRevert, tx)
+	if err != nil {
+		return err
+	}
+	if taken < reqData.Coupons {
+		l.Logger.Warnf("coupon wallet buyer %v short %v coupon reverting transaction %v", reqData.BuyerID, reqData.Coupons-taken, reqData.TransactionID)
+	}
+
+	return nil
+}
+
+// RevertRedeem return coupon spent on order that never went through, returned coupon get fresh expiry
+func (l *CouponLogic) RevertRedeem(ctx context.Context, reqData *dto.LedgerRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	return l.credit(ctx, reqData, enum.CouponLedgerTypeRevert, tx)
+}
+
+// Expire zero every lot past its expiry, each lot expired in its own transaction
+func (l *CouponLogic) Expire(ctx context.Context) error {
+	lots, err := l.CouponRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		tx := l.Database.Gorm.Begin()
+		if err := l.expire(ctx, lot, tx); err != nil {
+			l.Logger.Error(err)
+			tx.Rollback()
+			continue
+		}
+		tx.Commit()
+	}
+
+	return nil
+}
+
+// Wallet balance along with every mutation, newest first
+func (l *CouponLogic) Wallet(ctx context.Context, reqData *dto.WalletRequest) (*dto.WalletResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	balance, err := l.CouponRepo.Balance(ctx, reqData.BuyerID, time.Now())
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	history, err := l.CouponRepo.FindAllLedger(ctx, &model.CouponLedgers{
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, ledger := range history {
+		ledger.TypeLedger = ledger.Type.String()
+	}
+
+	return &dto.WalletResponse{
+		Balance:  balance,
+		Value:    config.Get().Coupon.ValueAmount,
+		Currency: config.Get().Currency.Base,
+		History:  history,
+	}, nil
+}
+
+func (l *CouponLogic) credit(ctx context.Context, reqData *dto.LedgerRequest, ledgerType enum.CouponLedgerType, tx *gorm.DB) error {
+	expiresAt := time.Now().Add(config.Get().Coupon.ExpiryDuration)
+	if _, err := l.CouponRepo.CreateLedger(ctx, &model.CouponLedgers{
+		BuyerID:       reqData.BuyerID,
+		TransactionID: &reqData.TransactionID,
+		Type:          ledgerType,
+		Amount:        reqData.Coupons,
+		Remaining:     reqData.Coupons,
+		ExpiresAt:     &expiresAt,
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// debit take up to requested coupon from locked lots in order, return number taken
+func (l *CouponLogic) debit(ctx context.Context, reqData *dto.LedgerRequest, lots []*model.CouponLedgers, ledgerType enum.CouponLedgerType, tx *gorm.DB) (int, error) {
+	var taken int
+	for _, lot := range lots {
+		if taken == reqData.Coupons {
+			break
+		}
+
+		from := lot.Remaining
+		take := min(from, reqData.Coupons-taken)
+		lot.Remaining = from - take
+		if _, err := l.CouponRepo.UpdateRemaining(ctx, lot, from, tx); err != nil {
+			return 0, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		taken += take
+	}
+
+	if taken == 0 {
+		return 0, nil
+	}
+
+	if _, err := l.CouponRepo.CreateLedger(ctx, &model.CouponLedgers{
+		BuyerID:       reqData.BuyerID,
+		TransactionID: &reqData.TransactionID,
+		Type:          ledgerType,
+		Amount:        -taken,
+	}, tx); err != nil {
+		return 0, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return taken, nil
+}
+
+// expire lot changed by concurrent redemption skipped, picked up again on next run
+func (l *CouponLogic) expire(ctx context.Context, lot *model.CouponLedgers, tx *gorm.DB) error {
+	from := lot.Remaining
+	lot.Remaining = 0
+	affected, err := l.CouponRepo.UpdateRemaining(ctx, lot, from, tx)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return nil
+	}
+
+	if _, err := l.CouponRepo.CreateLedger(ctx, &model.CouponLedgers{
+		BuyerID:       lot.BuyerID,
+		TransactionID: lot.TransactionID,
+		Type:          enum.CouponLedgerTypeExpire,
+		Amount:        -from,
+	}, tx); err != nil {
+		return err
+	}
+
+	return nil
+}
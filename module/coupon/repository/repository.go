@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CouponRepository
+type ICouponRepository interface {
+	CreateRule(context.Context, *model.CouponRules, *gorm.DB) (*int, error)
+	FindAllRule(context.Context, *model.CouponRules) ([]*model.CouponRules, error)
+	FindRule(context.Context, *model.CouponRules) (*model.CouponRules, error)
+	UpdateRule(context.Context, *model.CouponRules, *gorm.DB) error
+	DeleteRule(context.Context, *model.CouponRules, *gorm.DB) error
+	CreateLedger(context.Context, *model.CouponLedgers, *gorm.DB) (*int, error)
+	FindAllLedger(context.Context, *model.CouponLedgers) ([]*model.CouponLedgers, error)
+	FindAvailable(context.Context, *model.CouponLedgers, time.Time, *gorm.DB) ([]*model.CouponLedgers, error)
+	FindExpired(context.Context, time.Time) ([]*model.CouponLedgers, error)
+	UpdateRemaining(context.Context, *model.CouponLedgers, int, *gorm.DB) (int64, error)
+	Balance(context.Context, int, time.Time) (int, error)
+}
+
+type CouponRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(couponRepository CouponRepository) ICouponRepository {
+	return &couponRepository
+}
+
+// CreateRule
+func (l *CouponRepository) CreateRule(ctx context.Context, reqData *model.CouponRules, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAllRule only active rule when Active set
+func (l *CouponRepository) FindAllRule(ctx context.Context, reqData *model.CouponRules) ([]*model.CouponRules, error) {
+	rules := []*model.CouponRules{}
+
+	query := l.Database.Gorm.WithContext(ctx).Model(&model.CouponRules{})
+	if reqData.Active {
+		query = query.Where("active = ?", true)
+	}
+
+	if err := query.
+		Order("id asc").
+		Find(&rules).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FindRule
+func (l *CouponRepository) FindRule(ctx context.Context, reqData *model.CouponRules) (*model.CouponRules, error) {
+	rule := new(model.CouponRules)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.CouponRules{
+			ID: reqData.ID,
+		}).First(&rule).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule every field replaced, empty bound stored as null
+func (l *CouponRepository) UpdateRule(ctx context.Context, reqData *model.CouponRules, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.CouponRules{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"name":        reqData.Name,
+			"type":        reqData.Type,
+			"currency":    reqData.Currency,
+			"min_amount":  reqData.MinAmount,
+			"max_amount":  reqData.MaxAmount,
+			"step":        reqData.Step,
+			"coupons":     reqData.Coupons,
+			"multiplier":  reqData.Multiplier,
+			"seller_id":   reqData.SellerID,
+			"category_id": reqData.CategoryID,
+			"active":      reqData.Active,
+			"start_at":    reqData.StartAt,
+			"end_at":      reqData.EndAt,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteRule
+func (l *CouponRepository) DeleteRule(ctx context.Context, reqData *model.CouponRules, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Delete(&model.CouponRules{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateLedger
+func (l *CouponRepository) CreateLedger(ctx context.Context, reqData *model.CouponLedgers, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAllLedger newest first
+func (l *CouponRepository) FindAllLedger(ctx context.Context, reqData *model.CouponLedgers) ([]*model.CouponLedgers, error) {
+	ledgers := []*model.CouponLedgers{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.CouponLedgers{}).
+		Where(&model.CouponLedgers{
+			BuyerID: reqData.BuyerID,
+		}).
+		Order("created_at desc, id desc").
+		Find(&ledgers).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return ledgers, nil
+}
+
+// FindAvailable lock credit entry of the buyer still holding unexpired coupon, lot of TransactionID first then oldest expiry
+func (l *CouponRepository) FindAvailable(ctx context.Context, reqData *model.CouponLedgers, at time.Time, tx *gorm.DB) ([]*model.CouponLedgers, error) {
+	ledgers := []*model.CouponLedgers{}
+
+	query := tx.WithContext(ctx).Model(&model.CouponLedgers{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("buyer_id = ?", reqData.BuyerID).
+		Where("remaining > 0").
+		Where("(expires_at is null or expires_at > ?)", at)
+	if reqData.TransactionID != nil {
+		query = query.Order(fmt.Sprintf("transaction_id = %d desc nulls last", *reqData.TransactionID))
+	}
+
+	if err := query.
+		Order("expires_at asc nulls last, id asc").
+		Find(&ledgers).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return ledgers, nil
+}
+
+// FindExpired credit entry still holding coupon after its expiry
+func (l *CouponRepository) FindExpired(ctx context.Context, at time.Time) ([]*model.CouponLedgers, error) {
+	ledgers := []*model.CouponLedgers{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.CouponLedgers{}).
+		Where("remaining > 0").
+		Where("expires_at <= ?", at).
+		Order("id asc").
+		Find(&ledgers).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return ledgers, nil
+}
+
+// UpdateRemaining only update entry still holding the given remaining, return zero when it already changed
+func (l *CouponRepository) UpdateRemaining(ctx context.Context, reqData *model.CouponLedgers, from int, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).Model(&model.CouponLedgers{}).
+		Where("id = ?", reqData.ID).
+		Where("remaining = ?", from).
+		Updates(map[string]interface{}{
+			"remaining": reqData.Remaining,
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// Balance unexpired coupon held by the buyer
+func (l *CouponRepository) Balance(ctx context.Context, buyerID int, at time.Time) (int, error) {
+	var balance int
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.CouponLedgers{}).
+		Select("coalesce(sum(remaining), 0)").
+		Where("buyer_id = ?", buyerID).
+		Where("remaining > 0").
+		Where("(expires_at is null or expires_at > ?)", at).
+		Scan(&balance).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,293 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/coupon/dto"
+	"pcstakehometest/module/coupon/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.ICouponLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	coupon := h.EchoRoute.Group("/v1/coupon", m...)
+	coupon.POST("/rule", h.CreateRule, h.EchoRoute.Authentication)
+	coupon.GET("/rule", h.FindAllRule, h.EchoRoute.Authentication)
+	coupon.GET("/rule/:id", h.FindRule, h.EchoRoute.Authentication)
+	coupon.PUT("/rule/:id", h.UpdateRule, h.EchoRoute.Authentication)
+	coupon.DELETE("/rule/:id", h.DeleteRule, h.EchoRoute.Authentication)
+	coupon.POST("/dry-run", h.DryRun, h.EchoRoute.Authentication)
+
+	wallet := h.EchoRoute.Group("/v1/coupons", m...)
+	wallet.GET("", h.Wallet, h.EchoRoute.Authentication)
+}
+
+// CreateRule
+func (h *Handler) CreateRule(c echo.Context) error {
+	var reqData = new(dto.CreateRuleRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.CreateRule(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindAllRule
+func (h *Handler) FindAllRule(c echo.Context) error {
+	var reqData = new(dto.FindAllRuleRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindAllRule(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindRule
+func (h *Handler) FindRule(c echo.Context) error {
+	var reqData = new(dto.FindRuleRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindRule(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// UpdateRule
+func (h *Handler) UpdateRule(c echo.Context) error {
+	var reqData = new(dto.UpdateRuleRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.UpdateRule(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// DeleteRule
+func (h *Handler) DeleteRule(c echo.Context) error {
+	var reqData = new(dto.FindRuleRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.DeleteRule(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// DryRun
+func (h *Handler) DryRun(c echo.Context) error {
+	var reqData = new(dto.DryRunRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.DryRun(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Wallet
+func (h *Handler) Wallet(c echo.Context) error {
+	var reqData = new(dto.WalletRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.Wallet(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
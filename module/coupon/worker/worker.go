@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"pcstakehometest/config"
+	"pcstakehometest/module/coupon/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/scheduler"
+
+	"go.uber.org/fx"
+)
+
+type Worker struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logic     logic.ICouponLogic
+	Logger    *logger.LogRus
+}
+
+func NewWorker(w Worker) {
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "ExpireCoupons",
+		Interval: config.Get().Coupon.ExpireIntervalDuration,
+		Run:      w.Logic.Expire,
+	})
+}
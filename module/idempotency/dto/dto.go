@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"fmt"
+
+	"pcstakehometest/static"
+)
+
+// MaxKeyLength of Idempotency-Key header
+const MaxKeyLength = 255
+
+type BeginRequest struct {
+	UserID int
+	Key    string
+	Method string
+	Path   string
+	Body   []byte
+}
+
+func (d *BeginRequest) Validate() error {
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if d.Key == "" {
+		return fmt.Errorf(static.EmptyValue, "Idempotency-Key")
+	}
+	if len(d.Key) > MaxKeyLength {
+		return fmt.Errorf(static.IdempotencyKeyTooLong, MaxKeyLength)
+	}
+	return nil
+}
+
+type CompleteRequest struct {
+	ID         int
+	StatusCode int
+	Response   []byte
+}
+
+func (d *CompleteRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.StatusCode <= 0 {
+		return fmt.Errorf(static.EmptyValue, "StatusCode")
+	}
+	return nil
+}
+
+// Claim key held by current request, kept in request context under Claim{}
+type Claim struct {
+	ID        int
+	Completed bool
+}
@@ -0,0 +1,164 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/model"
+	"pcstakehometest/module/idempotency/dto"
+	"pcstakehometest/module/idempotency/repository"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// IdempotencyLogic
+type IIdempotencyLogic interface {
+	Begin(context.Context, *dto.BeginRequest) (*model.IdempotencyKeys, error)
+	Complete(context.Context, *dto.CompleteRequest, *gorm.DB) error
+	Abandon(context.Context, int) error
+	PurgeExpired(context.Context) error
+}
+
+type IdempotencyLogic struct {
+	fx.In
+	Logger          *logger.LogRus
+	IdempotencyRepo repository.IIdempotencyRepository
+}
+
+// NewLogic :
+func NewLogic(idempotencyLogic IdempotencyLogic) IIdempotencyLogic {
+	return &idempotencyLogic
+}
+
+// Begin claim key for the request, stored response returned when the same request already finished
+func (l *IdempotencyLogic) Begin(ctx context.Context, reqData *dto.BeginRequest) (*model.IdempotencyKeys, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	fingerprint := fingerprint(reqData.Method, reqData.Path, reqData.Body)
+
+	existing, err := l.IdempotencyRepo.Find(ctx, &model.IdempotencyKeys{
+		UserID: reqData.UserID,
+		Key:    reqData.Key,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err == nil {
+		// Key past retention window free to be used again
+		if !time.Now().Before(existing.ExpiresAt) {
+			if err := l.IdempotencyRepo.Delete(ctx, existing); err != nil {
+				return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+			}
+		} else {
+			if existing.Fingerprint != fingerprint {
+				return nil, utilities.ErrorRequest(errors.New(static.IdempotencyKeyReused), http.StatusUnprocessableEntity)
+			}
+			if existing.StatusCode != nil {
+				return existing, nil
+			}
+
+			// Response commit together with the request changes, so key still in progress past its lease belong to request
+			// which crashed before commit and may safely run again
+			if time.Since(existing.UpdatedAt) < config.Get().Idempotency.LeaseDuration {
+				return nil, utilities.ErrorRequest(errors.New(static.IdempotencyInProgress), http.StatusConflict)
+			}
+			claimed, err := l.IdempotencyRepo.Claim(ctx, existing)
+			if err != nil {
+				return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+			}
+			if !claimed {
+				return nil, utilities.ErrorRequest(errors.New(static.IdempotencyInProgress), http.StatusConflict)
+			}
+			return existing, nil
+		}
+	}
+
+	idempotencyKey := &model.IdempotencyKeys{
+		UserID:      reqData.UserID,
+		Key:         reqData.Key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(config.Get().Idempotency.RetentionDuration),
+	}
+	created, err := l.IdempotencyRepo.Create(ctx, idempotencyKey)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	// Concurrent retry claimed the key first
+	if !created {
+		return nil, utilities.ErrorRequest(errors.New(static.IdempotencyInProgress), http.StatusConflict)
+	}
+
+	return idempotencyKey, nil
+}
+
+// Complete store response to be replayed, within the transaction of the request so response and its changes commit together
+func (l *IdempotencyLogic) Complete(ctx context.Context, reqData *dto.CompleteRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if err := l.IdempotencyRepo.Update(ctx, &model.IdempotencyKeys{
+		ID:         reqData.ID,
+		StatusCode: &reqData.StatusCode,
+		Response:   reqData.Response,
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Abandon release key of failed request so client may retry it
+func (l *IdempotencyLogic) Abandon(ctx context.Context, id int) error {
+	if err := l.IdempotencyRepo.Delete(ctx, &model.IdempotencyKeys{
+		ID: id,
+	}); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// PurgeExpired
+func (l *IdempotencyLogic) PurgeExpired(ctx context.Context) error {
+	count, err := l.IdempotencyRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		l.Logger.Infof("purged %d expired idempotency key", count)
+	}
+
+	return nil
+}
+
+// fingerprint JSON body compacted first so formatting difference of a retry still match
+func fingerprint(method string, path string, body []byte) string {
+	compact := new(bytes.Buffer)
+	if err := json.Compact(compact, body); err == nil {
+		body = compact.Bytes()
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
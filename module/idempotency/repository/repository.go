@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository
+type IIdempotencyRepository interface {
+	Create(context.Context, *model.IdempotencyKeys) (bool, error)
+	Find(context.Context, *model.IdempotencyKeys) (*model.IdempotencyKeys, error)
+	Update(context.Context, *model.IdempotencyKeys, *gorm.DB) error
+	Claim(context.Context, *model.IdempotencyKeys) (bool, error)
+	Delete(context.Context, *model.IdempotencyKeys) error
+	DeleteExpired(context.Context, time.Time) (int64, error)
+}
+
+// IdempotencyRepository write straight to database, key must be visible to concurrent retry before request transaction commit,
+// only the response is stored within given transaction so it commit together with what the request changed
+type IdempotencyRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(idempotencyRepository IdempotencyRepository) IIdempotencyRepository {
+	return &idempotencyRepository
+}
+
+// Create return false when key already taken by other request
+func (l *IdempotencyRepository) Create(ctx context.Context, reqData *model.IdempotencyKeys) (bool, error) {
+	result := l.Database.Gorm.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&reqData)
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Find
+func (l *IdempotencyRepository) Find(ctx context.Context, reqData *model.IdempotencyKeys) (*model.IdempotencyKeys, error) {
+	idempotencyKey := new(model.IdempotencyKeys)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.IdempotencyKeys{
+			UserID: reqData.UserID,
+			Key:    reqData.Key,
+		}).
+		First(&idempotencyKey).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+// Update store response of finished request
+func (l *IdempotencyRepository) Update(ctx context.Context, reqData *model.IdempotencyKeys, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.IdempotencyKeys{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"status_code": reqData.StatusCode,
+			"response":    reqData.Response,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Claim take over key still in progress, return false when other retry took it over first
+func (l *IdempotencyRepository) Claim(ctx context.Context, reqData *model.IdempotencyKeys) (bool, error) {
+	result := l.Database.Gorm.WithContext(ctx).Model(&model.IdempotencyKeys{}).
+		Where("id = ?", reqData.ID).
+		Where("status_code is null").
+		Where("updated_at = ?", reqData.UpdatedAt).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete
+func (l *IdempotencyRepository) Delete(ctx context.Context, reqData *model.IdempotencyKeys) error {
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Delete(&model.IdempotencyKeys{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired
+func (l *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := l.Database.Gorm.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&model.IdempotencyKeys{})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"time"
+
+	"pcstakehometest/module/idempotency/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/scheduler"
+
+	"go.uber.org/fx"
+)
+
+type Worker struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logic     logic.IIdempotencyLogic
+	Logger    *logger.LogRus
+}
+
+func NewWorker(w Worker) {
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "PurgeExpiredIdempotencyKey",
+		Interval: time.Hour,
+		Run:      w.Logic.PurgeExpired,
+	})
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+type UploadRequest struct {
+	ProductID int
+	SellerID  int
+	RoleID    enum.RoleType
+	IsPrimary bool
+	Data      []byte
+}
+
+func (d *UploadRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if len(d.Data) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Image")
+	}
+	if int64(len(d.Data)) > config.Get().Storage.MaxImageSizeBytes {
+		return fmt.Errorf(static.ImageTooLarge, config.Get().Storage.MaxImageSize)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindAllRequest struct {
+	ProductID int
+}
+
+func (d *FindAllRequest) Validate() error {
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	return nil
+}
+
+type ImageRequest struct {
+	ProductID int
+	ImageID   int
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *ImageRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.ImageID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ImageID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type ReorderRequest struct {
+	ProductID int
+	ImageIDs  []int
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *ReorderRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if len(d.ImageIDs) == 0 {
+		return fmt.Errorf(static.EmptyValue, "ImageIDs")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
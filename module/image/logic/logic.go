@@ -0,0 +1,292 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pcstakehometest/config"
+	"pcstakehometest/model"
+	"pcstakehometest/module/image/dto"
+	"pcstakehometest/module/image/repository"
+	productDto "pcstakehometest/module/product/dto"
+	productLogic "pcstakehometest/module/product/logic"
+	"pcstakehometest/package/imaging"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/storage"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+const thumbnailSize = 320
+
+// ImageLogic
+type IImageLogic interface {
+	Upload(context.Context, *dto.UploadRequest, *gorm.DB) (*model.ProductImages, error)
+	FindAll(context.Context, *dto.FindAllRequest) ([]*model.ProductImages, error)
+	Reorder(context.Context, *dto.ReorderRequest, *gorm.DB) error
+	SetPrimary(context.Context, *dto.ImageRequest, *gorm.DB) error
+	Delete(context.Context, *dto.ImageRequest, *gorm.DB) error
+}
+
+type ImageLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	Storage      storage.Storage
+	ProductLogic productLogic.IProductLogic
+	ImageRepo    repository.IImageRepository
+}
+
+// NewLogic :
+func NewLogic(imageLogic ImageLogic) IImageLogic {
+	return &imageLogic
+}
+
+// Upload store original image and its thumbnail
+func (l *ImageLogic) Upload(ctx context.Context, reqData *dto.UploadRequest, tx *gorm.DB) (*model.ProductImages, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	// Make sure product owned by seller
+	if _, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+		ID:       reqData.ProductID,
+		SellerID: reqData.SellerID,
+	}); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	contentType, extension, err := imaging.Sniff(reqData.Data)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(errors.New(static.ImageNotSupported), http.StatusBadRequest)
+	}
+
+	maxPixels := config.Get().Storage.MaxImagePixels
+	img, err := imaging.Decode(reqData.Data, contentType, maxPixels)
+	if err != nil {
+		l.Logger.Error(err)
+		if errors.Is(err, imaging.ErrTooManyPixels) {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.ImageTooManyPixel, maxPixels), http.StatusBadRequest)
+		}
+		return nil, utilities.ErrorRequest(errors.New(static.ImageNotSupported), http.StatusBadRequest)
+	}
+
+	thumbnail, thumbnailType, err := imaging.Encode(imaging.Thumbnail(img, thumbnailSize), contentType)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	name := utilities.RandomString(20)
+	key := fmt.Sprintf("products/%d/%s.%s", reqData.ProductID, name, extension)
+	thumbnailKey := fmt.Sprintf("products/%d/%s_thumb.%s", reqData.ProductID, name, imaging.Extension(thumbnailType))
+
+	if err := l.Storage.Put(ctx, key, bytes.NewReader(reqData.Data), int64(len(reqData.Data)), contentType); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.Storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+		l.Logger.Error(err)
+		l.cleanup(ctx, key)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	count, err := l.ImageRepo.Count(ctx, &model.ProductImages{
+		ProductID: reqData.ProductID,
+	}, tx)
+	if err != nil {
+		l.cleanup(ctx, key, thumbnailKey)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	image := &model.ProductImages{
+		ProductID:    reqData.ProductID,
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		URL:          l.Storage.URL(key),
+		ThumbnailURL: l.Storage.URL(thumbnailKey),
+		ContentType:  contentType,
+		Size:         int64(len(reqData.Data)),
+		Width:        img.Bounds().Dx(),
+		Height:       img.Bounds().Dy(),
+		Position:     int(count),
+		IsPrimary:    count == 0,
+	}
+	if _, err := l.ImageRepo.Create(ctx, image, tx); err != nil {
+		l.cleanup(ctx, key, thumbnailKey)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if reqData.IsPrimary && !image.IsPrimary {
+		if err := l.ImageRepo.UpdatePrimary(ctx, image, tx); err != nil {
+			l.cleanup(ctx, key, thumbnailKey)
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		image.IsPrimary = true
+	}
+
+	return image, nil
+}
+
+// FindAll
+func (l *ImageLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest) ([]*model.ProductImages, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	images, err := l.ImageRepo.FindAll(ctx, &model.ProductImages{
+		ProductID: reqData.ProductID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return images, nil
+}
+
+// Reorder set image position following the given id order
+func (l *ImageLogic) Reorder(ctx context.Context, reqData *dto.ReorderRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if _, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+		ID:       reqData.ProductID,
+		SellerID: reqData.SellerID,
+	}); err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	images, err := l.ImageRepo.FindAll(ctx, &model.ProductImages{
+		ProductID: reqData.ProductID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	exist := map[int]bool{}
+	for _, image := range images {
+		exist[image.ID] = true
+	}
+
+	for position, imageID := range reqData.ImageIDs {
+		if !exist[imageID] {
+			return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "gambar"), http.StatusNotFound)
+		}
+
+		if err := l.ImageRepo.UpdatePosition(ctx, &model.ProductImages{
+			ID:        imageID,
+			ProductID: reqData.ProductID,
+			Position:  position,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+// SetPrimary
+func (l *ImageLogic) SetPrimary(ctx context.Context, reqData *dto.ImageRequest, tx *gorm.DB) error {
+	image, err := l.find(ctx, reqData)
+	if err != nil {
+		return err
+	}
+
+	if err := l.ImageRepo.UpdatePrimary(ctx, image, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Delete remove image and move primary to the next image
+func (l *ImageLogic) Delete(ctx context.Context, reqData *dto.ImageRequest, tx *gorm.DB) error {
+	image, err := l.find(ctx, reqData)
+	if err != nil {
+		return err
+	}
+
+	if err := l.ImageRepo.Delete(ctx, image, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if image.IsPrimary {
+		images, err := l.ImageRepo.FindAll(ctx, &model.ProductImages{
+			ProductID: image.ProductID,
+		})
+		if err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		for _, next := range images {
+			if next.ID == image.ID {
+				continue
+			}
+			if err := l.ImageRepo.UpdatePrimary(ctx, next, tx); err != nil {
+				return utilities.ErrorRequest(err, http.StatusInternalServerError)
+			}
+			break
+		}
+	}
+
+	l.cleanup(ctx, image.StorageKey, image.ThumbnailKey)
+
+	return nil
+}
+
+func (l *ImageLogic) find(ctx context.Context, reqData *dto.ImageRequest) (*model.ProductImages, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if _, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+		ID:       reqData.ProductID,
+		SellerID: reqData.SellerID,
+	}); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	image, err := l.ImageRepo.Find(ctx, &model.ProductImages{
+		ID:        reqData.ImageID,
+		ProductID: reqData.ProductID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "gambar"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return image, nil
+}
+
+// cleanup remove stored object, failure only logged because record is the source of truth
+func (l *ImageLogic) cleanup(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := l.Storage.Delete(ctx, key); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+}
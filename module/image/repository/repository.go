@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// ImageRepository
+type IImageRepository interface {
+	Create(context.Context, *model.ProductImages, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.ProductImages) ([]*model.ProductImages, error)
+	Find(context.Context, *model.ProductImages) (*model.ProductImages, error)
+	Count(context.Context, *model.ProductImages, *gorm.DB) (int64, error)
+	UpdatePosition(context.Context, *model.ProductImages, *gorm.DB) error
+	UpdatePrimary(context.Context, *model.ProductImages, *gorm.DB) error
+	Delete(context.Context, *model.ProductImages, *gorm.DB) error
+}
+
+type ImageRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(imageRepository ImageRepository) IImageRepository {
+	return &imageRepository
+}
+
+// Create
+func (l *ImageRepository) Create(ctx context.Context, reqData *model.ProductImages, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll
+func (l *ImageRepository) FindAll(ctx context.Context, reqData *model.ProductImages) ([]*model.ProductImages, error) {
+	images := []*model.ProductImages{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductImages{}).
+		Where(&model.ProductImages{
+			ProductID: reqData.ProductID,
+		}).
+		Order("position asc").
+		Order("id asc").
+		Find(&images).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// Find
+func (l *ImageRepository) Find(ctx context.Context, reqData *model.ProductImages) (*model.ProductImages, error) {
+	image := new(model.ProductImages)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.ProductImages{
+			ID:        reqData.ID,
+			ProductID: reqData.ProductID,
+		}).First(&image).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return image, nil
+}
+
+// Count
+func (l *ImageRepository) Count(ctx context.Context, reqData *model.ProductImages, tx *gorm.DB) (int64, error) {
+	var count int64
+	if err := tx.WithContext(ctx).Model(&model.ProductImages{}).
+		Where(&model.ProductImages{
+			ProductID: reqData.ProductID,
+		}).
+		Count(&count).Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdatePosition
+func (l *ImageRepository) UpdatePosition(ctx context.Context, reqData *model.ProductImages, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.ProductImages{}).
+		Where("id = ?", reqData.ID).
+		Where("product_id = ?", reqData.ProductID).
+		Updates(map[string]interface{}{
+			"position":   reqData.Position,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePrimary mark one image as primary and unmark the others
+func (l *ImageRepository) UpdatePrimary(ctx context.Context, reqData *model.ProductImages, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.ProductImages{}).
+		Where("product_id = ?", reqData.ProductID).
+		Updates(map[string]interface{}{
+			"is_primary": gorm.Expr("id = ?", reqData.ID),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Delete
+func (l *ImageRepository) Delete(ctx context.Context, reqData *model.ProductImages, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Where("product_id = ?", reqData.ProductID).
+		Delete(&model.ProductImages{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package route
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"pcstakehometest/config"
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/image/dto"
+	"pcstakehometest/module/image/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/storage"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// multipartOverhead give room for boundary and other form field
+const multipartOverhead = 64 * 1024
+
+type Handler struct {
+	fx.In
+	Logic     logic.IImageLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	image := h.EchoRoute.Group("/v1/product/:id/images", m...)
+	image.POST("", h.Upload, h.EchoRoute.Authentication)
+	image.GET("", h.FindAll, h.EchoRoute.Authentication)
+	image.PUT("/order", h.Reorder, h.EchoRoute.Authentication)
+	image.PUT("/:imageId/primary", h.SetPrimary, h.EchoRoute.Authentication)
+	image.DELETE("/:imageId", h.Delete, h.EchoRoute.Authentication)
+
+	// Serve uploaded file when stored on local filesystem
+	if cfg := config.Get().Storage; cfg.Driver == storage.DriverLocal || cfg.Driver == "" {
+		if baseURL, err := url.Parse(cfg.Local.BaseURL); err == nil && baseURL.Path != "" {
+			h.EchoRoute.Static(baseURL.Path, cfg.Local.Path)
+		}
+	}
+}
+
+// Upload
+func (h *Handler) Upload(c echo.Context) error {
+	var reqData = new(dto.UploadRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	maxSize := config.Get().Storage.MaxImageSizeBytes
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize+multipartOverhead)
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		h.Logger.Error(err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusRequestEntityTooLarge),
+			})
+		}
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	if err := echo.FormFieldBinder(c).
+		Bool("primary", (&reqData.IsPrimary)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+		})
+	}
+	defer src.Close()
+
+	// Read one more byte than limit so oversize file still rejected by validation
+	reqData.Data, err = io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+		})
+	}
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Upload(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindAll
+func (h *Handler) FindAll(c echo.Context) error {
+	var reqData = new(dto.FindAllRequest)
+
+	_, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.FindAll(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Reorder
+func (h *Handler) Reorder(c echo.Context) error {
+	var reqData = new(dto.ReorderRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Reorder(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// SetPrimary
+func (h *Handler) SetPrimary(c echo.Context) error {
+	reqData, err := h.imageRequest(c)
+	if err != nil {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.SetPrimary(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// Delete
+func (h *Handler) Delete(c echo.Context) error {
+	reqData, err := h.imageRequest(c)
+	if err != nil {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Delete(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+func (h *Handler) imageRequest(c echo.Context) (*dto.ImageRequest, error) {
+	var reqData = new(dto.ImageRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return nil, utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized)
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		Int("imageId", (&reqData.ImageID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return nil, utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest)
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	return reqData, nil
+}
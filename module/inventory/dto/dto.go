@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+type AdjustRequest struct {
+	ProductID int
+	Quantity  int
+	Note      string
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *AdjustRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.Quantity == 0 {
+		return fmt.Errorf(static.EmptyValue, "Quantity")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// SetStockRequest Stock absolute value the product end up with
+type SetStockRequest struct {
+	ProductID int
+	Stock     int
+	Note      string
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *SetStockRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.Stock < 0 {
+		return fmt.Errorf(static.NegativeValue, "Stock")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindMovementsRequest struct {
+	ProductID int
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *FindMovementsRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type ReserveItem struct {
+	ProductID int
+	Quantity  int
+}
+
+type ReserveRequest struct {
+	TransactionID int
+	Items         []ReserveItem
+}
+
+func (d *ReserveRequest) Validate() error {
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if len(d.Items) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Product")
+	}
+	for _, item := range d.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf(static.MinValue, "Quantity", 0)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/inventory/dto"
+	"pcstakehometest/module/inventory/repository"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// InventoryLogic
+type IInventoryLogic interface {
+	Adjust(context.Context, *dto.AdjustRequest, *gorm.DB) (*model.InventoryMovements, error)
+	SetStock(context.Context, *dto.SetStockRequest, *gorm.DB) (*model.InventoryMovements, error)
+	FindMovements(context.Context, *dto.FindMovementsRequest) ([]*model.InventoryMovements, error)
+	Reserve(context.Context, *dto.ReserveRequest, *gorm.DB) error
+	Commit(context.Context, int, *gorm.DB) error
+	Release(context.Context, int, string, *gorm.DB) error
+	FindExpired(context.Context) ([]int, error)
+}
+
+type InventoryLogic struct {
+	fx.In
+	Logger        *logger.LogRus
+	InventoryRepo repository.IInventoryRepository
+}
+
+// NewLogic :
+func NewLogic(inventoryLogic InventoryLogic) IInventoryLogic {
+	return &inventoryLogic
+}
+
+// Adjust add or subtract stock of seller product
+func (l *InventoryLogic) Adjust(ctx context.Context, reqData *dto.AdjustRequest, tx *gorm.DB) (*model.InventoryMovements, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.lock(ctx, reqData.ProductID, reqData.SellerID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.adjust(ctx, product, reqData.Quantity, reqData.Note, tx)
+}
+
+// SetStock overwrite stock with absolute value, difference taken from the locked row so concurrent order not lost
+func (l *InventoryLogic) SetStock(ctx context.Context, reqData *dto.SetStockRequest, tx *gorm.DB) (*model.InventoryMovements, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.lock(ctx, reqData.ProductID, reqData.SellerID, tx)
+	if err != nil {
+		return nil, err
+	}
+	if product.Stock == reqData.Stock {
+		return nil, nil
+	}
+
+	return l.adjust(ctx, product, reqData.Stock-product.Stock, reqData.Note, tx)
+}
+
+// lock product of seller until transaction finished
+func (l *InventoryLogic) lock(ctx context.Context, productID int, sellerID int, tx *gorm.DB) (*model.Products, error) {
+	product, err := l.InventoryRepo.LockProduct(ctx, &model.Products{
+		ID:       productID,
+		SellerID: sellerID,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "produk"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return product, nil
+}
+
+// adjust change stock of locked product and record it as adjustment movement
+func (l *InventoryLogic) adjust(ctx context.Context, product *model.Products, quantity int, note string, tx *gorm.DB) (*model.InventoryMovements, error) {
+	stock := product.Stock + quantity
+	if stock < 0 {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.OutOfStock, product.Name), http.StatusBadRequest)
+	}
+
+	if err := l.InventoryRepo.UpdateStock(ctx, &model.Products{
+		ID:    product.ID,
+		Stock: stock,
+	}, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	movement := &model.InventoryMovements{
+		ProductID:  product.ID,
+		SellerID:   product.SellerID,
+		Type:       enum.InventoryMovementTypeAdjustment,
+		Quantity:   quantity,
+		StockAfter: stock,
+		Note:       note,
+	}
+	if err := l.InventoryRepo.CreateMovement(ctx, movement, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	movement.TypeMovement = movement.Type.String()
+
+	return movement, nil
+}
+
+// FindMovements
+func (l *InventoryLogic) FindMovements(ctx context.Context, reqData *dto.FindMovementsRequest) ([]*model.InventoryMovements, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	movements, err := l.InventoryRepo.FindMovements(ctx, &model.InventoryMovements{
+		ProductID: reqData.ProductID,
+		SellerID:  reqData.SellerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, v := range movements {
+		v.TypeMovement = v.Type.String()
+	}
+
+	return movements, nil
+}
+
+// Reserve lock and subtract stock for every ordered product
+func (l *InventoryLogic) Reserve(ctx context.Context, reqData *dto.ReserveRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	quantities := map[int]int{}
+	productIDs := []int{}
+	for _, item := range reqData.Items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	// Lock in the same order on every request to avoid deadlock
+	sort.Ints(productIDs)
+
+	expiredAt := time.Now().Add(config.Get().Inventory.ReservationTimeoutDuration)
+	for _, productID := range productIDs {
+		product, err := l.InventoryRepo.LockProduct(ctx, &model.Products{
+			ID: productID,
+		}, tx)
+		if err != nil {
+			l.Logger.Error(err)
+			if err == gorm.ErrRecordNotFound {
+				return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "produk"), http.StatusNotFound)
+			}
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		quantity := quantities[productID]
+		if product.Stock < quantity {
+			return utilities.ErrorRequest(fmt.Errorf(static.OutOfStock, product.Name), http.StatusConflict)
+		}
+
+		stock := product.Stock - quantity
+		if err := l.InventoryRepo.UpdateStock(ctx, &model.Products{
+			ID:    product.ID,
+			Stock: stock,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		if err := l.InventoryRepo.CreateReservation(ctx, &model.StockReservations{
+			TransactionID: reqData.TransactionID,
+			ProductID:     product.ID,
+			Quantity:      quantity,
+			Status:        enum.ReservationStatusTypeReserved,
+			ExpiredAt:     expiredAt,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		if err := l.InventoryRepo.CreateMovement(ctx, &model.InventoryMovements{
+			ProductID:     product.ID,
+			SellerID:      product.SellerID,
+			TransactionID: &reqData.TransactionID,
+			Type:          enum.InventoryMovementTypeReserve,
+			Quantity:      -quantity,
+			StockAfter:    stock,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+// Commit keep reserved stock of accepted transaction
+func (l *InventoryLogic) Commit(ctx context.Context, transactionID int, tx *gorm.DB) error {
+	reservations, err := l.InventoryRepo.FindReservations(ctx, &model.StockReservations{
+		TransactionID: transactionID,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status == enum.ReservationStatusTypeReleased {
+			return utilities.ErrorRequest(errors.New(static.ReservationReleased), http.StatusConflict)
+		}
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status != enum.ReservationStatusTypeReserved {
+			continue
+		}
+
+		if err := l.InventoryRepo.UpdateReservation(ctx, &model.StockReservations{
+			ID:     reservation.ID,
+			Status: enum.ReservationStatusTypeCommitted,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+// Release return reserved or committed stock of transaction
+func (l *InventoryLogic) Release(ctx context.Context, transactionID int, note string, tx *gorm.DB) error {
+	reservations, err := l.InventoryRepo.FindReservations(ctx, &model.StockReservations{
+		TransactionID: transactionID,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return l.release(ctx, reservations, note, tx)
+}
+
+// FindExpired transaction which still hold reserved stock after its reservation expired
+func (l *InventoryLogic) FindExpired(ctx context.Context) ([]int, error) {
+	transactionIDs, err := l.InventoryRepo.FindExpiredReservations(ctx, time.Now())
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return transactionIDs, nil
+}
+
+func (l *InventoryLogic) release(ctx context.Context, reservations []*model.StockReservations, note string, tx *gorm.DB) error {
+	for _, reservation := range reservations {
+		if reservation.Status == enum.ReservationStatusTypeReleased {
+			continue
+		}
+
+		product, err := l.InventoryRepo.LockProduct(ctx, &model.Products{
+			ID: reservation.ProductID,
+		}, tx)
+		if err != nil {
+			l.Logger.Error(err)
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		stock := product.Stock + reservation.Quantity
+		if err := l.InventoryRepo.UpdateStock(ctx, &model.Products{
+			ID:    product.ID,
+			Stock: stock,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		if err := l.InventoryRepo.UpdateReservation(ctx, &model.StockReservations{
+			ID:     reservation.ID,
+			Status: enum.ReservationStatusTypeReleased,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		if err := l.InventoryRepo.CreateMovement(ctx, &model.InventoryMovements{
+			ProductID:     product.ID,
+			SellerID:      product.SellerID,
+			TransactionID: &reservation.TransactionID,
+			Type:          enum.InventoryMovementTypeRelease,
+			Quantity:      reservation.Quantity,
+			StockAfter:    stock,
+			Note:          note,
+		}, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
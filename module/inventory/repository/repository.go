@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InventoryRepository
+type IInventoryRepository interface {
+	LockProduct(context.Context, *model.Products, *gorm.DB) (*model.Products, error)
+	UpdateStock(context.Context, *model.Products, *gorm.DB) error
+	CreateMovement(context.Context, *model.InventoryMovements, *gorm.DB) error
+	FindMovements(context.Context, *model.InventoryMovements) ([]*model.InventoryMovements, error)
+	CreateReservation(context.Context, *model.StockReservations, *gorm.DB) error
+	FindReservations(context.Context, *model.StockReservations, *gorm.DB) ([]*model.StockReservations, error)
+	UpdateReservation(context.Context, *model.StockReservations, *gorm.DB) error
+	FindExpiredReservations(context.Context, time.Time) ([]int, error)
+}
+
+type InventoryRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(inventoryRepository InventoryRepository) IInventoryRepository {
+	return &inventoryRepository
+}
+
+// LockProduct select product row for update until transaction finished
+func (l *InventoryRepository) LockProduct(ctx context.Context, reqData *model.Products, tx *gorm.DB) (*model.Products, error) {
+	product := new(model.Products)
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(&model.Products{
+			ID:       reqData.ID,
+			SellerID: reqData.SellerID,
+		}).First(&product).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return product, nil
+}
+
+// UpdateStock
+func (l *InventoryRepository) UpdateStock(ctx context.Context, reqData *model.Products, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Products{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"stock":      reqData.Stock,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateMovement
+func (l *InventoryRepository) CreateMovement(ctx context.Context, reqData *model.InventoryMovements, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindMovements
+func (l *InventoryRepository) FindMovements(ctx context.Context, reqData *model.InventoryMovements) ([]*model.InventoryMovements, error) {
+	movements := []*model.InventoryMovements{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.InventoryMovements{}).
+		Where(&model.InventoryMovements{
+			ProductID: reqData.ProductID,
+			SellerID:  reqData.SellerID,
+		}).
+		Order("id desc").
+		Find(&movements).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// CreateReservation
+func (l *InventoryRepository) CreateReservation(ctx context.Context, reqData *model.StockReservations, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindReservations select reservation rows for update until transaction finished
+func (l *InventoryRepository) FindReservations(ctx context.Context, reqData *model.StockReservations, tx *gorm.DB) ([]*model.StockReservations, error) {
+	reservations := []*model.StockReservations{}
+
+	if err := tx.WithContext(ctx).Model(&model.StockReservations{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(&model.StockReservations{
+			TransactionID: reqData.TransactionID,
+			Status:        reqData.Status,
+		}).
+		Order("product_id asc").
+		Find(&reservations).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// UpdateReservation
+func (l *InventoryRepository) UpdateReservation(ctx context.Context, reqData *model.StockReservations, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.StockReservations{}).
+		Where("id = ?", reqData.ID).
+		Updates(model.StockReservations{
+			Status:    reqData.Status,
+			UpdatedAt: time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindExpiredReservations return transaction id which still hold reserved stock after expired time
+func (l *InventoryRepository) FindExpiredReservations(ctx context.Context, now time.Time) ([]int, error) {
+	transactionIDs := []int{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.StockReservations{}).
+		Distinct("transaction_id").
+		Where("status = ?", enum.ReservationStatusTypeReserved).
+		Where("expired_at <= ?", now).
+		Pluck("transaction_id", &transactionIDs).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return transactionIDs, nil
+}
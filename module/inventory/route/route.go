@@ -0,0 +1,116 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/inventory/dto"
+	"pcstakehometest/module/inventory/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.IInventoryLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	inventory := h.EchoRoute.Group("/v1/inventory", m...)
+	inventory.POST("/adjustment", h.Adjust, h.EchoRoute.Authentication)
+	inventory.GET("/movement", h.FindMovements, h.EchoRoute.Authentication)
+}
+
+// Adjust
+func (h *Handler) Adjust(c echo.Context) error {
+	var reqData = new(dto.AdjustRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Adjust(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindMovements
+func (h *Handler) FindMovements(c echo.Context) error {
+	var reqData = new(dto.FindMovementsRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.QueryParamsBinder(c).
+		Int("product", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindMovements(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+type CreateRequest struct {
+	CheckoutID int
+	BuyerID    int
+	RoleID     enum.RoleType
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.CheckoutID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "CheckoutID")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindRequest struct {
+	ID      int
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *FindRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type WebhookRequest struct {
+	Payload   []byte
+	Signature string
+}
+
+func (d *WebhookRequest) Validate() error {
+	if len(d.Payload) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Payload")
+	}
+	if d.Signature == "" {
+		return fmt.Errorf(static.EmptyValue, "Signature")
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/payment/dto"
+	"pcstakehometest/module/payment/repository"
+	refundDto "pcstakehometest/module/refund/dto"
+	refundLogic "pcstakehometest/module/refund/logic"
+	transactionDto "pcstakehometest/module/transaction/dto"
+	transactionLogic "pcstakehometest/module/transaction/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/package/payment"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// PaymentLogic
+type IPaymentLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Payments, error)
+	Find(context.Context, *dto.FindRequest) (*model.Payments, error)
+	Webhook(context.Context, *dto.WebhookRequest, *gorm.DB) (*model.Payments, error)
+}
+
+type PaymentLogic struct {
+	fx.In
+	Logger           *logger.LogRus
+	Gateway          payment.PaymentGateway
+	PaymentRepo      repository.IPaymentRepository
+	RefundLogic      refundLogic.IRefundLogic
+	TransactionLogic transactionLogic.ITransactionLogic
+}
+
+// NewLogic :
+func NewLogic(paymentLogic PaymentLogic) IPaymentLogic {
+	return &paymentLogic
+}
+
+// Create payment intent for every unpaid pending order of checkout, pending intent reused while its amount still match
+func (l *PaymentLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Payments, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	checkout, err := l.TransactionLogic.FindCheckout(ctx, &transactionDto.FindCheckoutRequest{
+		ID:      reqData.CheckoutID,
+		BuyerID: reqData.BuyerID,
+		RoleID:  reqData.RoleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	amount := outstanding(checkout)
+
+	existing, err := l.PaymentRepo.FindOpen(ctx, &model.Payments{
+		CheckoutID: checkout.ID,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	if err == nil {
+		// Payment which did not cover the checkout marked no order paid and was returned, buyer may pay again
+		if existing.Status == enum.PaymentStatusTypeSucceeded {
+			if !amount.IsPositive() {
+				return nil, utilities.ErrorRequest(errors.New(static.PaymentAlreadyPaid), http.StatusConflict)
+			}
+		} else if existing.Amount.Cmp(amount) == 0 && existing.Currency == checkout.Currency {
+			existing.StatusPayment = existing.Status.String()
+			return existing, nil
+		}
+
+		// Checkout total changed since the intent created, void it so its late callback can not mark the orders paid
+		if err := l.void(ctx, existing, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !amount.IsPositive() {
+		return nil, utilities.ErrorRequest(errors.New(static.PaymentNothingToPay), http.StatusConflict)
+	}
+
+	charge, err := l.Gateway.CreateIntent(ctx, &payment.Intent{
+		Reference: checkout.PaymentReference,
+		Amount:    amount,
+		Currency:  checkout.Currency,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if errors.Is(err, payment.ErrTimeout) {
+			return nil, utilities.ErrorRequest(errors.New(static.PaymentTimeout), http.StatusGatewayTimeout)
+		}
+		return nil, utilities.ErrorRequest(errors.New(static.PaymentGatewayFailed), http.StatusBadGateway)
+	}
+
+	result := &model.Payments{
+		CheckoutID:        checkout.ID,
+		BuyerID:           reqData.BuyerID,
+		Amount:            amount,
+		Currency:          checkout.Currency,
+		Provider:          l.Gateway.Name(),
+		ProviderReference: charge.ProviderReference,
+		Status:            enum.PaymentStatusTypePending,
+	}
+	if _, err := l.PaymentRepo.Create(ctx, result, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	result.StatusPayment = result.Status.String()
+	result.PaymentURL = charge.PaymentURL
+
+	return result, nil
+}
+
+// Find
+func (l *PaymentLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.Payments, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	result, err := l.PaymentRepo.Find(ctx, &model.Payments{
+		ID:      reqData.ID,
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "pembayaran"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	result.StatusPayment = result.Status.String()
+
+	return result, nil
+}
+
+// Webhook settle payment from signed gateway callback, repeated callback with the same result accepted
+func (l *PaymentLogic) Webhook(ctx context.Context, reqData *dto.WebhookRequest, tx *gorm.DB) (*model.Payments, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	event, err := l.Gateway.ParseWebhook(reqData.Payload, reqData.Signature)
+	if err != nil {
+		l.Logger.Error(err)
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return nil, utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized)
+		}
+		return nil, utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest)
+	}
+
+	result, err := l.PaymentRepo.Find(ctx, &model.Payments{
+		Provider:          l.Gateway.Name(),
+		ProviderReference: event.ProviderReference,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "pembayaran"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	status := enum.PaymentStatusTypeFailed
+	if event.Status == payment.StatusSucceeded {
+		status = enum.PaymentStatusTypeSucceeded
+	}
+
+	if result.Status != enum.PaymentStatusTypePending {
+		if result.Status == status {
+			result.StatusPayment = result.Status.String()
+			return result, nil
+		}
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.PaymentAlreadySettled, result.Status), http.StatusConflict)
+	}
+
+	// Gateway never collect more than the intent, anything else is not a callback of this intent
+	if status == enum.PaymentStatusTypeSucceeded {
+		if event.Currency != result.Currency || event.Amount.Cmp(result.Amount) > 0 {
+			err := fmt.Errorf(static.PaymentAmountMismatch, event.Amount, event.Currency, result.Amount, result.Currency)
+			l.Logger.Error(err)
+			return nil, utilities.ErrorRequest(err, http.StatusConflict)
+		}
+	}
+
+	now := time.Now()
+	result.Status = status
+	if status == enum.PaymentStatusTypeSucceeded {
+		result.PaidAt = &now
+	} else if event.FailureReason != "" {
+		result.FailureReason = &event.FailureReason
+	}
+
+	affected, err := l.PaymentRepo.UpdateStatus(ctx, result, tx)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	// Concurrent callback settled it first
+	if affected == 0 {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.PaymentAlreadySettled, "diproses"), http.StatusConflict)
+	}
+	result.StatusPayment = result.Status.String()
+
+	if status != enum.PaymentStatusTypeSucceeded {
+		return result, nil
+	}
+
+	checkout, err := l.TransactionLogic.FindCheckout(ctx, &transactionDto.FindCheckoutRequest{
+		ID:      result.CheckoutID,
+		BuyerID: result.BuyerID,
+		RoleID:  enum.RoleTypeBuyer,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Checkout may change after the intent, e.g. one of its orders cancelled, orders only marked paid when collected
+	// cover all of them and whatever collected beyond that returned to buyer
+	collected := event.Amount
+	payable := outstanding(checkout)
+	excess := collected
+	transactions := []*model.Transactions{}
+	if collected.Cmp(payable) >= 0 {
+		transactions, err = l.TransactionLogic.MarkPaid(ctx, result.CheckoutID, now, tx)
+		if err != nil {
+			return nil, err
+		}
+		excess = collected.Sub(payable)
+	}
+
+	if excess.IsPositive() {
+		if err := l.refundExcess(ctx, result, checkout, excess, len(transactions) > 0, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sent once the payment committed, failed delivery should not undo it
+	for _, transaction := range transactions {
+		if err := notifier.Defer(ctx, &notifier.Message{
+			UserID: transaction.SellerID,
+			Event:  notifier.EventOrderPaid,
+			Title:  static.OrderPaidTitle,
+			Body:   fmt.Sprintf(static.OrderPaidBody, transaction.ID),
+			Data: map[string]interface{}{
+				"TransactionID": transaction.ID,
+				"PaymentID":     result.ID,
+			},
+		}); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+
+	return result, nil
+}
+
+// void settle pending intent as failed so it can not be paid anymore
+func (l *PaymentLogic) void(ctx context.Context, result *model.Payments, tx *gorm.DB) error {
+	reason := static.PaymentIntentStale
+	result.Status = enum.PaymentStatusTypeFailed
+	result.FailureReason = &reason
+
+	affected, err := l.PaymentRepo.UpdateStatus(ctx, result, tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	// Concurrent callback settled it first
+	if affected == 0 {
+		return utilities.ErrorRequest(fmt.Errorf(static.PaymentAlreadySettled, "diproses"), http.StatusConflict)
+	}
+
+	return nil
+}
+
+// refundExcess return money collected beyond the orders marked paid, spread over the unpaid orders it was collected for
+func (l *PaymentLogic) refundExcess(ctx context.Context, result *model.Payments, checkout *model.Checkouts, excess money.Amount, paid bool, tx *gorm.DB) error {
+	unpaid := []*model.Transactions{}
+	for _, transaction := range checkout.Transactions {
+		if transaction.PaidAt != nil || (paid && transaction.Status == enum.TransactionStatusTypePending) {
+			continue
+		}
+		unpaid = append(unpaid, transaction)
+	}
+	if len(unpaid) == 0 {
+		unpaid = checkout.Transactions
+	}
+
+	for i, transaction := range unpaid {
+		if !excess.IsPositive() {
+			break
+		}
+
+		// Last order take whatever left
+		amount := excess
+		if i < len(unpaid)-1 && transaction.DisplayGrandTotal.Cmp(excess) < 0 {
+			amount = transaction.DisplayGrandTotal
+		}
+
+		if _, err := l.RefundLogic.Refund(ctx, &refundDto.RefundRequest{
+			PaymentID:     &result.ID,
+			CheckoutID:    &checkout.ID,
+			TransactionID: transaction.ID,
+			Amount:        amount,
+			Currency:      result.Currency,
+			Reason:        static.PaymentExcessRefund,
+		}, tx); err != nil {
+			return err
+		}
+		excess = excess.Sub(amount)
+	}
+
+	return nil
+}
+
+// outstanding total of checkout orders still pending and unpaid, in checkout currency
+func outstanding(checkout *model.Checkouts) money.Amount {
+	var amount money.Amount
+	for _, transaction := range checkout.Transactions {
+		if transaction.PaidAt == nil && transaction.Status == enum.TransactionStatusTypePending {
+			amount = amount.Add(transaction.DisplayGrandTotal)
+		}
+	}
+	return amount
+}
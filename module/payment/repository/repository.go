@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// PaymentRepository
+type IPaymentRepository interface {
+	Create(context.Context, *model.Payments, *gorm.DB) (*int, error)
+	Find(context.Context, *model.Payments) (*model.Payments, error)
+	FindOpen(context.Context, *model.Payments) (*model.Payments, error)
+	UpdateStatus(context.Context, *model.Payments, *gorm.DB) (int64, error)
+	CreateRefund(context.Context, *model.Refunds, *gorm.DB) (*int, error)
+	FindPendingRefund(context.Context) ([]model.Refunds, error)
+	UpdateRefund(context.Context, *model.Refunds, *gorm.DB) (int64, error)
+	SumRefund(context.Context, *model.Refunds, *gorm.DB) (money.Amount, error)
+}
+
+type PaymentRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(paymentRepository PaymentRepository) IPaymentRepository {
+	return &paymentRepository
+}
+
+// Create
+func (l *PaymentRepository) Create(ctx context.Context, reqData *model.Payments, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// Find
+func (l *PaymentRepository) Find(ctx context.Context, reqData *model.Payments) (*model.Payments, error) {
+	payment := new(model.Payments)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Payments{
+			ID:                reqData.ID,
+			BuyerID:           reqData.BuyerID,
+			CheckoutID:        reqData.CheckoutID,
+			Provider:          reqData.Provider,
+			ProviderReference: reqData.ProviderReference,
+			Status:            reqData.Status,
+		}).
+		Order("id desc").
+		First(&payment).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return payment, nil
+}
+
+// FindOpen latest payment of checkout which still pending or already succeeded
+func (l *PaymentRepository) FindOpen(ctx context.Context, reqData *model.Payments) (*model.Payments, error) {
+	payment := new(model.Payments)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Payments{
+			CheckoutID: reqData.CheckoutID,
+		}).
+		Where("status in ?", []enum.PaymentStatusType{enum.PaymentStatusTypePending, enum.PaymentStatusTypeSucceeded}).
+		Order("id desc").
+		First(&payment).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return payment, nil
+}
+
+// UpdateStatus only settle payment still pending, return zero when already settled
+func (l *PaymentRepository) UpdateStatus(ctx context.Context, reqData *model.Payments, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).Model(&model.Payments{}).
+		Where("id = ?", reqData.ID).
+		Where("status = ?", enum.PaymentStatusTypePending).
+		Updates(map[string]interface{}{
+			"status":         reqData.Status,
+			"failure_reason": reqData.FailureReason,
+			"paid_at":        reqData.PaidAt,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CreateRefund
+func (l *PaymentRepository) CreateRefund(ctx context.Context, reqData *model.Refunds, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindPendingRefund refund recorded but not yet sent to the gateway, oldest first
+func (l *PaymentRepository) FindPendingRefund(ctx context.Context) ([]model.Refunds, error) {
+	var refunds []model.Refunds
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Refunds{
+			Status: enum.RefundStatusTypePending,
+		}).
+		Order("id asc").
+		Find(&refunds).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// UpdateRefund only settle refund still pending, return zero when already settled
+func (l *PaymentRepository) UpdateRefund(ctx context.Context, reqData *model.Refunds, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).Model(&model.Refunds{}).
+		Where("id = ?", reqData.ID).
+		Where("status = ?", enum.RefundStatusTypePending).
+		Updates(map[string]interface{}{
+			"status":             reqData.Status,
+			"provider_reference": reqData.ProviderReference,
+			"failure_reason":     reqData.FailureReason,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// SumRefund total refunded so far including refund still pending, read inside transaction so refund in the same request counted
+func (l *PaymentRepository) SumRefund(ctx context.Context, reqData *model.Refunds, tx *gorm.DB) (money.Amount, error) {
+	var total money.Amount
+	if err := tx.WithContext(ctx).Model(&model.Refunds{}).
+		Where(&model.Refunds{
+			PaymentID:     reqData.PaymentID,
+			TransactionID: reqData.TransactionID,
+		}).
+		Where("status <> ?", enum.RefundStatusTypeFailed).
+		Select("coalesce(sum(amount), 0)").
+		Row().
+		Scan(&total); err != nil {
+		l.Logger.Error(err)
+		return total, err
+	}
+	return total, nil
+}
@@ -0,0 +1,152 @@
+package route
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/module/payment/dto"
+	"pcstakehometest/module/payment/logic"
+	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/payment"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.IPaymentLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+	Db        *postgres.DB
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	payment := h.EchoRoute.Group("/v1/payment", m...)
+	payment.POST("", h.Create, h.EchoRoute.Authentication, h.EchoRoute.Idempotency)
+	payment.POST("/webhook", h.Webhook)
+	payment.GET("/:id", h.Find, h.EchoRoute.Authentication)
+}
+
+// Create
+func (h *Handler) Create(c echo.Context) error {
+	var reqData = new(dto.CreateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Create(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Find
+func (h *Handler) Find(c echo.Context) error {
+	var reqData = new(dto.FindRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.Find(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Webhook called by payment gateway, authenticated by body signature instead of user token
+func (h *Handler) Webhook(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData := &dto.WebhookRequest{
+		Payload:   payload,
+		Signature: c.Request().Header.Get(payment.HeaderSignature),
+	}
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Webhook(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pcstakehometest/model"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+const (
+	ColumnSKU         = "sku"
+	ColumnName        = "name"
+	ColumnDescription = "description"
+	ColumnPrice       = "price"
+	ColumnStock       = "stock"
+	ColumnCurrency    = "currency"
+)
+
+// CSVHeader column order used by export and accepted by import
+var CSVHeader = []string{ColumnSKU, ColumnName, ColumnDescription, ColumnPrice, ColumnCurrency, ColumnStock}
+
+// CSVColumns map header name to its index, stock and currency column are optional
+type CSVColumns map[string]int
+
+func ParseCSVHeader(header []string) (CSVColumns, error) {
+	columns := CSVColumns{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, name := range []string{ColumnSKU, ColumnName, ColumnDescription, ColumnPrice} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf(static.InvalidCSVHeader, name)
+		}
+	}
+
+	return columns, nil
+}
+
+// HasStock
+func (c CSVColumns) HasStock() bool {
+	_, ok := c[ColumnStock]
+	return ok
+}
+
+func (c CSVColumns) value(record []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// ParseCSVRecord convert one csv row into create request
+func (c CSVColumns) ParseCSVRecord(record []string) (*CreateRequest, error) {
+	reqData := &CreateRequest{
+		SKU:         c.value(record, ColumnSKU),
+		Name:        c.value(record, ColumnName),
+		Description: c.value(record, ColumnDescription),
+		Currency:    strings.ToUpper(c.value(record, ColumnCurrency)),
+	}
+
+	if reqData.SKU == "" {
+		return reqData, fmt.Errorf(static.EmptyValue, "SKU")
+	}
+
+	price, err := money.Parse(c.value(record, ColumnPrice))
+	if err != nil {
+		return reqData, fmt.Errorf(static.InvalidCSVValue, "Price")
+	}
+	reqData.Price = price
+
+	if c.HasStock() {
+		stock, err := strconv.Atoi(c.value(record, ColumnStock))
+		if err != nil {
+			return reqData, fmt.Errorf(static.InvalidCSVValue, "Stock")
+		}
+		reqData.Stock = stock
+	}
+
+	return reqData, nil
+}
+
+// ToCSVRecord
+func ToCSVRecord(product *model.Products) []string {
+	return []string{
+		product.SKU,
+		product.Name,
+		product.Description,
+		product.Price.String(),
+		product.Currency,
+		strconv.Itoa(product.Stock),
+	}
+}
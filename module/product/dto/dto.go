@@ -3,16 +3,26 @@ package dto
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"pcstakehometest/enum"
 	"pcstakehometest/model"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/money"
 	"pcstakehometest/static"
 )
 
 type CreateRequest struct {
+	SKU         string
 	Name        string
 	Description string
-	Price       float64
+	Price       money.Amount
+	Currency    string
+	Stock       int
+	Status      enum.ProductStatusType
+	PublishAt   *time.Time
+	CategoryID  int
+	Attributes  attribute.Values
 	SellerID    int
 	RoleID      enum.RoleType
 }
@@ -24,9 +34,17 @@ func (d *CreateRequest) Validate() error {
 	if d.Description == "" {
 		return fmt.Errorf(static.EmptyValue, "Description")
 	}
-	if d.Price <= 0 {
+	if !d.Price.IsPositive() {
 		return fmt.Errorf(static.MinValue, "Price", 0)
 	}
+	if d.Stock < 0 {
+		return fmt.Errorf(static.NegativeValue, "Stock")
+	}
+	if d.Status != 0 {
+		if err := d.Status.IsValid(); err != nil {
+			return err
+		}
+	}
 	if err := d.RoleID.IsValid(); err != nil {
 		return err
 	}
@@ -36,15 +54,281 @@ func (d *CreateRequest) Validate() error {
 	return nil
 }
 
-type FindAllRequest struct {
+type UpdateRequest struct {
+	ID          int
+	Name        string
+	Description string
+	Price       money.Amount
+	Currency    string
+	CategoryID  int
+	Attributes  attribute.Values
+	SellerID    int
+	RoleID      enum.RoleType
+}
+
+func (d *UpdateRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.Description == "" {
+		return fmt.Errorf(static.EmptyValue, "Description")
+	}
+	if !d.Price.IsPositive() {
+		return fmt.Errorf(static.MinValue, "Price", 0)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	ID       int
 	SellerID int
+	RoleID   enum.RoleType
+}
+
+func (d *DeleteRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type StatusRequest struct {
+	ID        int
+	Status    enum.ProductStatusType
+	PublishAt *time.Time
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *StatusRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.Status.IsValid(); err != nil {
+		return err
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type SaleRequest struct {
+	ProductID int
+	SalePrice money.Amount
+	StartAt   time.Time
+	EndAt     time.Time
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *SaleRequest) Validate() error {
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if !d.SalePrice.IsPositive() {
+		return fmt.Errorf(static.MinValue, "SalePrice", 0)
+	}
+	if d.StartAt.IsZero() {
+		return fmt.Errorf(static.EmptyValue, "StartAt")
+	}
+	if !d.EndAt.After(d.StartAt) || !d.EndAt.After(time.Now()) {
+		return errors.New(static.SalePeriodInvalid)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type CancelSaleRequest struct {
+	ProductID int
+	SaleID    int
+	SellerID  int
+	RoleID    enum.RoleType
+}
+
+func (d *CancelSaleRequest) Validate() error {
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.SaleID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SaleID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type PriceHistoryRequest struct {
+	ProductID int
+}
+
+func (d *PriceHistoryRequest) Validate() error {
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	return nil
+}
+
+type PriceHistoryResponse struct {
+	Regular []*model.ProductPriceHistories
+	Sales   []*model.ProductSales
+}
+
+type DetailRequest struct {
+	ID       int
+	Currency string
+}
+
+func (d *DetailRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	return nil
+}
+
+type DetailResponse struct {
+	Product *model.Products
+	Store   *StoreResponse
+	Rating  *RatingResponse
+	Related []*model.Products
+}
+
+type StoreResponse struct {
+	ID            int
+	Username      string
+	RatingAverage float64
+	RatingCount   int
+	ProductCount  int64
+	Closed        bool
+	JoinedAt      time.Time
+}
+
+type RatingResponse struct {
+	Average      float64
+	Count        int
+	Distribution map[int]int
+}
+
+type FindAllRequest struct {
+	SellerID   int
+	Status     enum.ProductStatusType
+	Currency   string
+	CategoryID int
+	Attributes map[string]string
 }
 
 func (d *FindAllRequest) Validate() error {
 	if d.SellerID <= 0 {
 		return fmt.Errorf(static.EmptyValue, "SellerID")
 	}
+	if d.Status != 0 {
+		if err := d.Status.IsValid(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 type FindRequest model.Products
+
+type ImportRequest struct {
+	SellerID int
+	RoleID   enum.RoleType
+	DryRun   bool
+	Data     []byte
+}
+
+func (d *ImportRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if len(d.Data) == 0 {
+		return fmt.Errorf(static.EmptyValue, "File")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindImportRequest struct {
+	ID       int
+	SellerID int
+	RoleID   enum.RoleType
+}
+
+func (d *FindImportRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type ExportRequest struct {
+	SellerID int
+	RoleID   enum.RoleType
+}
+
+func (d *ExportRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/product/dto"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// Related product score, product bought together weigh more than the same category or seller
+const (
+	relatedLimit         = 8
+	relatedScorePurchase = 3
+	relatedScoreCategory = 2
+	relatedScoreSeller   = 1
+)
+
+// Detail published product along with its store, rating summary and related product
+func (l *ProductLogic) Detail(ctx context.Context, reqData *dto.DetailRequest) (*dto.DetailResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.ProductRepo.FindDetail(ctx, &model.Products{
+		ID:     reqData.ID,
+		Status: enum.ProductStatusTypePublished,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "produk"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	product.StatusProduct = product.Status.String()
+
+	store, err := l.store(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	// Seller returned as store
+	product.Seller = nil
+
+	distributions, err := l.ProductRepo.FindRatingDistribution(ctx, product.ID)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	rating := &dto.RatingResponse{
+		Average:      product.RatingAverage,
+		Count:        product.RatingCount,
+		Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+	}
+	for _, distribution := range distributions {
+		rating.Distribution[distribution.Rating] = distribution.Total
+	}
+
+	related, err := l.related(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	products := append([]*model.Products{product}, related...)
+	if err := l.ApplySale(ctx, products...); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	// Convert price into buyer display currency
+	if reqData.Currency != "" {
+		if err := l.convert(ctx, products, reqData.Currency); err != nil {
+			l.Logger.Error(err)
+			return nil, err
+		}
+	}
+
+	return &dto.DetailResponse{
+		Product: product,
+		Store:   store,
+		Rating:  rating,
+		Related: related,
+	}, nil
+}
+
+// store summary of product seller
+func (l *ProductLogic) store(ctx context.Context, product *model.Products) (*dto.StoreResponse, error) {
+	if product.Seller == nil {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "toko"), http.StatusNotFound)
+	}
+
+	count, err := l.ProductRepo.CountProduct(ctx, &model.Products{
+		SellerID: product.SellerID,
+		Status:   enum.ProductStatusTypePublished,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return &dto.StoreResponse{
+		ID:            product.Seller.ID,
+		Username:      product.Seller.Username,
+		RatingAverage: product.Seller.RatingAverage,
+		RatingCount:   product.Seller.RatingCount,
+		ProductCount:  count,
+		Closed:        product.Seller.StoreClosedAt != nil,
+		JoinedAt:      product.Seller.CreatedAt,
+	}, nil
+}
+
+// related rank product bought together, from the same category and from the same seller
+func (l *ProductLogic) related(ctx context.Context, product *model.Products) ([]*model.Products, error) {
+	frequencies, err := l.ProductRepo.FindCoPurchased(ctx, product.ID, relatedLimit*2)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	candidates, err := l.ProductRepo.FindRelated(ctx, product, relatedLimit*2)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	products := map[int]*model.Products{}
+	for _, candidate := range candidates {
+		products[candidate.ID] = candidate
+	}
+
+	purchased := map[int]int{}
+	missing := []int{}
+	for _, frequency := range frequencies {
+		purchased[frequency.ProductID] = frequency.Frequency
+		if _, ok := products[frequency.ProductID]; !ok {
+			missing = append(missing, frequency.ProductID)
+		}
+	}
+
+	if len(missing) > 0 {
+		bought, err := l.ProductRepo.FindByIDs(ctx, missing, enum.ProductStatusTypePublished)
+		if err != nil {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		for _, item := range bought {
+			products[item.ID] = item
+		}
+	}
+
+	scores := map[int]int{}
+	related := []*model.Products{}
+	for id, item := range products {
+		score := purchased[id] * relatedScorePurchase
+		if product.CategoryID != nil && item.CategoryID != nil && *product.CategoryID == *item.CategoryID {
+			score += relatedScoreCategory
+		}
+		if product.SellerID == item.SellerID {
+			score += relatedScoreSeller
+		}
+		scores[id] = score
+		item.StatusProduct = item.Status.String()
+		related = append(related, item)
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if scores[related[i].ID] != scores[related[j].ID] {
+			return scores[related[i].ID] > scores[related[j].ID]
+		}
+		return related[i].ID > related[j].ID
+	})
+
+	if len(related) > relatedLimit {
+		related = related[:relatedLimit]
+	}
+
+	return related, nil
+}
@@ -0,0 +1,372 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	categoryDto "pcstakehometest/module/category/dto"
+	inventoryDto "pcstakehometest/module/inventory/dto"
+	"pcstakehometest/module/product/dto"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// importProgressInterval save job progress every given rows
+const importProgressInterval = 100
+
+// Import validate csv header then upsert every row by sku in background
+func (l *ProductLogic) Import(ctx context.Context, reqData *dto.ImportRequest) (*model.ProductImportJobs, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(reqData.Data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.InvalidCSVValue, "CSV"), http.StatusBadRequest)
+	}
+
+	if len(records) == 0 {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.EmptyValue, "CSV"), http.StatusBadRequest)
+	}
+
+	columns, err := dto.ParseCSVHeader(records[0])
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	job := &model.ProductImportJobs{
+		SellerID:  reqData.SellerID,
+		Status:    enum.ImportJobStatusTypePending,
+		DryRun:    reqData.DryRun,
+		TotalRows: len(records) - 1,
+		Errors:    model.ImportErrors{},
+	}
+	if err := l.ProductRepo.CreateImportJob(ctx, job); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	// Request context canceled after response sent, job use its own context
+	go l.processImport(context.Background(), *job, columns, records[1:], reqData)
+
+	job.StatusJob = job.Status.String()
+
+	return job, nil
+}
+
+// FindImport
+func (l *ProductLogic) FindImport(ctx context.Context, reqData *dto.FindImportRequest) (*model.ProductImportJobs, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	job, err := l.ProductRepo.FindImportJob(ctx, &model.ProductImportJobs{
+		ID:       reqData.ID,
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "import"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	job.StatusJob = job.Status.String()
+
+	return job, nil
+}
+
+// Export
+func (l *ProductLogic) Export(ctx context.Context, reqData *dto.ExportRequest) ([]*model.Products, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	products, err := l.ProductRepo.FindAll(ctx, &model.Products{
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return products, nil
+}
+
+func (l *ProductLogic) processImport(ctx context.Context, job model.ProductImportJobs, columns dto.CSVColumns, records [][]string, reqData *dto.ImportRequest) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.Logger.Errorf("import job %d: %v", job.ID, r)
+			job.Status = enum.ImportJobStatusTypeFailed
+			l.finishImport(ctx, &job)
+		}
+	}()
+
+	job.Status = enum.ImportJobStatusTypeProcessing
+	if err := l.ProductRepo.UpdateImportJob(ctx, &job); err != nil {
+		l.Logger.Error(err)
+	}
+
+	seen := map[string]int{}
+	for i, record := range records {
+		// Row number follow spreadsheet numbering where header is the first row
+		row := i + 2
+
+		created, sku, err := l.importRow(ctx, columns, record, reqData, seen, row)
+		if err != nil {
+			job.FailedRows++
+			job.Errors = append(job.Errors, model.ImportRowError{
+				Row:   row,
+				SKU:   sku,
+				Error: err.Error(),
+			})
+		} else if created {
+			job.CreatedRows++
+		} else {
+			job.UpdatedRows++
+		}
+
+		if (i+1)%importProgressInterval == 0 {
+			if err := l.ProductRepo.UpdateImportJob(ctx, &job); err != nil {
+				l.Logger.Error(err)
+			}
+		}
+	}
+
+	job.Status = enum.ImportJobStatusTypeCompleted
+	l.finishImport(ctx, &job)
+}
+
+func (l *ProductLogic) finishImport(ctx context.Context, job *model.ProductImportJobs) {
+	now := time.Now()
+	job.FinishedAt = &now
+	if err := l.ProductRepo.UpdateImportJob(ctx, job); err != nil {
+		l.Logger.Error(err)
+	}
+}
+
+// importRow create or update single product, return true when product created
+func (l *ProductLogic) importRow(ctx context.Context, columns dto.CSVColumns, record []string, reqData *dto.ImportRequest, seen map[string]int, row int) (bool, string, error) {
+	createReq, err := columns.ParseCSVRecord(record)
+	if err != nil {
+		return false, createReq.SKU, err
+	}
+
+	createReq.SellerID = reqData.SellerID
+	createReq.RoleID = reqData.RoleID
+	if err := createReq.Validate(); err != nil {
+		return false, createReq.SKU, err
+	}
+
+	if createReq.Currency != "" {
+		if _, err := l.currency(ctx, createReq.Currency); err != nil {
+			return false, createReq.SKU, err
+		}
+	}
+
+	if previous, ok := seen[createReq.SKU]; ok {
+		return false, createReq.SKU, fmt.Errorf(static.DuplicateCSVRow, "SKU", previous)
+	}
+	seen[createReq.SKU] = row
+
+	if reqData.DryRun {
+		existing, err := l.ProductRepo.Find(ctx, &model.Products{
+			SKU:      createReq.SKU,
+			SellerID: reqData.SellerID,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return false, createReq.SKU, err
+		}
+		if err == gorm.ErrRecordNotFound {
+			existing = nil
+		}
+
+		if err := l.dryRun(ctx, existing, createReq); err != nil {
+			return false, createReq.SKU, err
+		}
+		return existing == nil, createReq.SKU, nil
+	}
+
+	ctx, outbox := notifier.WithOutbox(ctx)
+	tx := l.Database.Gorm.Begin()
+
+	// Locked within the row transaction so price and currency compared against the row being overwritten
+	existing, err := l.ProductRepo.FindForUpdate(ctx, &model.Products{
+		SKU:      createReq.SKU,
+		SellerID: reqData.SellerID,
+	}, tx)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		return false, createReq.SKU, err
+	}
+	if err == gorm.ErrRecordNotFound {
+		existing = nil
+		err = l.Create(ctx, createReq, tx)
+	} else {
+		err = l.update(ctx, existing, createReq, columns.HasStock(), tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, createReq.SKU, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, createReq.SKU, err
+	}
+
+	// Failed delivery should not fail the row already imported
+	if err := outbox.Send(ctx, l.Notifier); err != nil {
+		l.Logger.Error(err)
+	}
+
+	return existing == nil, createReq.SKU, nil
+}
+
+// dryRun check row the same way import would without writing it
+func (l *ProductLogic) dryRun(ctx context.Context, existing *model.Products, reqData *dto.CreateRequest) error {
+	if existing != nil {
+		_, err := l.prepareUpdate(ctx, existing, reqData, l.Database.Gorm)
+		return err
+	}
+
+	if _, err := lifecycle(reqData.Status, reqData.PublishAt); err != nil {
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	if _, err := l.CategoryLogic.ValidateAttributes(ctx, &categoryDto.AttributeRequest{
+		CategoryID: reqData.CategoryID,
+		Values:     reqData.Attributes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// update overwrite product detail and set stock to the imported value
+func (l *ProductLogic) update(ctx context.Context, product *model.Products, reqData *dto.CreateRequest, withStock bool, tx *gorm.DB) error {
+	updated, err := l.prepareUpdate(ctx, product, reqData, tx)
+	if err != nil {
+		return err
+	}
+	currency := updated.Currency
+
+	if err := l.ProductRepo.Update(ctx, updated, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if currency != product.Currency || reqData.Price.Cmp(product.Price) != 0 {
+		if err := l.recordPrice(ctx, &model.Products{
+			ID:       product.ID,
+			Price:    reqData.Price,
+			Currency: currency,
+		}, reqData.SellerID, tx); err != nil {
+			return err
+		}
+	}
+
+	// Notify buyer who wishlisted the product when the price they pay goes down, running sale included
+	if err := l.ApplySale(ctx, product); err != nil {
+		return err
+	}
+	price := (&model.Products{Price: reqData.Price, SalePrice: product.SalePrice}).EffectivePrice()
+	if product.Status == enum.ProductStatusTypePublished && currency == product.Currency && price.Cmp(product.EffectivePrice()) < 0 {
+		if err := l.WishlistLogic.NotifyPriceDrop(ctx, &model.Products{
+			ID:       product.ID,
+			Name:     reqData.Name,
+			Price:    price,
+			Currency: currency,
+		}, tx); err != nil {
+			return err
+		}
+	}
+
+	if withStock {
+		if _, err := l.InventoryLogic.SetStock(ctx, &inventoryDto.SetStockRequest{
+			ProductID: product.ID,
+			Stock:     reqData.Stock,
+			Note:      "import CSV",
+			SellerID:  reqData.SellerID,
+			RoleID:    reqData.RoleID,
+		}, tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareUpdate validate row against current product and return what it is overwritten with
+func (l *ProductLogic) prepareUpdate(ctx context.Context, product *model.Products, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Products, error) {
+	currency := product.Currency
+	if reqData.Currency != "" {
+		var err error
+		if currency, err = l.currency(ctx, reqData.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sale price set in the current currency, seller cancel running and upcoming sale before switching
+	if currency != product.Currency {
+		count, err := l.ProductRepo.CountUnendedSale(ctx, &model.ProductSales{
+			ProductID: product.ID,
+			EndAt:     time.Now(),
+		}, tx)
+		if err != nil {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		if count > 0 {
+			return nil, utilities.ErrorRequest(errors.New(static.SaleCurrencyLock), http.StatusConflict)
+		}
+	}
+
+	// Keep current category and attributes unless request replace them, e.g. CSV import
+	categoryID, attributes := product.CategoryID, product.Attributes
+	if reqData.CategoryID > 0 || reqData.Attributes != nil {
+		id := reqData.CategoryID
+		if id <= 0 && product.CategoryID != nil {
+			id = *product.CategoryID
+		}
+
+		var err error
+		attributes, err = l.CategoryLogic.ValidateAttributes(ctx, &categoryDto.AttributeRequest{
+			CategoryID: id,
+			Values:     reqData.Attributes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		categoryID = category(id)
+	}
+
+	return &model.Products{
+		ID:          product.ID,
+		SellerID:    product.SellerID,
+		Name:        reqData.Name,
+		Description: reqData.Description,
+		Price:       reqData.Price,
+		Currency:    currency,
+		CategoryID:  categoryID,
+		Attributes:  attributes,
+	}, nil
+}
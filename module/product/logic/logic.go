@@ -2,15 +2,29 @@ package logic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 	"gorm.io/gorm"
+	"pcstakehometest/config"
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
 	"pcstakehometest/model"
+	categoryDto "pcstakehometest/module/category/dto"
+	categoryLogic "pcstakehometest/module/category/logic"
+	inventoryDto "pcstakehometest/module/inventory/dto"
+	inventoryLogic "pcstakehometest/module/inventory/logic"
 	"pcstakehometest/module/product/dto"
 	"pcstakehometest/module/product/repository"
+	wishlistLogic "pcstakehometest/module/wishlist/logic"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/exchange"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/notifier"
 	"pcstakehometest/static"
 	"pcstakehometest/utilities"
 )
@@ -20,12 +34,31 @@ type IProductLogic interface {
 	Create(context.Context, *dto.CreateRequest, *gorm.DB) error
 	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Products, error)
 	Find(context.Context, *dto.FindRequest) (*model.Products, error)
+	Detail(context.Context, *dto.DetailRequest) (*dto.DetailResponse, error)
+	Update(context.Context, *dto.UpdateRequest, *gorm.DB) error
+	Delete(context.Context, *dto.DeleteRequest, *gorm.DB) error
+	UpdateStatus(context.Context, *dto.StatusRequest, *gorm.DB) error
+	PublishScheduled(context.Context) error
+	CreateSale(context.Context, *dto.SaleRequest, *gorm.DB) (*model.ProductSales, error)
+	CancelSale(context.Context, *dto.CancelSaleRequest, *gorm.DB) error
+	PriceHistory(context.Context, *dto.PriceHistoryRequest) (*dto.PriceHistoryResponse, error)
+	ApplySale(context.Context, ...*model.Products) error
+	NotifySalePriceDrop(context.Context) error
+	Import(context.Context, *dto.ImportRequest) (*model.ProductImportJobs, error)
+	FindImport(context.Context, *dto.FindImportRequest) (*model.ProductImportJobs, error)
+	Export(context.Context, *dto.ExportRequest) ([]*model.Products, error)
 }
 
 type ProductLogic struct {
 	fx.In
-	Logger      *logger.LogRus
-	ProductRepo repository.ISellerRepository
+	Logger         *logger.LogRus
+	Database       *postgres.DB
+	Exchange       exchange.Provider
+	Notifier       notifier.Notifier
+	ProductRepo    repository.ISellerRepository
+	InventoryLogic inventoryLogic.IInventoryLogic
+	WishlistLogic  wishlistLogic.IWishlistLogic
+	CategoryLogic  categoryLogic.ICategoryLogic
 }
 
 // NewLogic :
@@ -41,15 +74,77 @@ func (l *ProductLogic) Create(ctx context.Context, reqData *dto.CreateRequest, t
 		return utilities.ErrorRequest(err, http.StatusBadRequest)
 	}
 
-	if _, err := l.ProductRepo.Create(ctx, &model.Products{
+	currency, err := l.currency(ctx, reqData.Currency)
+	if err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	state, err := lifecycle(reqData.Status, reqData.PublishAt)
+	if err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	attributes, err := l.CategoryLogic.ValidateAttributes(ctx, &categoryDto.AttributeRequest{
+		CategoryID: reqData.CategoryID,
+		Values:     reqData.Attributes,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	// SKU must be unique per seller
+	if reqData.SKU != "" {
+		_, err := l.ProductRepo.Find(ctx, &model.Products{
+			SKU:      reqData.SKU,
+			SellerID: reqData.SellerID,
+		})
+		if err == nil {
+			return utilities.ErrorRequest(fmt.Errorf(static.AlreadyExist, "SKU"), http.StatusBadRequest)
+		}
+		if err != gorm.ErrRecordNotFound {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	}
+
+	product := &model.Products{
+		SKU:         reqData.SKU,
 		SellerID:    reqData.SellerID,
 		Name:        reqData.Name,
 		Description: reqData.Description,
 		Price:       reqData.Price,
-	}, tx); err != nil {
+		Currency:    currency,
+		CategoryID:  category(reqData.CategoryID),
+		Attributes:  attributes,
+		Status:      state.Status,
+		PublishAt:   state.PublishAt,
+		PublishedAt: state.PublishedAt,
+	}
+	productID, err := l.ProductRepo.Create(ctx, product, tx)
+	if err != nil {
 		return utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
 
+	if err := l.recordPrice(ctx, product, reqData.SellerID, tx); err != nil {
+		return err
+	}
+
+	// Record initial stock in inventory ledger
+	if reqData.Stock > 0 {
+		if _, err := l.InventoryLogic.Adjust(ctx, &inventoryDto.AdjustRequest{
+			ProductID: *productID,
+			Quantity:  reqData.Stock,
+			Note:      "stok awal",
+			SellerID:  reqData.SellerID,
+			RoleID:    reqData.RoleID,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -61,8 +156,16 @@ func (l *ProductLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest)
 		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
 	}
 
+	filter := attribute.Values{}
+	for key, value := range reqData.Attributes {
+		filter[key] = value
+	}
+
 	products, err := l.ProductRepo.FindAll(ctx, &model.Products{
-		SellerID: reqData.SellerID,
+		SellerID:   reqData.SellerID,
+		Status:     reqData.Status,
+		CategoryID: category(reqData.CategoryID),
+		Attributes: filter,
 	})
 	if err != nil {
 		l.Logger.Error(err)
@@ -72,6 +175,23 @@ func (l *ProductLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest)
 		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
 
+	for _, product := range products {
+		product.StatusProduct = product.Status.String()
+	}
+
+	if err := l.ApplySale(ctx, products...); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	// Convert price into buyer display currency
+	if reqData.Currency != "" {
+		if err := l.convert(ctx, products, reqData.Currency); err != nil {
+			l.Logger.Error(err)
+			return nil, err
+		}
+	}
+
 	return products, nil
 }
 
@@ -80,6 +200,7 @@ func (l *ProductLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*mod
 	product, err := l.ProductRepo.Find(ctx, &model.Products{
 		ID:       reqData.ID,
 		SellerID: reqData.SellerID,
+		Status:   reqData.Status,
 	})
 	if err != nil {
 		l.Logger.Error(err)
@@ -88,5 +209,196 @@ func (l *ProductLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*mod
 		}
 		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
+
+	if err := l.ApplySale(ctx, product); err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Update
+func (l *ProductLogic) Update(ctx context.Context, reqData *dto.UpdateRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	// Locked within the transaction so price and currency compared against the row being overwritten
+	product, err := l.ProductRepo.FindForUpdate(ctx, &model.Products{
+		ID:       reqData.ID,
+		SellerID: reqData.SellerID,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "product"), http.StatusNotFound)
+		}
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return l.update(ctx, product, &dto.CreateRequest{
+		Name:        reqData.Name,
+		Description: reqData.Description,
+		Price:       reqData.Price,
+		Currency:    reqData.Currency,
+		CategoryID:  reqData.CategoryID,
+		Attributes:  reqData.Attributes,
+		SellerID:    reqData.SellerID,
+		RoleID:      reqData.RoleID,
+	}, false, tx)
+}
+
+// Delete
+func (l *ProductLogic) Delete(ctx context.Context, reqData *dto.DeleteRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.Find(ctx, &dto.FindRequest{
+		ID:       reqData.ID,
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := l.ProductRepo.Delete(ctx, product, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// UpdateStatus move product through draft, scheduled, published and archived
+func (l *ProductLogic) UpdateStatus(ctx context.Context, reqData *dto.StatusRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.Find(ctx, &dto.FindRequest{
+		ID:       reqData.ID,
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	state, err := lifecycle(reqData.Status, reqData.PublishAt)
+	if err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	// Publishing already published product keep its original publish time
+	if product.Status == enum.ProductStatusTypePublished && state.Status == enum.ProductStatusTypePublished {
+		state.PublishedAt = nil
+	}
+
+	state.ID = product.ID
+	state.SellerID = product.SellerID
+	if err := l.ProductRepo.UpdateStatus(ctx, state, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// PublishScheduled publish scheduled product once its publish time reached
+func (l *ProductLogic) PublishScheduled(ctx context.Context) error {
+	count, err := l.ProductRepo.PublishScheduled(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		l.Logger.Infof("published %d scheduled product", count)
+	}
+
+	return nil
+}
+
+// lifecycle resolve product status and its publish time, product without status published immediately
+func lifecycle(status enum.ProductStatusType, publishAt *time.Time) (*model.Products, error) {
+	if status == 0 {
+		status = enum.ProductStatusTypePublished
+		if publishAt != nil {
+			status = enum.ProductStatusTypeScheduled
+		}
+	}
+
+	product := &model.Products{
+		Status: status,
+	}
+
+	switch status {
+	case enum.ProductStatusTypeScheduled:
+		if publishAt == nil {
+			return nil, fmt.Errorf(static.EmptyValue, "PublishAt")
+		}
+		if !publishAt.After(time.Now()) {
+			return nil, errors.New(static.PublishAtPast)
+		}
+		product.PublishAt = publishAt
+	case enum.ProductStatusTypePublished:
+		now := time.Now()
+		product.PublishedAt = &now
+	}
+
 	return product, nil
 }
+
+// category product without category stored as null
+func category(categoryID int) *int {
+	if categoryID <= 0 {
+		return nil
+	}
+	return &categoryID
+}
+
+// currency normalize product currency and make sure exchange rate available
+func (l *ProductLogic) currency(ctx context.Context, currency string) (string, error) {
+	base := config.Get().Currency.Base
+	if currency == "" {
+		return base, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if _, err := l.Exchange.Rate(ctx, base, currency); err != nil {
+		l.Logger.Error(err)
+		return "", utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, currency), http.StatusBadRequest)
+	}
+
+	return currency, nil
+}
+
+// convert fill display price of every product from the price buyer pay
+func (l *ProductLogic) convert(ctx context.Context, products []*model.Products, currency string) error {
+	currency = strings.ToUpper(currency)
+	rates := map[string]*exchange.Rate{}
+
+	for _, product := range products {
+		rate, ok := rates[product.Currency]
+		if !ok {
+			var err error
+			rate, err = l.Exchange.Rate(ctx, product.Currency, currency)
+			if err != nil {
+				l.Logger.Error(err)
+				return utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, currency), http.StatusBadRequest)
+			}
+			rates[product.Currency] = rate
+		}
+
+		displayPrice := product.EffectivePrice().Convert(rate.Value)
+		product.DisplayPrice = &displayPrice
+		product.DisplayCurrency = currency
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/product/dto"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// CreateSale schedule sale price for a period
+func (l *ProductLogic) CreateSale(ctx context.Context, reqData *dto.SaleRequest, tx *gorm.DB) (*model.ProductSales, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.Find(ctx, &dto.FindRequest{
+		ID:       reqData.ProductID,
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reqData.SalePrice.Cmp(product.Price) >= 0 {
+		return nil, utilities.ErrorRequest(errors.New(static.SalePriceTooHigh), http.StatusBadRequest)
+	}
+
+	sale := &model.ProductSales{
+		ProductID: product.ID,
+		SellerID:  product.SellerID,
+		SalePrice: reqData.SalePrice,
+		StartAt:   reqData.StartAt,
+		EndAt:     reqData.EndAt,
+	}
+
+	// Only one sale can run at a time
+	count, err := l.ProductRepo.CountOverlapSale(ctx, sale, tx)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	if count > 0 {
+		return nil, utilities.ErrorRequest(errors.New(static.SaleOverlap), http.StatusBadRequest)
+	}
+
+	if _, err := l.ProductRepo.CreateSale(ctx, sale, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return sale, nil
+}
+
+// CancelSale remove upcoming sale or end running sale now, so sale that ever ran stay in history
+func (l *ProductLogic) CancelSale(ctx context.Context, reqData *dto.CancelSaleRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	sale, err := l.ProductRepo.FindSale(ctx, &model.ProductSales{
+		ID:        reqData.SaleID,
+		ProductID: reqData.ProductID,
+		SellerID:  reqData.SellerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "promo"), http.StatusNotFound)
+		}
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+	switch {
+	case sale.StartAt.After(now):
+		if err := l.ProductRepo.DeleteSale(ctx, sale, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	case sale.EndAt.After(now):
+		sale.EndAt = now
+		if err := l.ProductRepo.UpdateSaleEnd(ctx, sale, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+	default:
+		return utilities.ErrorRequest(errors.New(static.SaleEnded), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// PriceHistory list every regular price change and sale of product
+func (l *ProductLogic) PriceHistory(ctx context.Context, reqData *dto.PriceHistoryRequest) (*dto.PriceHistoryResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.Find(ctx, &dto.FindRequest{
+		ID: reqData.ProductID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := l.ProductRepo.FindPriceHistories(ctx, &model.ProductPriceHistories{
+		ProductID: product.ID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	sales, err := l.ProductRepo.FindSales(ctx, &model.ProductSales{
+		ProductID: product.ID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return &dto.PriceHistoryResponse{
+		Regular: histories,
+		Sales:   sales,
+	}, nil
+}
+
+// recordPrice append regular price into price history
+func (l *ProductLogic) recordPrice(ctx context.Context, product *model.Products, changedBy int, tx *gorm.DB) error {
+	if err := l.ProductRepo.CreatePriceHistory(ctx, &model.ProductPriceHistories{
+		ProductID: product.ID,
+		Price:     product.Price,
+		Currency:  product.Currency,
+		ChangedBy: changedBy,
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ApplySale fill sale price of product with running sale
+func (l *ProductLogic) ApplySale(ctx context.Context, products ...*model.Products) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	sales, err := l.ProductRepo.FindActiveSales(ctx, productIDs, time.Now())
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	running := map[int]*model.ProductSales{}
+	for _, sale := range sales {
+		running[sale.ProductID] = sale
+	}
+
+	for _, product := range products {
+		if sale, ok := running[product.ID]; ok {
+			product.SalePrice = &sale.SalePrice
+			product.SaleEndAt = &sale.EndAt
+		}
+	}
+
+	return nil
+}
+
+// NotifySalePriceDrop notify buyer who wishlisted a product once its running sale drop the price they pay
+func (l *ProductLogic) NotifySalePriceDrop(ctx context.Context) error {
+	sales, err := l.ProductRepo.FindRunningSales(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, sale := range sales {
+		product, err := l.ProductRepo.Find(ctx, &model.Products{
+			ID:     sale.ProductID,
+			Status: enum.ProductStatusTypePublished,
+		})
+		if err == gorm.ErrRecordNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		product.SalePrice = &sale.SalePrice
+
+		// Wishlist already notified for this price is skipped, so running sale notify once
+		ctx, outbox := notifier.WithOutbox(ctx)
+		tx := l.Database.Gorm.Begin()
+		if err := l.WishlistLogic.NotifyPriceDrop(ctx, &model.Products{
+			ID:       product.ID,
+			Name:     product.Name,
+			Price:    product.EffectivePrice(),
+			Currency: product.Currency,
+		}, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit().Error; err != nil {
+			l.Logger.Error(err)
+			return err
+		}
+
+		if err := outbox.Send(ctx, l.Notifier); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+
+	return nil
+}
@@ -2,13 +2,17 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
 	"pcstakehometest/model"
 	"pcstakehometest/package/logger"
 
 	"go.uber.org/fx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SellerRepository
@@ -16,6 +20,31 @@ type ISellerRepository interface {
 	Create(context.Context, *model.Products, *gorm.DB) (*int, error)
 	FindAll(context.Context, *model.Products) ([]*model.Products, error)
 	Find(context.Context, *model.Products) (*model.Products, error)
+	FindForUpdate(context.Context, *model.Products, *gorm.DB) (*model.Products, error)
+	FindDetail(context.Context, *model.Products) (*model.Products, error)
+	FindByIDs(context.Context, []int, enum.ProductStatusType) ([]*model.Products, error)
+	FindRelated(context.Context, *model.Products, int) ([]*model.Products, error)
+	FindCoPurchased(context.Context, int, int) ([]*model.ProductFrequency, error)
+	FindRatingDistribution(context.Context, int) ([]*model.RatingDistribution, error)
+	CountProduct(context.Context, *model.Products) (int64, error)
+	Update(context.Context, *model.Products, *gorm.DB) error
+	Delete(context.Context, *model.Products, *gorm.DB) error
+	UpdateStatus(context.Context, *model.Products, *gorm.DB) error
+	PublishScheduled(context.Context, time.Time) (int64, error)
+	CreatePriceHistory(context.Context, *model.ProductPriceHistories, *gorm.DB) error
+	FindPriceHistories(context.Context, *model.ProductPriceHistories) ([]*model.ProductPriceHistories, error)
+	CreateSale(context.Context, *model.ProductSales, *gorm.DB) (*int, error)
+	FindSales(context.Context, *model.ProductSales) ([]*model.ProductSales, error)
+	FindSale(context.Context, *model.ProductSales) (*model.ProductSales, error)
+	CountOverlapSale(context.Context, *model.ProductSales, *gorm.DB) (int64, error)
+	CountUnendedSale(context.Context, *model.ProductSales, *gorm.DB) (int64, error)
+	FindActiveSales(context.Context, []int, time.Time) ([]*model.ProductSales, error)
+	FindRunningSales(context.Context, time.Time) ([]*model.ProductSales, error)
+	UpdateSaleEnd(context.Context, *model.ProductSales, *gorm.DB) error
+	DeleteSale(context.Context, *model.ProductSales, *gorm.DB) error
+	CreateImportJob(context.Context, *model.ProductImportJobs) error
+	UpdateImportJob(context.Context, *model.ProductImportJobs) error
+	FindImportJob(context.Context, *model.ProductImportJobs) (*model.ProductImportJobs, error)
 }
 
 type SellerRepository struct {
@@ -42,11 +71,23 @@ func (l *SellerRepository) Create(ctx context.Context, reqData *model.Products,
 func (l *SellerRepository) FindAll(ctx context.Context, reqData *model.Products) ([]*model.Products, error) {
 	products := []*model.Products{}
 
-	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
+	query := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
 		Preload("Seller").
-		Where(&model.Products{
-			SellerID: reqData.SellerID,
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
 		}).
+		Where(&model.Products{
+			SellerID:   reqData.SellerID,
+			Status:     reqData.Status,
+			CategoryID: reqData.CategoryID,
+		})
+
+	// Attribute filter compare text value, e.g. attr.color=red
+	for key, value := range reqData.Attributes {
+		query = query.Where("lower(attributes ->> ?) = lower(?)", key, fmt.Sprint(value))
+	}
+
+	if err := query.
 		Order("id desc").
 		Find(&products).
 		Error; err != nil {
@@ -61,8 +102,27 @@ func (l *SellerRepository) FindAll(ctx context.Context, reqData *model.Products)
 func (l *SellerRepository) Find(ctx context.Context, reqData *model.Products) (*model.Products, error) {
 	product := new(model.Products)
 	if err := l.Database.Gorm.WithContext(ctx).
+		Preload("Category").
+		Where(&model.Products{
+			ID:       reqData.ID,
+			SKU:      reqData.SKU,
+			SellerID: reqData.SellerID,
+			Status:   reqData.Status,
+		}).First(&product).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return product, nil
+}
+
+// FindForUpdate lock product so it is compared and overwritten by one transaction at a time
+func (l *SellerRepository) FindForUpdate(ctx context.Context, reqData *model.Products, tx *gorm.DB) (*model.Products, error) {
+	product := new(model.Products)
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where(&model.Products{
 			ID:       reqData.ID,
+			SKU:      reqData.SKU,
 			SellerID: reqData.SellerID,
 		}).First(&product).Error; err != nil {
 		l.Logger.Error(err)
@@ -70,3 +130,396 @@ func (l *SellerRepository) Find(ctx context.Context, reqData *model.Products) (*
 	}
 	return product, nil
 }
+
+// FindDetail find product along with its seller, category and images
+func (l *SellerRepository) FindDetail(ctx context.Context, reqData *model.Products) (*model.Products, error) {
+	product := new(model.Products)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Preload("Seller").
+		Preload("Category").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
+		}).
+		Where(&model.Products{
+			ID:     reqData.ID,
+			Status: reqData.Status,
+		}).First(&product).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return product, nil
+}
+
+// FindByIDs
+func (l *SellerRepository) FindByIDs(ctx context.Context, ids []int, status enum.ProductStatusType) ([]*model.Products, error) {
+	products := []*model.Products{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
+		}).
+		Where("id in ?", ids).
+		Where(&model.Products{
+			Status: status,
+		}).
+		Find(&products).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindRelated find published product from the same seller or the same category, newest first
+func (l *SellerRepository) FindRelated(ctx context.Context, reqData *model.Products, limit int) ([]*model.Products, error) {
+	products := []*model.Products{}
+
+	query := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
+		}).
+		Where("id <> ?", reqData.ID).
+		Where("status = ?", enum.ProductStatusTypePublished)
+
+	if reqData.CategoryID != nil {
+		query = query.Where("seller_id = ? or category_id = ?", reqData.SellerID, *reqData.CategoryID)
+	} else {
+		query = query.Where("seller_id = ?", reqData.SellerID)
+	}
+
+	if err := query.
+		Order("id desc").
+		Limit(limit).
+		Find(&products).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindCoPurchased count other product bought in the same transaction as the given product
+func (l *SellerRepository) FindCoPurchased(ctx context.Context, productID int, limit int) ([]*model.ProductFrequency, error) {
+	frequencies := []*model.ProductFrequency{}
+
+	if err := l.Database.Gorm.WithContext(ctx).
+		Table("transactions, json_array_elements(transactions.items) as item").
+		Select("(item ->> 'ID')::int as product_id, count(distinct transactions.id) as frequency").
+		Where("transactions.deleted_at is null").
+		Where("transactions.items::jsonb @> ?::jsonb", fmt.Sprintf(`[{"ID":%d}]`, productID)).
+		Where("(item ->> 'ID')::int <> ?", productID).
+		Group("product_id").
+		Order("frequency desc").
+		Limit(limit).
+		Scan(&frequencies).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return frequencies, nil
+}
+
+// FindRatingDistribution count published review of product per rating
+func (l *SellerRepository) FindRatingDistribution(ctx context.Context, productID int) ([]*model.RatingDistribution, error) {
+	distributions := []*model.RatingDistribution{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Reviews{}).
+		Select("rating, count(*) as total").
+		Where("product_id = ?", productID).
+		Where("status = ?", enum.ReviewStatusTypePublished).
+		Group("rating").
+		Scan(&distributions).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return distributions, nil
+}
+
+// CountProduct
+func (l *SellerRepository) CountProduct(ctx context.Context, reqData *model.Products) (int64, error) {
+	var count int64
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
+		Where(&model.Products{
+			SellerID: reqData.SellerID,
+			Status:   reqData.Status,
+		}).
+		Count(&count).Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update
+func (l *SellerRepository) Update(ctx context.Context, reqData *model.Products, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Products{}).
+		Where("id = ?", reqData.ID).
+		Where("seller_id = ?", reqData.SellerID).
+		Updates(map[string]interface{}{
+			"name":        reqData.Name,
+			"description": reqData.Description,
+			"price":       reqData.Price,
+			"currency":    reqData.Currency,
+			"category_id": reqData.CategoryID,
+			"attributes":  reqData.Attributes,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Delete
+func (l *SellerRepository) Delete(ctx context.Context, reqData *model.Products, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Where("seller_id = ?", reqData.SellerID).
+		Delete(&model.Products{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateStatus keep previous published time unless product published again
+func (l *SellerRepository) UpdateStatus(ctx context.Context, reqData *model.Products, tx *gorm.DB) error {
+	values := map[string]interface{}{
+		"status":     reqData.Status,
+		"publish_at": reqData.PublishAt,
+		"updated_at": time.Now(),
+	}
+	if reqData.PublishedAt != nil {
+		values["published_at"] = reqData.PublishedAt
+	}
+
+	if err := tx.WithContext(ctx).Model(&model.Products{}).
+		Where("id = ?", reqData.ID).
+		Where("seller_id = ?", reqData.SellerID).
+		Updates(values).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// PublishScheduled publish every scheduled product whose publish time has passed
+func (l *SellerRepository) PublishScheduled(ctx context.Context, now time.Time) (int64, error) {
+	result := l.Database.Gorm.WithContext(ctx).Model(&model.Products{}).
+		Where("status = ?", enum.ProductStatusTypeScheduled).
+		Where("publish_at <= ?", now).
+		Updates(map[string]interface{}{
+			"status":       enum.ProductStatusTypePublished,
+			"published_at": gorm.Expr("publish_at"),
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CreatePriceHistory
+func (l *SellerRepository) CreatePriceHistory(ctx context.Context, reqData *model.ProductPriceHistories, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindPriceHistories
+func (l *SellerRepository) FindPriceHistories(ctx context.Context, reqData *model.ProductPriceHistories) ([]*model.ProductPriceHistories, error) {
+	histories := []*model.ProductPriceHistories{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductPriceHistories{}).
+		Where(&model.ProductPriceHistories{
+			ProductID: reqData.ProductID,
+		}).
+		Order("created_at asc").
+		Order("id asc").
+		Find(&histories).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// CreateSale
+func (l *SellerRepository) CreateSale(ctx context.Context, reqData *model.ProductSales, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindSales
+func (l *SellerRepository) FindSales(ctx context.Context, reqData *model.ProductSales) ([]*model.ProductSales, error) {
+	sales := []*model.ProductSales{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductSales{}).
+		Where(&model.ProductSales{
+			ProductID: reqData.ProductID,
+		}).
+		Order("start_at asc").
+		Find(&sales).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// FindSale
+func (l *SellerRepository) FindSale(ctx context.Context, reqData *model.ProductSales) (*model.ProductSales, error) {
+	sale := new(model.ProductSales)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.ProductSales{
+			ID:        reqData.ID,
+			ProductID: reqData.ProductID,
+			SellerID:  reqData.SellerID,
+		}).First(&sale).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return sale, nil
+}
+
+// CountOverlapSale count sale of the product whose period intersect the given period
+func (l *SellerRepository) CountOverlapSale(ctx context.Context, reqData *model.ProductSales, tx *gorm.DB) (int64, error) {
+	var count int64
+	if err := tx.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("product_id = ?", reqData.ProductID).
+		Where("start_at < ?", reqData.EndAt).
+		Where("end_at > ?", reqData.StartAt).
+		Count(&count).Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountUnendedSale count sale of the product running or starting after EndAt
+func (l *SellerRepository) CountUnendedSale(ctx context.Context, reqData *model.ProductSales, tx *gorm.DB) (int64, error) {
+	var count int64
+	if err := tx.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("product_id = ?", reqData.ProductID).
+		Where("end_at > ?", reqData.EndAt).
+		Count(&count).Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindActiveSales find sale running at the given time
+func (l *SellerRepository) FindActiveSales(ctx context.Context, productIDs []int, now time.Time) ([]*model.ProductSales, error) {
+	sales := []*model.ProductSales{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("product_id in ?", productIDs).
+		Where("start_at <= ?", now).
+		Where("end_at > ?", now).
+		Find(&sales).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// FindRunningSales find every sale running at the given time
+func (l *SellerRepository) FindRunningSales(ctx context.Context, now time.Time) ([]*model.ProductSales, error) {
+	sales := []*model.ProductSales{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("start_at <= ?", now).
+		Where("end_at > ?", now).
+		Order("id asc").
+		Find(&sales).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// UpdateSaleEnd
+func (l *SellerRepository) UpdateSaleEnd(ctx context.Context, reqData *model.ProductSales, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"end_at":     reqData.EndAt,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteSale
+func (l *SellerRepository) DeleteSale(ctx context.Context, reqData *model.ProductSales, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Delete(&model.ProductSales{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateImportJob
+func (l *SellerRepository) CreateImportJob(ctx context.Context, reqData *model.ProductImportJobs) error {
+	if err := l.Database.Gorm.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateImportJob
+func (l *SellerRepository) UpdateImportJob(ctx context.Context, reqData *model.ProductImportJobs) error {
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductImportJobs{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"status":       reqData.Status,
+			"total_rows":   reqData.TotalRows,
+			"created_rows": reqData.CreatedRows,
+			"updated_rows": reqData.UpdatedRows,
+			"failed_rows":  reqData.FailedRows,
+			"errors":       reqData.Errors,
+			"finished_at":  reqData.FinishedAt,
+			"updated_at":   time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindImportJob
+func (l *SellerRepository) FindImportJob(ctx context.Context, reqData *model.ProductImportJobs) (*model.ProductImportJobs, error) {
+	job := new(model.ProductImportJobs)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.ProductImportJobs{
+			ID:       reqData.ID,
+			SellerID: reqData.SellerID,
+		}).First(&job).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return job, nil
+}
@@ -1,8 +1,11 @@
 package route
 
 import (
+	"encoding/csv"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"pcstakehometest/database/postgres"
 	"pcstakehometest/enum"
@@ -36,6 +39,16 @@ func (h *Handler) Route(m ...echo.MiddlewareFunc) {
 	product.POST("", h.Create, h.EchoRoute.Authentication)
 	product.GET("", h.FindAll, h.EchoRoute.Authentication)
 	product.GET("/list", h.FindAllForBuyer, h.EchoRoute.Authentication)
+	product.POST("/import", h.Import, h.EchoRoute.Authentication)
+	product.GET("/import/:id", h.FindImport, h.EchoRoute.Authentication)
+	product.GET("/export", h.Export, h.EchoRoute.Authentication)
+	product.GET("/:id", h.Detail)
+	product.PUT("/:id", h.Update, h.EchoRoute.Authentication)
+	product.DELETE("/:id", h.Delete, h.EchoRoute.Authentication)
+	product.PUT("/:id/status", h.UpdateStatus, h.EchoRoute.Authentication)
+	product.POST("/:id/sale", h.CreateSale, h.EchoRoute.Authentication)
+	product.DELETE("/:id/sale/:saleId", h.CancelSale, h.EchoRoute.Authentication)
+	product.GET("/:id/price-history", h.PriceHistory, h.EchoRoute.Authentication)
 }
 
 // FindAllForBuyer
@@ -51,12 +64,18 @@ func (h *Handler) FindAllForBuyer(c echo.Context) error {
 
 	if err := echo.QueryParamsBinder(c).
 		Int("seller", (&reqData.SellerID)).
+		String("currency", (&reqData.Currency)).
+		Int("category", (&reqData.CategoryID)).
 		BindError(); err != nil {
 		h.Logger.Error(err)
 		return utilities.Response(c, &utilities.ResponseRequest{
 			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
 		})
 	}
+	reqData.Attributes = attributeFilter(c)
+
+	// Buyer only see published product
+	reqData.Status = enum.ProductStatusTypePublished
 
 	resp, err := h.Logic.FindAll(c.Request().Context(), reqData)
 	if err != nil {
@@ -73,6 +92,43 @@ func (h *Handler) FindAllForBuyer(c echo.Context) error {
 	})
 }
 
+// Detail public product detail, no login required
+func (h *Handler) Detail(c echo.Context) error {
+	var reqData = new(dto.DetailRequest)
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	if err := echo.QueryParamsBinder(c).
+		String("currency", (&reqData.Currency)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.Detail(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
 // FindAll
 func (h *Handler) FindAll(c echo.Context) error {
 	var reqData = new(dto.FindAllRequest)
@@ -85,6 +141,19 @@ func (h *Handler) FindAll(c echo.Context) error {
 	}
 	reqData.SellerID = data.UserID
 
+	var status int
+	if err := echo.QueryParamsBinder(c).
+		Int("status", (&status)).
+		Int("category", (&reqData.CategoryID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+	reqData.Status = enum.ProductStatusType(status)
+	reqData.Attributes = attributeFilter(c)
+
 	resp, err := h.Logic.FindAll(c.Request().Context(), reqData)
 	if err != nil {
 		h.Logger.Error(err)
@@ -138,3 +207,429 @@ func (h *Handler) Create(c echo.Context) error {
 		Status: static.Success,
 	})
 }
+
+// Import
+func (h *Handler) Import(c echo.Context) error {
+	var reqData = new(dto.ImportRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	if err := echo.QueryParamsBinder(c).
+		Bool("dryRun", (&reqData.DryRun)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	// Accept csv as multipart file or as raw request body
+	body := c.Request().Body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			h.Logger.Error(err)
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+			})
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			h.Logger.Error(err)
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+			})
+		}
+		defer src.Close()
+		body = src
+	}
+
+	var err error
+	reqData.Data, err = io.ReadAll(body)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.Import(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusAccepted,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindImport
+func (h *Handler) FindImport(c echo.Context) error {
+	var reqData = new(dto.FindImportRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindImport(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Export
+func (h *Handler) Export(c echo.Context) error {
+	var reqData = new(dto.ExportRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	products, err := h.Logic.Export(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="products.csv"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	if err := writer.Write(dto.CSVHeader); err != nil {
+		h.Logger.Error(err)
+		return err
+	}
+	for _, product := range products {
+		if err := writer.Write(dto.ToCSVRecord(product)); err != nil {
+			h.Logger.Error(err)
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Update
+func (h *Handler) Update(c echo.Context) error {
+	var reqData = new(dto.UpdateRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Update(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// Delete
+func (h *Handler) Delete(c echo.Context) error {
+	var reqData = new(dto.DeleteRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.Delete(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// UpdateStatus
+func (h *Handler) UpdateStatus(c echo.Context) error {
+	var reqData = new(dto.StatusRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.UpdateStatus(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// CreateSale
+func (h *Handler) CreateSale(c echo.Context) error {
+	var reqData = new(dto.SaleRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.CreateSale(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// CancelSale
+func (h *Handler) CancelSale(c echo.Context) error {
+	var reqData = new(dto.CancelSaleRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		Int("saleId", (&reqData.SaleID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.CancelSale(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// PriceHistory
+func (h *Handler) PriceHistory(c echo.Context) error {
+	var reqData = new(dto.PriceHistoryRequest)
+
+	_, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ProductID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.PriceHistory(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// attributeFilter collect attribute filter from query param prefixed with attr., e.g. attr.color=red
+func attributeFilter(c echo.Context) map[string]string {
+	filter := map[string]string{}
+	for name, values := range c.QueryParams() {
+		key, ok := strings.CutPrefix(name, "attr.")
+		if !ok || key == "" || len(values) == 0 {
+			continue
+		}
+		filter[key] = values[0]
+	}
+	return filter
+}
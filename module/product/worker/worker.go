@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"time"
+
+	"pcstakehometest/module/product/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/scheduler"
+
+	"go.uber.org/fx"
+)
+
+type Worker struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logic     logic.IProductLogic
+	Logger    *logger.LogRus
+}
+
+func NewWorker(w Worker) {
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "PublishScheduledProduct",
+		Interval: time.Minute,
+		Run:      w.Logic.PublishScheduled,
+	})
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "NotifySalePriceDrop",
+		Interval: time.Minute,
+		Run:      w.Logic.NotifySalePriceDrop,
+	})
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"fmt"
+
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+// RefundRequest Amount in checkout currency, CheckoutID empty for order placed before checkout existed,
+// PaymentID empty to refund from the latest succeeded payment of the checkout
+type RefundRequest struct {
+	PaymentID     *int
+	CheckoutID    *int
+	TransactionID int
+	ReturnID      *int
+	Amount        money.Amount
+	Currency      string
+	Reason        string
+}
+
+func (d *RefundRequest) Validate() error {
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if !d.Amount.IsPositive() {
+		return fmt.Errorf(static.MinValue, "Amount", 0)
+	}
+	if d.Currency == "" {
+		return fmt.Errorf(static.EmptyValue, "Currency")
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	paymentRepository "pcstakehometest/module/payment/repository"
+	"pcstakehometest/module/refund/dto"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/payment"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// ProviderManual refund settled outside payment gateway
+const ProviderManual = "manual"
+
+// RefundLogic
+type IRefundLogic interface {
+	Refund(context.Context, *dto.RefundRequest, *gorm.DB) (*model.Refunds, error)
+	Settle(context.Context) error
+}
+
+type RefundLogic struct {
+	fx.In
+	Logger      *logger.LogRus
+	Gateway     payment.PaymentGateway
+	Database    *postgres.DB
+	PaymentRepo paymentRepository.IPaymentRepository
+}
+
+// NewLogic :
+func NewLogic(refundLogic RefundLogic) IRefundLogic {
+	return &refundLogic
+}
+
+// Refund record money to be returned through the gateway which collected it, sent later by Settle so it never go out
+// for a transaction which did not commit, order paid outside gateway recorded as settled manual refund
+func (l *RefundLogic) Refund(ctx context.Context, reqData *dto.RefundRequest, tx *gorm.DB) (*model.Refunds, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	refund := &model.Refunds{
+		TransactionID: reqData.TransactionID,
+		ReturnID:      reqData.ReturnID,
+		Amount:        reqData.Amount,
+		Currency:      reqData.Currency,
+		Provider:      ProviderManual,
+		Status:        enum.RefundStatusTypeSucceeded,
+	}
+	if reqData.Reason != "" {
+		refund.Reason = &reqData.Reason
+	}
+
+	var paid *model.Payments
+	if reqData.PaymentID != nil {
+		result, err := l.PaymentRepo.Find(ctx, &model.Payments{
+			ID: *reqData.PaymentID,
+		})
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "pembayaran"), http.StatusNotFound)
+			}
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		paid = result
+	} else if reqData.CheckoutID != nil {
+		result, err := l.PaymentRepo.Find(ctx, &model.Payments{
+			CheckoutID: *reqData.CheckoutID,
+			Status:     enum.PaymentStatusTypeSucceeded,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		paid = result
+	}
+
+	if paid != nil {
+		if paid.Currency != reqData.Currency {
+			return nil, utilities.ErrorRequest(errors.New(static.CurrencyMismatch), http.StatusBadRequest)
+		}
+
+		refunded, err := l.PaymentRepo.SumRefund(ctx, &model.Refunds{
+			PaymentID: &paid.ID,
+		}, tx)
+		if err != nil {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		if refunded.Add(reqData.Amount).Cmp(paid.Amount) > 0 {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.RefundExceedPayment, paid.Amount.Sub(refunded), paid.Currency), http.StatusConflict)
+		}
+
+		refund.PaymentID = &paid.ID
+		refund.Provider = paid.Provider
+		refund.Status = enum.RefundStatusTypePending
+	}
+
+	if _, err := l.PaymentRepo.CreateRefund(ctx, refund, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	refund.StatusRefund = refund.Status.String()
+
+	return refund, nil
+}
+
+// Settle send pending refund to the gateway, refund left pending when gateway did not answer so it is retried next run
+func (l *RefundLogic) Settle(ctx context.Context) error {
+	refunds, err := l.PaymentRepo.FindPendingRefund(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range refunds {
+		if err := l.settle(ctx, &refunds[i]); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// settle one refund, reference taken from refund row so gateway ignore the retry of a refund it already made
+func (l *RefundLogic) settle(ctx context.Context, refund *model.Refunds) error {
+	paid, err := l.PaymentRepo.Find(ctx, &model.Payments{
+		ID: *refund.PaymentID,
+	})
+	if err != nil {
+		return err
+	}
+
+	reference, err := l.Gateway.Refund(ctx, &payment.Refund{
+		ProviderReference: paid.ProviderReference,
+		Reference:         fmt.Sprintf("REFUND-%d", refund.ID),
+		Amount:            refund.Amount,
+		Currency:          refund.Currency,
+	})
+	switch {
+	case err == nil:
+		refund.Status = enum.RefundStatusTypeSucceeded
+		refund.ProviderReference = &reference
+	case errors.Is(err, payment.ErrDeclined):
+		reason := err.Error()
+		refund.Status = enum.RefundStatusTypeFailed
+		refund.FailureReason = &reason
+	default:
+		return err
+	}
+
+	if _, err := l.PaymentRepo.UpdateRefund(ctx, refund, l.Database.Gorm); err != nil {
+		return err
+	}
+
+	if refund.Status == enum.RefundStatusTypeFailed {
+		l.Logger.Errorf("refund %d of transaction %d declined by gateway, settle it manually", refund.ID, refund.TransactionID)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"time"
+
+	"pcstakehometest/module/refund/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/scheduler"
+
+	"go.uber.org/fx"
+)
+
+type Worker struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logic     logic.IRefundLogic
+	Logger    *logger.LogRus
+}
+
+func NewWorker(w Worker) {
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "SettleRefund",
+		Interval: time.Minute,
+		Run:      w.Logic.Settle,
+	})
+}
@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+type CreateRequest struct {
+	ProductID int
+	BuyerID   int
+	RoleID    enum.RoleType
+	Rating    int
+	Content   string
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if d.Rating < MinRating || d.Rating > MaxRating {
+		return fmt.Errorf(static.InvalidRange, "Rating", MinRating, MaxRating)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type UploadPhotoRequest struct {
+	ReviewID int
+	BuyerID  int
+	RoleID   enum.RoleType
+	Data     []byte
+}
+
+func (d *UploadPhotoRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.ReviewID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ReviewID")
+	}
+	if len(d.Data) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Image")
+	}
+	if int64(len(d.Data)) > config.Get().Storage.MaxImageSizeBytes {
+		return fmt.Errorf(static.ImageTooLarge, config.Get().Storage.MaxImageSize)
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindAllRequest struct {
+	ProductID int
+	SellerID  int
+}
+
+func (d *FindAllRequest) Validate() error {
+	if d.ProductID <= 0 && d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	return nil
+}
+
+type ReplyRequest struct {
+	ReviewID int
+	SellerID int
+	RoleID   enum.RoleType
+	Reply    string
+}
+
+func (d *ReplyRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.ReviewID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ReviewID")
+	}
+	if d.Reply == "" {
+		return fmt.Errorf(static.EmptyValue, "Reply")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type ReportRequest struct {
+	ReviewID   int
+	ReporterID int
+	RoleID     enum.RoleType
+	Reason     string
+}
+
+func (d *ReportRequest) Validate() error {
+	if d.ReporterID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ReporterID")
+	}
+	if d.ReviewID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ReviewID")
+	}
+	if d.Reason == "" {
+		return fmt.Errorf(static.EmptyValue, "Reason")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type FindAllReportRequest struct {
+	RoleID enum.RoleType
+	Status enum.ReviewReportStatusType
+}
+
+func (d *FindAllReportRequest) Validate() error {
+	if d.Status != 0 {
+		if err := d.Status.IsValid(); err != nil {
+			return err
+		}
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type ModerateRequest struct {
+	ReportID int
+	AdminID  int
+	RoleID   enum.RoleType
+	Status   enum.ReviewReportStatusType
+	Note     string
+}
+
+func (d *ModerateRequest) Validate() error {
+	if d.AdminID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "AdminID")
+	}
+	if d.ReportID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ReportID")
+	}
+	if d.Status != enum.ReviewReportStatusTypeHidden && d.Status != enum.ReviewReportStatusTypeDismissed {
+		return fmt.Errorf(static.DataNotFound, "Tipe Status Laporan")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	productDto "pcstakehometest/module/product/dto"
+	productLogic "pcstakehometest/module/product/logic"
+	"pcstakehometest/module/review/dto"
+	"pcstakehometest/module/review/repository"
+	"pcstakehometest/package/imaging"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/storage"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPhotos     = 5
+	thumbnailSize = 320
+)
+
+// ReviewLogic
+type IReviewLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Reviews, error)
+	UploadPhoto(context.Context, *dto.UploadPhotoRequest, *gorm.DB) (*model.Reviews, error)
+	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Reviews, error)
+	Reply(context.Context, *dto.ReplyRequest, *gorm.DB) error
+	Report(context.Context, *dto.ReportRequest, *gorm.DB) error
+	FindAllReport(context.Context, *dto.FindAllReportRequest) ([]*model.ReviewReports, error)
+	Moderate(context.Context, *dto.ModerateRequest, *gorm.DB) error
+}
+
+type ReviewLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	Storage      storage.Storage
+	ProductLogic productLogic.IProductLogic
+	ReviewRepo   repository.IReviewRepository
+}
+
+// NewLogic :
+func NewLogic(reviewLogic ReviewLogic) IReviewLogic {
+	return &reviewLogic
+}
+
+// Create review of product bought in accepted transaction
+func (l *ReviewLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Reviews, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+		ID: reqData.ProductID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	// Only buyer with accepted transaction containing the product can review
+	transaction, err := l.ReviewRepo.FindEligibleTransaction(ctx, &model.Reviews{
+		BuyerID:   reqData.BuyerID,
+		ProductID: product.ID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(errors.New(static.ReviewNotEligible), http.StatusForbidden)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	// One review per buyer for each product
+	if _, err := l.ReviewRepo.Find(ctx, &model.Reviews{
+		BuyerID:   reqData.BuyerID,
+		ProductID: product.ID,
+	}); err == nil {
+		return nil, utilities.ErrorRequest(errors.New(static.ReviewAlreadyExist), http.StatusBadRequest)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	review := &model.Reviews{
+		ProductID:     product.ID,
+		SellerID:      product.SellerID,
+		BuyerID:       reqData.BuyerID,
+		TransactionID: transaction.ID,
+		Rating:        reqData.Rating,
+		Content:       reqData.Content,
+		Photos:        model.ReviewPhotos{},
+		Status:        enum.ReviewStatusTypePublished,
+	}
+	if _, err := l.ReviewRepo.Create(ctx, review, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.ReviewRepo.UpdateRating(ctx, review, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	review.StatusReview = review.Status.String()
+
+	return review, nil
+}
+
+// UploadPhoto attach photo to buyer review
+func (l *ReviewLogic) UploadPhoto(ctx context.Context, reqData *dto.UploadPhotoRequest, tx *gorm.DB) (*model.Reviews, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	review, err := l.find(ctx, &model.Reviews{
+		ID:      reqData.ReviewID,
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(review.Photos) >= maxPhotos {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.ReviewPhotoLimit, maxPhotos), http.StatusBadRequest)
+	}
+
+	contentType, extension, err := imaging.Sniff(reqData.Data)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(errors.New(static.ImageNotSupported), http.StatusBadRequest)
+	}
+
+	maxPixels := config.Get().Storage.MaxImagePixels
+	img, err := imaging.Decode(reqData.Data, contentType, maxPixels)
+	if err != nil {
+		l.Logger.Error(err)
+		if errors.Is(err, imaging.ErrTooManyPixels) {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.ImageTooManyPixel, maxPixels), http.StatusBadRequest)
+		}
+		return nil, utilities.ErrorRequest(errors.New(static.ImageNotSupported), http.StatusBadRequest)
+	}
+
+	thumbnail, thumbnailType, err := imaging.Encode(imaging.Thumbnail(img, thumbnailSize), contentType)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	name := utilities.RandomString(20)
+	key := fmt.Sprintf("reviews/%d/%s.%s", review.ID, name, extension)
+	thumbnailKey := fmt.Sprintf("reviews/%d/%s_thumb.%s", review.ID, name, imaging.Extension(thumbnailType))
+
+	if err := l.Storage.Put(ctx, key, bytes.NewReader(reqData.Data), int64(len(reqData.Data)), contentType); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.Storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+		l.Logger.Error(err)
+		l.cleanup(ctx, key)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	review.Photos = append(review.Photos, model.ReviewPhoto{
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		URL:          l.Storage.URL(key),
+		ThumbnailURL: l.Storage.URL(thumbnailKey),
+	})
+	if err := l.ReviewRepo.UpdatePhotos(ctx, review, tx); err != nil {
+		l.cleanup(ctx, key, thumbnailKey)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	review.StatusReview = review.Status.String()
+
+	return review, nil
+}
+
+// FindAll published review of product or seller
+func (l *ReviewLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest) ([]*model.Reviews, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	reviews, err := l.ReviewRepo.FindAll(ctx, &model.Reviews{
+		ProductID: reqData.ProductID,
+		SellerID:  reqData.SellerID,
+		Status:    enum.ReviewStatusTypePublished,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, review := range reviews {
+		review.StatusReview = review.Status.String()
+	}
+
+	return reviews, nil
+}
+
+// Reply set seller reply of review, replace previous reply
+func (l *ReviewLogic) Reply(ctx context.Context, reqData *dto.ReplyRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	review, err := l.find(ctx, &model.Reviews{
+		ID:       reqData.ReviewID,
+		SellerID: reqData.SellerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	review.Reply = &reqData.Reply
+	review.RepliedAt = &now
+	if err := l.ReviewRepo.UpdateReply(ctx, review, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Report flag review as abusive for admin moderation
+func (l *ReviewLogic) Report(ctx context.Context, reqData *dto.ReportRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	review, err := l.find(ctx, &model.Reviews{
+		ID:     reqData.ReviewID,
+		Status: enum.ReviewStatusTypePublished,
+	})
+	if err != nil {
+		return err
+	}
+
+	if review.BuyerID == reqData.ReporterID {
+		return utilities.ErrorRequest(errors.New(static.Authorization), http.StatusForbidden)
+	}
+
+	if _, err := l.ReviewRepo.FindReport(ctx, &model.ReviewReports{
+		ReviewID:   review.ID,
+		ReporterID: reqData.ReporterID,
+	}); err == nil {
+		return utilities.ErrorRequest(errors.New(static.ReviewAlreadyReported), http.StatusBadRequest)
+	} else if err != gorm.ErrRecordNotFound {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if _, err := l.ReviewRepo.CreateReport(ctx, &model.ReviewReports{
+		ReviewID:   review.ID,
+		ReporterID: reqData.ReporterID,
+		Reason:     reqData.Reason,
+		Status:     enum.ReviewReportStatusTypeOpen,
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// FindAllReport list report for admin, open report by default
+func (l *ReviewLogic) FindAllReport(ctx context.Context, reqData *dto.FindAllReportRequest) ([]*model.ReviewReports, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	status := reqData.Status
+	if status == 0 {
+		status = enum.ReviewReportStatusTypeOpen
+	}
+
+	reports, err := l.ReviewRepo.FindAllReport(ctx, &model.ReviewReports{
+		Status: status,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, report := range reports {
+		report.StatusReport = report.Status.String()
+		if report.Review != nil {
+			report.Review.StatusReview = report.Review.Status.String()
+		}
+	}
+
+	return reports, nil
+}
+
+// Moderate resolve report by hiding the review or dismissing the report
+func (l *ReviewLogic) Moderate(ctx context.Context, reqData *dto.ModerateRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	report, err := l.ReviewRepo.FindReport(ctx, &model.ReviewReports{
+		ID: reqData.ReportID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "laporan"), http.StatusNotFound)
+		}
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if report.Status != enum.ReviewReportStatusTypeOpen {
+		return utilities.ErrorRequest(errors.New(static.ReportAlreadyResolved), http.StatusBadRequest)
+	}
+
+	now := time.Now()
+	resolve := &model.ReviewReports{
+		ID:         report.ID,
+		Status:     reqData.Status,
+		ResolvedBy: &reqData.AdminID,
+		ResolvedAt: &now,
+	}
+	if reqData.Note != "" {
+		resolve.Note = &reqData.Note
+	}
+
+	if reqData.Status == enum.ReviewReportStatusTypeHidden {
+		review, err := l.find(ctx, &model.Reviews{
+			ID: report.ReviewID,
+		})
+		if err != nil {
+			return err
+		}
+
+		review.Status = enum.ReviewStatusTypeHidden
+		if err := l.ReviewRepo.UpdateStatus(ctx, review, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		if err := l.ReviewRepo.UpdateRating(ctx, review, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		// Hidden review resolve every open report of it
+		resolve.ID = 0
+		resolve.ReviewID = review.ID
+	}
+
+	if err := l.ReviewRepo.UpdateReport(ctx, resolve, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (l *ReviewLogic) find(ctx context.Context, reqData *model.Reviews) (*model.Reviews, error) {
+	review, err := l.ReviewRepo.Find(ctx, reqData)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "ulasan"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return review, nil
+}
+
+// cleanup remove stored object, failure only logged because record is the source of truth
+func (l *ReviewLogic) cleanup(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := l.Storage.Delete(ctx, key); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// ReviewRepository
+type IReviewRepository interface {
+	Create(context.Context, *model.Reviews, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.Reviews) ([]*model.Reviews, error)
+	Find(context.Context, *model.Reviews) (*model.Reviews, error)
+	FindEligibleTransaction(context.Context, *model.Reviews) (*model.Transactions, error)
+	UpdatePhotos(context.Context, *model.Reviews, *gorm.DB) error
+	UpdateReply(context.Context, *model.Reviews, *gorm.DB) error
+	UpdateStatus(context.Context, *model.Reviews, *gorm.DB) error
+	UpdateRating(context.Context, *model.Reviews, *gorm.DB) error
+	CreateReport(context.Context, *model.ReviewReports, *gorm.DB) (*int, error)
+	FindAllReport(context.Context, *model.ReviewReports) ([]*model.ReviewReports, error)
+	FindReport(context.Context, *model.ReviewReports) (*model.ReviewReports, error)
+	UpdateReport(context.Context, *model.ReviewReports, *gorm.DB) error
+}
+
+type ReviewRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(reviewRepository ReviewRepository) IReviewRepository {
+	return &reviewRepository
+}
+
+// Create
+func (l *ReviewRepository) Create(ctx context.Context, reqData *model.Reviews, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll
+func (l *ReviewRepository) FindAll(ctx context.Context, reqData *model.Reviews) ([]*model.Reviews, error) {
+	reviews := []*model.Reviews{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Reviews{}).
+		Preload("Buyer").
+		Where(&model.Reviews{
+			ProductID: reqData.ProductID,
+			SellerID:  reqData.SellerID,
+			Status:    reqData.Status,
+		}).
+		Order("id desc").
+		Find(&reviews).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Find
+func (l *ReviewRepository) Find(ctx context.Context, reqData *model.Reviews) (*model.Reviews, error) {
+	review := new(model.Reviews)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Reviews{
+			ID:        reqData.ID,
+			ProductID: reqData.ProductID,
+			SellerID:  reqData.SellerID,
+			BuyerID:   reqData.BuyerID,
+			Status:    reqData.Status,
+		}).First(&review).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return review, nil
+}
+
+// FindEligibleTransaction find latest accepted transaction of buyer containing the product
+func (l *ReviewRepository) FindEligibleTransaction(ctx context.Context, reqData *model.Reviews) (*model.Transactions, error) {
+	transaction := new(model.Transactions)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where("buyer_id = ?", reqData.BuyerID).
+		Where("status in ?", enum.TransactionStatusFulfilled).
+		Where("items::jsonb @> ?::jsonb", fmt.Sprintf(`[{"ID":%d}]`, reqData.ProductID)).
+		Order("id desc").
+		First(&transaction).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// UpdatePhotos
+func (l *ReviewRepository) UpdatePhotos(ctx context.Context, reqData *model.Reviews, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Reviews{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"photos":     reqData.Photos,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateReply
+func (l *ReviewRepository) UpdateReply(ctx context.Context, reqData *model.Reviews, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Reviews{}).
+		Where("id = ?", reqData.ID).
+		Where("seller_id = ?", reqData.SellerID).
+		Updates(map[string]interface{}{
+			"reply":      reqData.Reply,
+			"replied_at": reqData.RepliedAt,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateStatus
+func (l *ReviewRepository) UpdateStatus(ctx context.Context, reqData *model.Reviews, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Reviews{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"status":     reqData.Status,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateRating recalculate rating average and count of product and its seller from published review
+func (l *ReviewRepository) UpdateRating(ctx context.Context, reqData *model.Reviews, tx *gorm.DB) error {
+	published := enum.ReviewStatusTypePublished
+
+	if err := tx.WithContext(ctx).Model(&model.Products{}).
+		Where("id = ?", reqData.ProductID).
+		Updates(map[string]interface{}{
+			"rating_average": gorm.Expr("coalesce((select round(avg(rating), 2) from reviews where product_id = ? and status = ? and deleted_at is null), 0)", reqData.ProductID, published),
+			"rating_count":   gorm.Expr("(select count(*) from reviews where product_id = ? and status = ? and deleted_at is null)", reqData.ProductID, published),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Model(&model.Users{}).
+		Where("id = ?", reqData.SellerID).
+		Updates(map[string]interface{}{
+			"rating_average": gorm.Expr("coalesce((select round(avg(rating), 2) from reviews where seller_id = ? and status = ? and deleted_at is null), 0)", reqData.SellerID, published),
+			"rating_count":   gorm.Expr("(select count(*) from reviews where seller_id = ? and status = ? and deleted_at is null)", reqData.SellerID, published),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateReport
+func (l *ReviewRepository) CreateReport(ctx context.Context, reqData *model.ReviewReports, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAllReport
+func (l *ReviewRepository) FindAllReport(ctx context.Context, reqData *model.ReviewReports) ([]*model.ReviewReports, error) {
+	reports := []*model.ReviewReports{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ReviewReports{}).
+		Preload("Review").
+		Where(&model.ReviewReports{
+			ReviewID: reqData.ReviewID,
+			Status:   reqData.Status,
+		}).
+		Order("id asc").
+		Find(&reports).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// FindReport
+func (l *ReviewRepository) FindReport(ctx context.Context, reqData *model.ReviewReports) (*model.ReviewReports, error) {
+	report := new(model.ReviewReports)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.ReviewReports{
+			ID:         reqData.ID,
+			ReviewID:   reqData.ReviewID,
+			ReporterID: reqData.ReporterID,
+		}).First(&report).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// UpdateReport resolve open report matching id or every open report of the review
+func (l *ReviewRepository) UpdateReport(ctx context.Context, reqData *model.ReviewReports, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.ReviewReports{}).
+		Where(&model.ReviewReports{
+			ID:       reqData.ID,
+			ReviewID: reqData.ReviewID,
+		}).
+		Where("status = ?", enum.ReviewReportStatusTypeOpen).
+		Updates(map[string]interface{}{
+			"status":      reqData.Status,
+			"note":        reqData.Note,
+			"resolved_by": reqData.ResolvedBy,
+			"resolved_at": reqData.ResolvedAt,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
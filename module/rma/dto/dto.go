@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+type CreateRequest struct {
+	TransactionID int
+	Reason        string
+	Items         []ReturnItem
+	BuyerID       int
+	RoleID        enum.RoleType
+}
+
+type ReturnItem struct {
+	ProductID int
+	Quantity  int
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if strings.TrimSpace(d.Reason) == "" {
+		return fmt.Errorf(static.EmptyValue, "Reason")
+	}
+	if len(d.Items) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Items")
+	}
+	for _, item := range d.Items {
+		if item.ProductID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "ProductID")
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf(static.MinValue, "Quantity", 0)
+		}
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindAllRequest struct {
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *FindAllRequest) Validate() error {
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type FindRequest struct {
+	ID     int
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *FindRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateRequest move return into Status, Reason required on reject, Courier and TrackingNumber required on ship
+type UpdateRequest struct {
+	ID             int
+	Status         enum.ReturnStatusType
+	Reason         string
+	Courier        string
+	TrackingNumber string
+	UserID         int
+	RoleID         enum.RoleType
+}
+
+func (d *UpdateRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if err := d.Status.IsValid(); err != nil {
+		return err
+	}
+	switch d.Status {
+	case enum.ReturnStatusTypeRejected:
+		if strings.TrimSpace(d.Reason) == "" {
+			return fmt.Errorf(static.EmptyValue, "Reason")
+		}
+	case enum.ReturnStatusTypeShipped:
+		if strings.TrimSpace(d.Courier) == "" {
+			return fmt.Errorf(static.EmptyValue, "Courier")
+		}
+		if strings.TrimSpace(d.TrackingNumber) == "" {
+			return fmt.Errorf(static.EmptyValue, "TrackingNumber")
+		}
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
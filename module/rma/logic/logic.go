@@ -0,0 +1,398 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/rma/dto"
+	"pcstakehometest/module/rma/repository"
+	transactionDto "pcstakehometest/module/transaction/dto"
+	transactionLogic "pcstakehometest/module/transaction/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// transitions every allowed return status change along with role permitted to make it
+var transitions = map[enum.ReturnStatusType]map[enum.ReturnStatusType][]enum.RoleType{
+	enum.ReturnStatusTypeRequested: {
+		enum.ReturnStatusTypeApproved: {enum.RoleTypeSeller},
+		enum.ReturnStatusTypeRejected: {enum.RoleTypeSeller},
+	},
+	enum.ReturnStatusTypeApproved: {
+		enum.ReturnStatusTypeShipped: {enum.RoleTypeBuyer},
+	},
+	enum.ReturnStatusTypeShipped: {
+		enum.ReturnStatusTypeReceived: {enum.RoleTypeSeller},
+	},
+	enum.ReturnStatusTypeReceived: {
+		enum.ReturnStatusTypeRefunded: {},
+	},
+}
+
+// RmaLogic
+type IRmaLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Returns, error)
+	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Returns, error)
+	Find(context.Context, *dto.FindRequest) (*model.Returns, error)
+	Update(context.Context, *dto.UpdateRequest, *gorm.DB) (*model.Returns, error)
+}
+
+type RmaLogic struct {
+	fx.In
+	Logger           *logger.LogRus
+	RmaRepo          repository.IRmaRepository
+	TransactionLogic transactionLogic.ITransactionLogic
+}
+
+// NewLogic :
+func NewLogic(rmaLogic RmaLogic) IRmaLogic {
+	return &rmaLogic
+}
+
+// Create return request for items of delivered order, quantity limited to what not yet returned
+func (l *RmaLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Returns, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.TransactionLogic.Find(ctx, &transactionDto.FindRequest{
+		ID:     reqData.TransactionID,
+		UserID: reqData.BuyerID,
+		RoleID: reqData.RoleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != enum.TransactionStatusTypeDelivered && transaction.Status != enum.TransactionStatusTypeCompleted {
+		return nil, utilities.ErrorRequest(errors.New(static.ReturnNotEligible), http.StatusConflict)
+	}
+
+	// Quantity already held by other return of the same order
+	returns, err := l.RmaRepo.FindAll(ctx, &model.Returns{
+		TransactionID: transaction.ID,
+	}, enum.ReturnStatusOpen)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	returned := map[int]int{}
+	var held money.Amount
+	for _, existing := range returns {
+		for _, item := range existing.Items {
+			returned[item.ID] += item.Quantity
+			held = held.Add(item.Price.Mul(int64(item.Quantity)))
+		}
+	}
+
+	// Voucher and redeemed coupon discount the whole order, spread over items in proportion to their price
+	var subtotal money.Amount
+	for _, item := range transaction.Items {
+		subtotal = subtotal.Add(item.Price.Mul(int64(item.Quantity)))
+	}
+	discount := transaction.VoucherDiscount.Add(transaction.CouponDiscount)
+
+	requested := map[int]int{}
+	productIDs := []int{}
+	for _, item := range reqData.Items {
+		if _, ok := requested[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		requested[item.ProductID] += item.Quantity
+	}
+
+	result := &model.Returns{
+		TransactionID: transaction.ID,
+		BuyerID:       transaction.BuyerID,
+		SellerID:      transaction.SellerID,
+		Reason:        strings.TrimSpace(reqData.Reason),
+		Status:        enum.ReturnStatusTypeRequested,
+		Currency:      transaction.Currency,
+	}
+	for _, productID := range productIDs {
+		var line *model.ProductTransaction
+		for i := range transaction.Items {
+			if transaction.Items[i].ID == productID {
+				line = &transaction.Items[i]
+				break
+			}
+		}
+		if line == nil {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, fmt.Sprintf("produk %v pada transaksi", productID)), http.StatusBadRequest)
+		}
+
+		quantity := requested[productID]
+		if quantity > line.Quantity-returned[productID] {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.ReturnQuantityExceed, line.Name), http.StatusBadRequest)
+		}
+
+		// Share counted on everything returned so far, a full return refund exactly what the items cost after discount
+		gross := line.Price.Mul(int64(quantity))
+		lineTotal := gross.Sub(share(discount, held.Add(gross), subtotal).Sub(share(discount, held, subtotal)))
+		held = held.Add(gross)
+		result.Items = append(result.Items, model.ProductReturn{
+			ID:        line.ID,
+			Name:      line.Name,
+			Price:     line.Price,
+			Quantity:  quantity,
+			LineTotal: lineTotal,
+		})
+		result.RefundAmount = result.RefundAmount.Add(lineTotal)
+	}
+
+	if _, err := l.RmaRepo.Create(ctx, result, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.RmaRepo.CreateHistory(ctx, &model.ReturnHistories{
+		ReturnID:  result.ID,
+		ToStatus:  result.Status,
+		ActorID:   &reqData.BuyerID,
+		ActorRole: &reqData.RoleID,
+		Note:      &result.Reason,
+	}, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	result.StatusReturn = result.Status.String()
+
+	l.notify(ctx, result.SellerID, result)
+
+	return result, nil
+}
+
+// FindAll buyer see their own return, seller see return of their order
+func (l *RmaLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest) ([]*model.Returns, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	whereData := &model.Returns{}
+	switch reqData.RoleID {
+	case enum.RoleTypeSeller:
+		whereData.SellerID = reqData.UserID
+	case enum.RoleTypeBuyer:
+		whereData.BuyerID = reqData.UserID
+	}
+
+	returns, err := l.RmaRepo.FindAll(ctx, whereData, nil)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, result := range returns {
+		result.StatusReturn = result.Status.String()
+	}
+
+	return returns, nil
+}
+
+// Find along with refund and every status change
+func (l *RmaLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.Returns, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	result, err := l.find(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, history := range result.Histories {
+		if history.FromStatus != nil {
+			history.From = history.FromStatus.String()
+		}
+		history.To = history.ToStatus.String()
+		if history.ActorRole != nil {
+			history.Role = history.ActorRole.String()
+		}
+	}
+
+	return result, nil
+}
+
+// Update seller approve, reject and confirm receipt, buyer ship item back, receipt refund the returned items
+func (l *RmaLogic) Update(ctx context.Context, reqData *dto.UpdateRequest, tx *gorm.DB) (*model.Returns, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	result, err := l.find(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(reqData.Reason)
+	switch reqData.Status {
+	case enum.ReturnStatusTypeRejected:
+		result.RejectReason = &note
+	case enum.ReturnStatusTypeShipped:
+		courier := strings.TrimSpace(reqData.Courier)
+		trackingNumber := strings.TrimSpace(reqData.TrackingNumber)
+		result.Courier = &courier
+		result.TrackingNumber = &trackingNumber
+	}
+
+	if err := l.transition(ctx, result, reqData.Status, reqData.UserID, reqData.RoleID, note, tx); err != nil {
+		return nil, err
+	}
+
+	if result.Status == enum.ReturnStatusTypeReceived {
+		if err := l.refund(ctx, result, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	recipient := result.BuyerID
+	if reqData.RoleID == enum.RoleTypeBuyer {
+		recipient = result.SellerID
+	}
+	l.notify(ctx, recipient, result)
+
+	return result, nil
+}
+
+// refund returned items on the transaction, which return the money through payment gateway
+func (l *RmaLogic) refund(ctx context.Context, result *model.Returns, tx *gorm.DB) error {
+	transaction, err := l.TransactionLogic.Find(ctx, &transactionDto.FindRequest{
+		ID:     result.TransactionID,
+		UserID: result.SellerID,
+		RoleID: enum.RoleTypeSeller,
+	})
+	if err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("retur #%v: %v", result.ID, result.Reason)
+	refunded, err := l.TransactionLogic.Refund(ctx, &transactionDto.RefundRequest{
+		ID:       transaction.ID,
+		ReturnID: &result.ID,
+		Amount:   result.RefundAmount,
+		Reason:   reason,
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	result.RefundID = &refunded.Refund.ID
+	result.Refund = refunded.Refund
+
+	return l.transition(ctx, result, enum.ReturnStatusTypeRefunded, 0, 0, "", tx)
+}
+
+// transition move return into next status and record who made the change, zero actor means system
+func (l *RmaLogic) transition(ctx context.Context, result *model.Returns, to enum.ReturnStatusType, actorID int, role enum.RoleType, note string, tx *gorm.DB) error {
+	from := result.Status
+
+	roles, ok := transitions[from][to]
+	if !ok {
+		return utilities.ErrorRequest(fmt.Errorf(static.InvalidReturnTransition, from, to), http.StatusConflict)
+	}
+	if actorID != 0 && !permitted(roles, role) {
+		return utilities.ErrorRequest(errors.New(static.Authorization), http.StatusForbidden)
+	}
+
+	result.Status = to
+	affected, err := l.RmaRepo.UpdateStatus(ctx, result, from, tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return utilities.ErrorRequest(errors.New(static.ReturnChanged), http.StatusConflict)
+	}
+
+	history := &model.ReturnHistories{
+		ReturnID:   result.ID,
+		FromStatus: &from,
+		ToStatus:   to,
+	}
+	if actorID != 0 {
+		history.ActorID = &actorID
+		history.ActorRole = &role
+	}
+	if note != "" {
+		history.Note = &note
+	}
+	if err := l.RmaRepo.CreateHistory(ctx, history, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	result.StatusReturn = to.String()
+
+	return nil
+}
+
+// find seller and buyer only see their own return, admin see every return
+func (l *RmaLogic) find(ctx context.Context, id int, userID int, role enum.RoleType) (*model.Returns, error) {
+	whereData := &model.Returns{
+		ID: id,
+	}
+	switch role {
+	case enum.RoleTypeSeller:
+		whereData.SellerID = userID
+	case enum.RoleTypeBuyer:
+		whereData.BuyerID = userID
+	}
+
+	result, err := l.RmaRepo.Find(ctx, whereData)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "retur"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	result.StatusReturn = result.Status.String()
+
+	return result, nil
+}
+
+// notify sent once the return committed, failed delivery should not undo it
+func (l *RmaLogic) notify(ctx context.Context, userID int, result *model.Returns) {
+	if err := notifier.Defer(ctx, &notifier.Message{
+		UserID: userID,
+		Event:  notifier.EventReturnUpdated,
+		Title:  static.ReturnUpdatedTitle,
+		Body:   fmt.Sprintf(static.ReturnUpdatedBody, result.ID, result.TransactionID, strings.ToLower(result.StatusReturn)),
+		Data: map[string]interface{}{
+			"ReturnID":      result.ID,
+			"TransactionID": result.TransactionID,
+			"Status":        result.StatusReturn,
+		},
+	}); err != nil {
+		l.Logger.Error(err)
+	}
+}
+
+func permitted(roles []enum.RoleType, role enum.RoleType) bool {
+	for _, allowed := range roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// share part of discount belong to part of whole
+func share(discount, part, whole money.Amount) money.Amount {
+	if !whole.IsPositive() {
+		return 0
+	}
+	return money.FromMinor(discount.Minor() * part.Minor() / whole.Minor())
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// RmaRepository
+type IRmaRepository interface {
+	Create(context.Context, *model.Returns, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.Returns, []enum.ReturnStatusType) ([]*model.Returns, error)
+	Find(context.Context, *model.Returns) (*model.Returns, error)
+	UpdateStatus(context.Context, *model.Returns, enum.ReturnStatusType, *gorm.DB) (int64, error)
+	CreateHistory(context.Context, *model.ReturnHistories, *gorm.DB) error
+}
+
+type RmaRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(rmaRepository RmaRepository) IRmaRepository {
+	return &rmaRepository
+}
+
+// Create
+func (l *RmaRepository) Create(ctx context.Context, reqData *model.Returns, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll newest first, empty statuses mean every status
+func (l *RmaRepository) FindAll(ctx context.Context, reqData *model.Returns, statuses []enum.ReturnStatusType) ([]*model.Returns, error) {
+	returns := []*model.Returns{}
+
+	query := l.Database.Gorm.WithContext(ctx).Model(&model.Returns{}).
+		Where(&model.Returns{
+			TransactionID: reqData.TransactionID,
+			BuyerID:       reqData.BuyerID,
+			SellerID:      reqData.SellerID,
+		})
+	if len(statuses) > 0 {
+		query = query.Where("status in ?", statuses)
+	}
+
+	if err := query.
+		Order("id desc").
+		Find(&returns).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+// Find along with refund and history
+func (l *RmaRepository) Find(ctx context.Context, reqData *model.Returns) (*model.Returns, error) {
+	result := new(model.Returns)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Preload("Refund").
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id asc")
+		}).
+		Where(&model.Returns{
+			ID:       reqData.ID,
+			BuyerID:  reqData.BuyerID,
+			SellerID: reqData.SellerID,
+		}).
+		First(&result).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateStatus only move return still in the given status, return zero when status already changed
+func (l *RmaRepository) UpdateStatus(ctx context.Context, reqData *model.Returns, from enum.ReturnStatusType, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).Model(&model.Returns{}).
+		Where("id = ?", reqData.ID).
+		Where("status = ?", from).
+		Updates(map[string]interface{}{
+			"status":          reqData.Status,
+			"reject_reason":   reqData.RejectReason,
+			"courier":         reqData.Courier,
+			"tracking_number": reqData.TrackingNumber,
+			"refund_id":       reqData.RefundID,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CreateHistory
+func (l *RmaRepository) CreateHistory(ctx context.Context, reqData *model.ReturnHistories, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
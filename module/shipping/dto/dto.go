@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"pcstakehometest/package/shipping"
+	"pcstakehometest/static"
+)
+
+type QuoteRequest struct {
+	Origin      string
+	Destination string
+	Items       []QuoteItem
+	Currency    string
+}
+
+type QuoteItem struct {
+	ProductID int
+	Quantity  int
+}
+
+func (d *QuoteRequest) Validate() error {
+	if strings.TrimSpace(d.Origin) == "" {
+		return fmt.Errorf(static.EmptyValue, "Origin")
+	}
+	if strings.TrimSpace(d.Destination) == "" {
+		return fmt.Errorf(static.EmptyValue, "Destination")
+	}
+	if len(d.Items) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Items")
+	}
+	for _, item := range d.Items {
+		if item.ProductID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "ProductID")
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf(static.MinValue, "Quantity", 0)
+		}
+	}
+	return nil
+}
+
+type QuoteResponse struct {
+	Weight int
+	Quotes []*shipping.Quote
+}
+
+// SelectRequest quote of the chosen service, fee converted into Currency when set
+type SelectRequest struct {
+	Origin      string
+	Destination string
+	Weight      int
+	Carrier     string
+	Service     string
+	Currency    string
+}
+
+func (d *SelectRequest) Validate() error {
+	if strings.TrimSpace(d.Origin) == "" {
+		return fmt.Errorf(static.EmptyValue, "Origin")
+	}
+	if strings.TrimSpace(d.Destination) == "" {
+		return fmt.Errorf(static.EmptyValue, "Destination")
+	}
+	if d.Weight <= 0 {
+		return fmt.Errorf(static.MinValue, "Weight", 0)
+	}
+	if strings.TrimSpace(d.Carrier) == "" {
+		return fmt.Errorf(static.EmptyValue, "Carrier")
+	}
+	if strings.TrimSpace(d.Service) == "" {
+		return fmt.Errorf(static.EmptyValue, "Service")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	productDto "pcstakehometest/module/product/dto"
+	productLogic "pcstakehometest/module/product/logic"
+	"pcstakehometest/module/shipping/dto"
+	"pcstakehometest/package/exchange"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/shipping"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+)
+
+// ShippingLogic
+type IShippingLogic interface {
+	Quote(context.Context, *dto.QuoteRequest) (*dto.QuoteResponse, error)
+	Select(context.Context, *dto.SelectRequest) (*shipping.Quote, error)
+}
+
+type ShippingLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	Carrier      shipping.Carrier
+	Exchange     exchange.Provider
+	ProductLogic productLogic.IProductLogic
+}
+
+// NewLogic :
+func NewLogic(shippingLogic ShippingLogic) IShippingLogic {
+	return &shippingLogic
+}
+
+// Quote every service able to deliver the items, weight taken from product attribute
+func (l *ShippingLogic) Quote(ctx context.Context, reqData *dto.QuoteRequest) (*dto.QuoteResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	weight := 0
+	for _, item := range reqData.Items {
+		productDetail, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+			ID:     item.ProductID,
+			Status: enum.ProductStatusTypePublished,
+		})
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, err
+		}
+
+		unitWeight := config.Get().Shipping.DefaultWeight
+		if productDetail.Category != nil {
+			unitWeight = shipping.Weight(productDetail.Category.Attributes.Items(productDetail.Attributes), unitWeight)
+		}
+		weight += unitWeight * item.Quantity
+	}
+
+	quotes, err := l.quote(ctx, &shipping.Parcel{
+		Origin:      reqData.Origin,
+		Destination: reqData.Destination,
+		Weight:      weight,
+	}, reqData.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.QuoteResponse{
+		Weight: weight,
+		Quotes: quotes,
+	}, nil
+}
+
+// Select fee of the chosen service, used when order placed so fee never taken from client
+func (l *ShippingLogic) Select(ctx context.Context, reqData *dto.SelectRequest) (*shipping.Quote, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	quotes, err := l.quote(ctx, &shipping.Parcel{
+		Origin:      reqData.Origin,
+		Destination: reqData.Destination,
+		Weight:      reqData.Weight,
+	}, reqData.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := shipping.Select(quotes, strings.TrimSpace(reqData.Carrier), strings.TrimSpace(reqData.Service))
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.ShippingServiceNotFound, strings.ToUpper(reqData.Carrier), strings.ToUpper(reqData.Service)), http.StatusBadRequest)
+	}
+
+	return quote, nil
+}
+
+// quote ask carrier then convert every fee into currency
+func (l *ShippingLogic) quote(ctx context.Context, parcel *shipping.Parcel, currency string) ([]*shipping.Quote, error) {
+	quotes, err := l.Carrier.Quote(ctx, parcel)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == shipping.ErrRouteNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.ShippingRouteNotFound, parcel.Origin, parcel.Destination), http.StatusBadRequest)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return quotes, nil
+	}
+
+	for _, quote := range quotes {
+		if quote.Currency == currency {
+			continue
+		}
+		rate, err := l.Exchange.Rate(ctx, quote.Currency, currency)
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, currency), http.StatusBadRequest)
+		}
+		quote.Fee = quote.Fee.Convert(rate.Value)
+		quote.Currency = currency
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,59 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"pcstakehometest/module/shipping/dto"
+	"pcstakehometest/module/shipping/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/router"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Handler struct {
+	fx.In
+	Logic     logic.IShippingLogic
+	EchoRoute *router.Router
+	Logger    *logger.LogRus
+}
+
+func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
+	h.Route(m...)
+	return h
+}
+
+func (h *Handler) Route(m ...echo.MiddlewareFunc) {
+	shipping := h.EchoRoute.Group("/v1/shipping", m...)
+	shipping.POST("/quote", h.Quote, h.EchoRoute.Authentication)
+}
+
+// Quote
+func (h *Handler) Quote(c echo.Context) error {
+	var reqData = new(dto.QuoteRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	resp, err := h.Logic.Quote(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
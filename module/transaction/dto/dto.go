@@ -1,20 +1,55 @@
 package dto
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"pcstakehometest/model"
+	"strings"
 
 	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
 	"pcstakehometest/static"
 )
 
 type CreateOrderRequest struct {
-	BuyerID  int
-	SellerID int
-	Items    []int
-	RoleID   enum.RoleType
-	Coupons  int
+	BuyerID     int
+	SellerID    int
+	Items       []OrderItem
+	RoleID      enum.RoleType
+	Coupons     int
+	Currency    string
+	Origin      string
+	Destination string
+	Carrier     string
+	Service     string
+
+	// RedeemCoupons coupon spent from buyer wallet as discount of item subtotal
+	RedeemCoupons int
+	VoucherCode   string
+}
+
+type OrderItem struct {
+	ProductID int
+	Quantity  int
+}
+
+// UnmarshalJSON accept line item object or plain product ID from older client, quantity default to one
+func (d *OrderItem) UnmarshalJSON(data []byte) error {
+	var productID int
+	if err := json.Unmarshal(data, &productID); err == nil {
+		*d = OrderItem{ProductID: productID, Quantity: 1}
+		return nil
+	}
+
+	type orderItem OrderItem
+	item := orderItem{Quantity: 1}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+
+	*d = OrderItem(item)
+	return nil
 }
 
 func (d *CreateOrderRequest) Validate() error {
@@ -24,8 +59,100 @@ func (d *CreateOrderRequest) Validate() error {
 	if d.SellerID <= 0 {
 		return fmt.Errorf(static.EmptyValue, "SellerID")
 	}
-	if len(d.Items) == 0 {
-		return fmt.Errorf(static.EmptyValue, "Product")
+	if err := validateItems(d.Items); err != nil {
+		return err
+	}
+	if d.RedeemCoupons < 0 {
+		return fmt.Errorf(static.NegativeValue, "RedeemCoupons")
+	}
+	// Order without shipping service picked up by buyer
+	if d.Carrier != "" || d.Service != "" {
+		if err := validateShipping(d.Origin, d.Destination, d.Carrier, d.Service); err != nil {
+			return err
+		}
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type CheckoutRequest struct {
+	BuyerID     int
+	Items       []OrderItem
+	RoleID      enum.RoleType
+	Currency    string
+	Destination string
+	Shipping    []ShippingOption
+	Vouchers    []VoucherOption
+}
+
+// ShippingOption service chosen for order of one seller
+type ShippingOption struct {
+	SellerID int
+	Origin   string
+	Carrier  string
+	Service  string
+}
+
+// VoucherOption voucher code applied to order of one seller
+type VoucherOption struct {
+	SellerID int
+	Code     string
+}
+
+func (d *CheckoutRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := validateItems(d.Items); err != nil {
+		return err
+	}
+	for _, option := range d.Shipping {
+		if option.SellerID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "SellerID")
+		}
+		if err := validateShipping(option.Origin, d.Destination, option.Carrier, option.Service); err != nil {
+			return err
+		}
+	}
+	for _, option := range d.Vouchers {
+		if option.SellerID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "SellerID")
+		}
+		if strings.TrimSpace(option.Code) == "" {
+			return fmt.Errorf(static.EmptyValue, "Code")
+		}
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type CheckoutResponse struct {
+	Checkout *model.Checkouts
+	Coupons  int
+}
+
+type FindCheckoutRequest struct {
+	ID      int
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *FindCheckoutRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
 	}
 	if err := d.RoleID.IsValid(); err != nil {
 		return err
@@ -36,6 +163,37 @@ func (d *CreateOrderRequest) Validate() error {
 	return nil
 }
 
+func validateShipping(origin, destination, carrier, service string) error {
+	if strings.TrimSpace(origin) == "" {
+		return fmt.Errorf(static.EmptyValue, "Origin")
+	}
+	if strings.TrimSpace(destination) == "" {
+		return fmt.Errorf(static.EmptyValue, "Destination")
+	}
+	if strings.TrimSpace(carrier) == "" {
+		return fmt.Errorf(static.EmptyValue, "Carrier")
+	}
+	if strings.TrimSpace(service) == "" {
+		return fmt.Errorf(static.EmptyValue, "Service")
+	}
+	return nil
+}
+
+func validateItems(items []OrderItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Product")
+	}
+	for _, item := range items {
+		if item.ProductID <= 0 {
+			return fmt.Errorf(static.EmptyValue, "ProductID")
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf(static.MinValue, "Quantity", 0)
+		}
+	}
+	return nil
+}
+
 type FindAllRequest struct {
 	UserID int
 	Items  []int
@@ -94,3 +252,279 @@ func (d *AcceptOrderRequest) Validate() error {
 	}
 	return nil
 }
+
+type RejectOrderRequest struct {
+	SellerID      int
+	TransactionID int
+	Reason        string
+	RoleID        enum.RoleType
+}
+
+func (d *RejectOrderRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if strings.TrimSpace(d.Reason) == "" {
+		return fmt.Errorf(static.EmptyValue, "Reason")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// Action of bulk order request
+const (
+	BulkActionAccept = "accept"
+	BulkActionReject = "reject"
+)
+
+// MaxBulkOrder transaction allowed in one bulk request
+const MaxBulkOrder = 100
+
+type BulkOrderRequest struct {
+	SellerID       int
+	Action         string
+	TransactionIDs []int
+	Reason         string
+	RoleID         enum.RoleType
+}
+
+func (d *BulkOrderRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.Action != BulkActionAccept && d.Action != BulkActionReject {
+		return fmt.Errorf(static.InvalidBulkAction, d.Action)
+	}
+	if len(d.TransactionIDs) == 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionIDs")
+	}
+	if len(d.TransactionIDs) > MaxBulkOrder {
+		return fmt.Errorf(static.MaxBulkOrder, MaxBulkOrder)
+	}
+	for _, id := range d.TransactionIDs {
+		if id <= 0 {
+			return fmt.Errorf(static.EmptyValue, "TransactionID")
+		}
+	}
+	if d.Action == BulkActionReject && strings.TrimSpace(d.Reason) == "" {
+		return fmt.Errorf(static.EmptyValue, "Reason")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type BulkOrderResponse struct {
+	Succeeded int
+	Failed    int
+	Results   []*BulkOrderResult
+}
+
+// BulkOrderResult outcome of one transaction, Code follow http status of single order endpoint
+type BulkOrderResult struct {
+	TransactionID int
+	Success       bool
+	Code          int
+	Error         string `json:",omitempty"`
+}
+
+type CancelOrderRequest struct {
+	BuyerID       int
+	TransactionID int
+	Reason        string
+	RoleID        enum.RoleType
+}
+
+func (d *CancelOrderRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if strings.TrimSpace(d.Reason) == "" {
+		return fmt.Errorf(static.EmptyValue, "Reason")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// CancelOrderResponse Cancellation filled when order past cutoff and wait for seller approval
+type CancelOrderResponse struct {
+	Transaction  *model.Transactions
+	Cancellation *model.Cancellations `json:",omitempty"`
+}
+
+type ResolveCancellationRequest struct {
+	SellerID      int
+	TransactionID int
+	Approve       bool
+	Note          string
+	RoleID        enum.RoleType
+}
+
+func (d *ResolveCancellationRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// RefundRequest Amount in transaction currency, empty Amount refund everything not yet refunded, ReturnID empty for refund outside return flow, UserID empty when made by system
+type RefundRequest struct {
+	ID       int
+	ReturnID *int
+	Amount   money.Amount
+	Reason   string
+	UserID   int
+	RoleID   enum.RoleType
+}
+
+func (d *RefundRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.Amount.IsNegative() {
+		return fmt.Errorf(static.MinValue, "Amount", 0)
+	}
+	return nil
+}
+
+type RefundResponse struct {
+	Transaction *model.Transactions
+	Refund      *model.Refunds
+}
+
+type FindRequest struct {
+	ID     int
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *FindRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateStatusRequest Carrier and TrackingNumber attached when order shipped
+type UpdateStatusRequest struct {
+	ID             int
+	Status         enum.TransactionStatusType
+	Reason         string
+	Carrier        string
+	TrackingNumber string
+	UserID         int
+	RoleID         enum.RoleType
+}
+
+func (d *UpdateStatusRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.Status.IsValid(); err != nil {
+		return err
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type StatusHistoryRequest struct {
+	ID     int
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *StatusHistoryRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type TrackingRequest struct {
+	ID     int
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *TrackingRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if d.UserID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TrackingResponse Events empty until carrier report the first checkpoint
+type TrackingResponse struct {
+	TransactionID  int
+	Status         string
+	Carrier        string
+	TrackingNumber string
+	Events         []*model.TrackingEvents
+}
+
+type TrackingWebhookRequest struct {
+	Payload   []byte
+	Signature string
+}
+
+func (d *TrackingWebhookRequest) Validate() error {
+	if len(d.Payload) == 0 {
+		return fmt.Errorf(static.EmptyValue, "Payload")
+	}
+	if d.Signature == "" {
+		return fmt.Errorf(static.EmptyValue, "Signature")
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/transaction/dto"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// CancelOrder pending order or order accepted within cancel window cancelled right away,
+// accepted order past the window or packed order turn into cancellation request for seller
+func (l *TransactionLogic) CancelOrder(ctx context.Context, reqData *dto.CancelOrderRequest, tx *gorm.DB) (*dto.CancelOrderResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.TransactionID, reqData.BuyerID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(reqData.Reason)
+	response := &dto.CancelOrderResponse{
+		Transaction: transaction,
+	}
+
+	direct, err := l.withinCancelWindow(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if direct {
+		if err := l.cancel(ctx, transaction, reqData.BuyerID, reqData.RoleID, reason, tx); err != nil {
+			return nil, err
+		}
+
+		// Sent once the cancellation committed, failed delivery should not undo it
+		if err := notifier.Defer(ctx, &notifier.Message{
+			UserID: transaction.SellerID,
+			Event:  notifier.EventOrderCancelled,
+			Title:  static.OrderCancelledTitle,
+			Body:   fmt.Sprintf(static.OrderCancelledBody, transaction.ID, reason),
+			Data: map[string]interface{}{
+				"TransactionID": transaction.ID,
+				"Reason":        reason,
+			},
+		}); err != nil {
+			l.Logger.Error(err)
+		}
+
+		return response, nil
+	}
+
+	if transaction.Status != enum.TransactionStatusTypeAccept && transaction.Status != enum.TransactionStatusTypePacked {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.InvalidTransition, transaction.Status, enum.TransactionStatusTypeCancelled), http.StatusConflict)
+	}
+
+	_, err = l.TransactionRepo.FindCancellation(ctx, &model.Cancellations{
+		TransactionID: transaction.ID,
+		Status:        enum.CancellationStatusTypeRequested,
+	})
+	if err == nil {
+		return nil, utilities.ErrorRequest(errors.New(static.CancellationRequested), http.StatusConflict)
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	cancellation := &model.Cancellations{
+		TransactionID: transaction.ID,
+		BuyerID:       transaction.BuyerID,
+		SellerID:      transaction.SellerID,
+		Reason:        reason,
+		Status:        enum.CancellationStatusTypeRequested,
+	}
+	if _, err := l.TransactionRepo.CreateCancellation(ctx, cancellation, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	cancellation.StatusCancellation = cancellation.Status.String()
+	response.Cancellation = cancellation
+
+	if err := notifier.Defer(ctx, &notifier.Message{
+		UserID: transaction.SellerID,
+		Event:  notifier.EventCancellationRequested,
+		Title:  static.CancellationTitle,
+		Body:   fmt.Sprintf(static.CancellationBody, transaction.ID, reason),
+		Data: map[string]interface{}{
+			"TransactionID":  transaction.ID,
+			"CancellationID": cancellation.ID,
+			"Reason":         reason,
+		},
+	}); err != nil {
+		l.Logger.Error(err)
+	}
+
+	return response, nil
+}
+
+// ResolveCancellation seller approve or decline pending cancellation request
+func (l *TransactionLogic) ResolveCancellation(ctx context.Context, reqData *dto.ResolveCancellationRequest, tx *gorm.DB) (*model.Cancellations, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.TransactionID, reqData.SellerID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation, err := l.TransactionRepo.FindCancellation(ctx, &model.Cancellations{
+		TransactionID: transaction.ID,
+		Status:        enum.CancellationStatusTypeRequested,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, static.CancellationNotFound), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	cancellation.Status = enum.CancellationStatusTypeDeclined
+	if reqData.Approve {
+		cancellation.Status = enum.CancellationStatusTypeApproved
+		if err := l.cancel(ctx, transaction, reqData.SellerID, reqData.RoleID, cancellation.Reason, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if note := strings.TrimSpace(reqData.Note); note != "" {
+		cancellation.Note = &note
+	}
+	cancellation.ResolvedAt = &now
+	if err := l.TransactionRepo.UpdateCancellation(ctx, cancellation, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	cancellation.StatusCancellation = cancellation.Status.String()
+
+	if err := notifier.Defer(ctx, &notifier.Message{
+		UserID: transaction.BuyerID,
+		Event:  notifier.EventCancellationResolved,
+		Title:  static.CancellationResultTitle,
+		Body:   fmt.Sprintf(static.CancellationResultBody, transaction.ID, strings.ToLower(cancellation.StatusCancellation)),
+		Data: map[string]interface{}{
+			"TransactionID":  transaction.ID,
+			"CancellationID": cancellation.ID,
+			"Status":         cancellation.StatusCancellation,
+			"Note":           cancellation.Note,
+		},
+	}); err != nil {
+		l.Logger.Error(err)
+	}
+
+	return cancellation, nil
+}
+
+// withinCancelWindow buyer may cancel without approval while pending or shortly after seller accept
+func (l *TransactionLogic) withinCancelWindow(ctx context.Context, transaction *model.Transactions) (bool, error) {
+	switch transaction.Status {
+	case enum.TransactionStatusTypePending:
+		return true, nil
+	case enum.TransactionStatusTypeAccept:
+	default:
+		return false, nil
+	}
+
+	acceptedAt := transaction.UpdatedAt
+	history, err := l.TransactionRepo.FindLatestStatusHistory(ctx, &model.TransactionStatusHistories{
+		TransactionID: transaction.ID,
+		ToStatus:      enum.TransactionStatusTypeAccept,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	if err == nil {
+		acceptedAt = history.CreatedAt
+	}
+
+	return time.Since(acceptedAt) <= config.Get().Transaction.CancelWindowDuration, nil
+}
+
+// ExpireOrders cancel pending order still holding reserved stock after its reservation expired, each order on its own transaction
+func (l *TransactionLogic) ExpireOrders(ctx context.Context) error {
+	transactionIDs, err := l.InventoryLogic.FindExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, transactionID := range transactionIDs {
+		transaction, err := l.findOwned(ctx, transactionID, 0, 0)
+		if err != nil {
+			continue
+		}
+		if transaction.Status != enum.TransactionStatusTypePending {
+			continue
+		}
+
+		tx := l.Database.Gorm.Begin()
+		if err := l.cancel(ctx, transaction, 0, 0, static.ReservationExpired, tx); err != nil {
+			l.Logger.Error(err)
+			tx.Rollback()
+			continue
+		}
+		tx.Commit()
+	}
+
+	return nil
+}
+
+// cancel move order into cancelled, return its stock, coupons spent, voucher used and money paid, and revoke coupons issued by it
+func (l *TransactionLogic) cancel(ctx context.Context, transaction *model.Transactions, actorID int, role enum.RoleType, reason string, tx *gorm.DB) error {
+	if err := l.transition(ctx, transaction, enum.TransactionStatusTypeCancelled, actorID, role, reason, tx); err != nil {
+		return err
+	}
+
+	if err := l.InventoryLogic.Release(ctx, transaction.ID, "order cancelled", tx); err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	issued := transaction.Coupons
+	transaction.Coupons = 0
+	if err := l.TransactionRepo.UpdateCoupons(ctx, transaction, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.revertCoupons(ctx, transaction, issued, transaction.CouponsRedeemed, tx); err != nil {
+		return err
+	}
+
+	if err := l.releaseVoucher(ctx, transaction, tx); err != nil {
+		return err
+	}
+
+	if err := l.refundPaid(ctx, transaction, reason, tx); err != nil {
+		return err
+	}
+
+	return nil
+}
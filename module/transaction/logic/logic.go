@@ -2,19 +2,41 @@ package logic
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"pcstakehometest/config"
+	"pcstakehometest/database/postgres"
 	"pcstakehometest/enum"
 	"pcstakehometest/model"
+	couponDto "pcstakehometest/module/coupon/dto"
+	couponLogic "pcstakehometest/module/coupon/logic"
+	inventoryDto "pcstakehometest/module/inventory/dto"
+	inventoryLogic "pcstakehometest/module/inventory/logic"
 	productDto "pcstakehometest/module/product/dto"
 	productLogic "pcstakehometest/module/product/logic"
+	refundDto "pcstakehometest/module/refund/dto"
+	refundLogic "pcstakehometest/module/refund/logic"
+	shippingDto "pcstakehometest/module/shipping/dto"
+	shippingLogic "pcstakehometest/module/shipping/logic"
 	"pcstakehometest/module/transaction/dto"
 	"pcstakehometest/module/transaction/repository"
 	userDto "pcstakehometest/module/user/dto"
 	userLogic "pcstakehometest/module/user/logic"
+	voucherDto "pcstakehometest/module/voucher/dto"
+	voucherLogic "pcstakehometest/module/voucher/logic"
+	"pcstakehometest/package/coupon"
+	"pcstakehometest/package/exchange"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/package/shipping"
+	"pcstakehometest/package/tracking"
 	"pcstakehometest/static"
 	"pcstakehometest/utilities"
 
@@ -25,9 +47,24 @@ import (
 // TransactionLogic
 type ITransactionLogic interface {
 	CreateOrder(context.Context, *dto.CreateOrderRequest, *gorm.DB) (int, error)
+	Checkout(context.Context, *dto.CheckoutRequest, *gorm.DB) (*dto.CheckoutResponse, error)
+	FindCheckout(context.Context, *dto.FindCheckoutRequest) (*model.Checkouts, error)
 	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Transactions, error)
+	Find(context.Context, *dto.FindRequest) (*model.Transactions, error)
 	AcceptOrder(context.Context, *dto.AcceptOrderRequest, *gorm.DB) error
 	FindHistory(context.Context, *dto.FindHistory) ([]*dto.TransactionHistoryResponse, int, error)
+	RejectOrder(context.Context, *dto.RejectOrderRequest, *gorm.DB) error
+	BulkOrder(context.Context, *dto.BulkOrderRequest, *gorm.DB) (*dto.BulkOrderResponse, error)
+	UpdateStatus(context.Context, *dto.UpdateStatusRequest, *gorm.DB) (*model.Transactions, error)
+	FindStatusHistory(context.Context, *dto.StatusHistoryRequest) ([]*model.TransactionStatusHistories, error)
+	CancelOrder(context.Context, *dto.CancelOrderRequest, *gorm.DB) (*dto.CancelOrderResponse, error)
+	ResolveCancellation(context.Context, *dto.ResolveCancellationRequest, *gorm.DB) (*model.Cancellations, error)
+	ExpireOrders(context.Context) error
+	MarkPaid(context.Context, int, time.Time, *gorm.DB) ([]*model.Transactions, error)
+	Refund(context.Context, *dto.RefundRequest, *gorm.DB) (*dto.RefundResponse, error)
+	Tracking(context.Context, *dto.TrackingRequest) (*dto.TrackingResponse, error)
+	TrackingWebhook(context.Context, *dto.TrackingWebhookRequest, *gorm.DB) ([]*model.TrackingEvents, error)
+	PollTracking(context.Context) error
 }
 
 type TransactionLogic struct {
@@ -35,6 +72,15 @@ type TransactionLogic struct {
 	Logger          *logger.LogRus
 	ProductLogic    productLogic.IProductLogic
 	UserLogic       userLogic.IUserLogic
+	Exchange        exchange.Provider
+	InventoryLogic  inventoryLogic.IInventoryLogic
+	ShippingLogic   shippingLogic.IShippingLogic
+	CouponLogic     couponLogic.ICouponLogic
+	VoucherLogic    voucherLogic.IVoucherLogic
+	RefundLogic     refundLogic.IRefundLogic
+	Tracker         tracking.Tracker
+	Database        *postgres.DB
+	Notifier        notifier.Notifier
 	TransactionRepo repository.ITransactionRepository
 }
 
@@ -52,10 +98,161 @@ func (l *TransactionLogic) CreateOrder(ctx context.Context, reqData *dto.CreateO
 		return 0, utilities.ErrorRequest(err, http.StatusBadRequest)
 	}
 
+	response, err := l.checkout(ctx, reqData.BuyerID, []*dto.CreateOrderRequest{reqData}, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Coupons, nil
+}
+
+// Checkout split items into one transaction per seller under a single checkout and payment reference
+func (l *TransactionLogic) Checkout(ctx context.Context, reqData *dto.CheckoutRequest, tx *gorm.DB) (*dto.CheckoutResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	orders := []*dto.CreateOrderRequest{}
+	sellers := map[int]*dto.CreateOrderRequest{}
+	for _, item := range reqData.Items {
+		productDetail, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
+			ID:     item.ProductID,
+			Status: enum.ProductStatusTypePublished,
+		})
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, err
+		}
+
+		order, ok := sellers[productDetail.SellerID]
+		if !ok {
+			order = &dto.CreateOrderRequest{
+				BuyerID:  reqData.BuyerID,
+				SellerID: productDetail.SellerID,
+				RoleID:   reqData.RoleID,
+				Currency: reqData.Currency,
+			}
+			sellers[productDetail.SellerID] = order
+			orders = append(orders, order)
+		}
+		order.Items = append(order.Items, item)
+	}
+
+	// Every seller ship from its own origin once buyer choose shipping
+	if len(reqData.Shipping) > 0 {
+		for _, order := range orders {
+			for _, option := range reqData.Shipping {
+				if option.SellerID == order.SellerID {
+					order.Origin = option.Origin
+					order.Destination = reqData.Destination
+					order.Carrier = option.Carrier
+					order.Service = option.Service
+				}
+			}
+			if order.Service == "" {
+				err := fmt.Errorf(static.EmptyValue, fmt.Sprintf("Shipping penjual %v", order.SellerID))
+				l.Logger.Error(err)
+				return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+			}
+		}
+	}
+
+	// Voucher applied to order of the seller it was chosen for
+	for _, option := range reqData.Vouchers {
+		order, ok := sellers[option.SellerID]
+		if !ok {
+			err := fmt.Errorf(static.VoucherNotApplicable, option.Code)
+			l.Logger.Error(err)
+			return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+		}
+		order.VoucherCode = option.Code
+	}
+
+	// Every transaction displayed in one currency so checkout total can be summed
+	if reqData.Currency == "" && len(orders) > 1 {
+		for _, order := range orders {
+			order.Currency = config.Get().Currency.Base
+		}
+	}
+
+	return l.checkout(ctx, reqData.BuyerID, orders, tx)
+}
+
+// FindCheckout
+func (l *TransactionLogic) FindCheckout(ctx context.Context, reqData *dto.FindCheckoutRequest) (*model.Checkouts, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	checkout, err := l.TransactionRepo.FindCheckout(ctx, &model.Checkouts{
+		ID:      reqData.ID,
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "checkout"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, transaction := range checkout.Transactions {
+		transaction.StatusTransaction = transaction.Status.String()
+	}
+
+	return checkout, nil
+}
+
+// checkout create parent checkout then one transaction for every order
+func (l *TransactionLogic) checkout(ctx context.Context, buyerID int, orders []*dto.CreateOrderRequest, tx *gorm.DB) (*dto.CheckoutResponse, error) {
+	reference, err := paymentReference()
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	checkout := &model.Checkouts{
+		BuyerID:          buyerID,
+		PaymentReference: reference,
+	}
+	if _, err := l.TransactionRepo.CreateCheckout(ctx, checkout, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	response := &dto.CheckoutResponse{
+		Checkout: checkout,
+	}
+	for _, order := range orders {
+		transaction, coupons, err := l.createOrder(ctx, order, checkout.ID, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		checkout.GrandTotal = checkout.GrandTotal.Add(transaction.DisplayGrandTotal)
+		checkout.Currency = transaction.DisplayCurrency
+		checkout.Transactions = append(checkout.Transactions, transaction)
+		response.Coupons += coupons
+	}
+
+	if err := l.TransactionRepo.UpdateCheckout(ctx, checkout, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return response, nil
+}
+
+// createOrder create transaction of one seller and reserve its stock
+func (l *TransactionLogic) createOrder(ctx context.Context, reqData *dto.CreateOrderRequest, checkoutID int, tx *gorm.DB) (*model.Transactions, int, error) {
 	var (
-		grandTotal   float64
+		grandTotal   money.Amount
 		snapshotItem model.ItemsTransaction
+		reserveItem  []inventoryDto.ReserveItem
 		coupons      int
+		currency     string
 	)
 
 	// Find detail seller
@@ -64,7 +261,11 @@ func (l *TransactionLogic) CreateOrder(ctx context.Context, reqData *dto.CreateO
 	})
 	if err != nil {
 		l.Logger.Error(err)
-		return 0, err
+		return nil, 0, err
+	}
+
+	if sellerDetail.StoreClosedAt != nil {
+		return nil, 0, utilities.ErrorRequest(fmt.Errorf(static.StoreClosed, sellerDetail.Username), http.StatusBadRequest)
 	}
 
 	// Find detail buyer
@@ -73,48 +274,239 @@ func (l *TransactionLogic) CreateOrder(ctx context.Context, reqData *dto.CreateO
 	})
 	if err != nil {
 		l.Logger.Error(err)
-		return 0, err
+		return nil, 0, err
+	}
+
+	// Repeated product merged into one line
+	quantities := map[int]int{}
+	productIDs := []int{}
+	for _, orderItem := range reqData.Items {
+		if _, ok := quantities[orderItem.ProductID]; !ok {
+			productIDs = append(productIDs, orderItem.ProductID)
+		}
+		quantities[orderItem.ProductID] += orderItem.Quantity
 	}
 
 	// Validate product
-	for _, productID := range reqData.Items {
+	for _, productID := range productIDs {
+		quantity := quantities[productID]
 		productDetail, err := l.ProductLogic.Find(ctx, &productDto.FindRequest{
 			ID:       productID,
 			SellerID: sellerDetail.ID,
+			Status:   enum.ProductStatusTypePublished,
 		})
 		if err != nil {
 			l.Logger.Error(err)
-			return 0, err
+			return nil, 0, err
+		}
+
+		// Every product settled in one currency
+		if currency == "" {
+			currency = productDetail.Currency
+		}
+		if productDetail.Currency != currency {
+			err := errors.New(static.CurrencyMismatch)
+			l.Logger.Error(err)
+			return nil, 0, utilities.ErrorRequest(err, http.StatusBadRequest)
 		}
 
-		snapshotItem = append(snapshotItem, model.ProductTransaction{
+		// Charge sale price when a sale is running at order time
+		price := productDetail.EffectivePrice()
+		lineTotal := price.Mul(int64(quantity))
+		item := model.ProductTransaction{
 			ID:          productDetail.ID,
 			Name:        productDetail.Name,
 			Description: productDetail.Description,
-			Price:       productDetail.Price,
+			Price:       price,
+			Quantity:    quantity,
+			LineTotal:   lineTotal,
+			CategoryID:  productDetail.CategoryID,
+		}
+		if price.Cmp(productDetail.Price) != 0 {
+			item.OriginalPrice = &productDetail.Price
+		}
+		// Keep attributes with their unit so shipping can read weight and dimension later
+		if productDetail.Category != nil {
+			item.Attributes = productDetail.Category.Attributes.Items(productDetail.Attributes)
+		}
+		snapshotItem = append(snapshotItem, item)
+
+		reserveItem = append(reserveItem, inventoryDto.ReserveItem{
+			ProductID: productDetail.ID,
+			Quantity:  quantity,
 		})
 
-		grandTotal += productDetail.Price
+		grandTotal = grandTotal.Add(lineTotal)
+	}
+
+	// Coupon evaluated on item subtotal at order time, stored and issued as is once seller accept the order
+	earned, err := l.CouponLogic.Evaluate(ctx, &coupon.Order{
+		SellerID: sellerDetail.ID,
+		Currency: currency,
+		Items:    couponItems(snapshotItem),
+		At:       time.Now(),
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, 0, err
+	}
+	coupons = earned.Coupons
+	subtotal := grandTotal
+
+	// Voucher discount item subtotal first, coupon redeemed on what is left
+	var voucher *voucherDto.QuoteResponse
+	if reqData.VoucherCode != "" {
+		voucher, err = l.VoucherLogic.Quote(ctx, &voucherDto.QuoteRequest{
+			Code:     reqData.VoucherCode,
+			BuyerID:  buyerDetail.ID,
+			SellerID: sellerDetail.ID,
+			Subtotal: subtotal,
+			Currency: currency,
+		}, tx)
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, 0, err
+		}
+		grandTotal = grandTotal.Sub(voucher.Discount)
+	}
+
+	// Redeemed coupon valued in base currency, discount item subtotal only
+	var discount money.Amount
+	if reqData.RedeemCoupons > 0 {
+		rate, err := l.Exchange.Rate(ctx, config.Get().Currency.Base, currency)
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, 0, utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, currency), http.StatusBadRequest)
+		}
 
-		if productDetail.Price > 49999 && productDetail.Price < 99999 {
-			coupons++
+		discount = config.Get().Coupon.ValueAmount.Convert(rate.Value).Mul(int64(reqData.RedeemCoupons))
+		if discount.Cmp(grandTotal) > 0 {
+			return nil, 0, utilities.ErrorRequest(fmt.Errorf(static.CouponExceedTotal, grandTotal, currency), http.StatusBadRequest)
 		}
+		grandTotal = grandTotal.Sub(discount)
+	}
 
+	// Shipping fee charged in transaction currency, part of grand total but not counted for coupon
+	var (
+		quote  *shipping.Quote
+		weight int
+	)
+	if reqData.Service != "" {
+		for _, item := range snapshotItem {
+			weight += shipping.Weight(item.Attributes, config.Get().Shipping.DefaultWeight) * item.Quantity
+		}
+
+		quote, err = l.ShippingLogic.Select(ctx, &shippingDto.SelectRequest{
+			Origin:      reqData.Origin,
+			Destination: reqData.Destination,
+			Weight:      weight,
+			Carrier:     reqData.Carrier,
+			Service:     reqData.Service,
+			Currency:    currency,
+		})
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, 0, err
+		}
+		grandTotal = grandTotal.Add(quote.Fee)
 	}
 
-	coupons += int(grandTotal) / 100000
+	// Snapshot exchange rate used to display grand total to buyer
+	displayCurrency := currency
+	if reqData.Currency != "" {
+		displayCurrency = strings.ToUpper(reqData.Currency)
+	}
 
-	if _, err := l.TransactionRepo.Create(ctx, &model.Transactions{
-		BuyerID:    buyerDetail.ID,
-		SellerID:   sellerDetail.ID,
-		GrandTotal: grandTotal,
-		Status:     enum.TransactionStatusTypePending,
-		Items:      snapshotItem,
+	rate, err := l.Exchange.Rate(ctx, currency, displayCurrency)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, 0, utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, displayCurrency), http.StatusBadRequest)
+	}
+
+	rateAt := rate.AsOf
+	if rateAt.IsZero() {
+		rateAt = time.Now()
+	}
+
+	transaction := &model.Transactions{
+		CheckoutID:        &checkoutID,
+		BuyerID:           buyerDetail.ID,
+		SellerID:          sellerDetail.ID,
+		GrandTotal:        grandTotal,
+		Currency:          currency,
+		DisplayCurrency:   displayCurrency,
+		DisplayGrandTotal: grandTotal.Convert(rate.Value),
+		ExchangeRate:      rate.Value,
+		RateAt:            &rateAt,
+		Status:            enum.TransactionStatusTypePending,
+		Subtotal:          subtotal,
+		CouponsEarned:     &coupons,
+		CouponsRedeemed:   reqData.RedeemCoupons,
+		CouponDiscount:    discount,
+		Items:             snapshotItem,
+		Origin:            strings.TrimSpace(reqData.Origin),
+		Destination:       strings.TrimSpace(reqData.Destination),
+	}
+	if quote != nil {
+		transaction.ShippingCarrier = quote.Carrier
+		transaction.ShippingService = quote.Service
+		transaction.ShippingWeight = weight
+		transaction.ShippingFee = quote.Fee
+	}
+	if voucher != nil {
+		transaction.VoucherID = &voucher.Voucher.ID
+		transaction.VoucherCode = voucher.Voucher.Code
+		transaction.VoucherDiscount = voucher.Discount
+	}
+	transactionID, err := l.TransactionRepo.Create(ctx, transaction, tx)
+	if err != nil {
+		return nil, 0, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	transaction.StatusTransaction = transaction.Status.String()
+
+	if err := l.TransactionRepo.CreateStatusHistory(ctx, &model.TransactionStatusHistories{
+		TransactionID: *transactionID,
+		ToStatus:      enum.TransactionStatusTypePending,
+		ActorID:       &buyerDetail.ID,
+		ActorRole:     &reqData.RoleID,
 	}, tx); err != nil {
-		return 0, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		return nil, 0, utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
 
-	return coupons, nil
+	if reqData.RedeemCoupons > 0 {
+		if err := l.CouponLogic.Redeem(ctx, &couponDto.LedgerRequest{
+			BuyerID:       buyerDetail.ID,
+			TransactionID: *transactionID,
+			Coupons:       reqData.RedeemCoupons,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return nil, 0, err
+		}
+	}
+
+	if voucher != nil {
+		if err := l.VoucherLogic.Redeem(ctx, &voucherDto.RedeemRequest{
+			VoucherID:     voucher.Voucher.ID,
+			BuyerID:       buyerDetail.ID,
+			TransactionID: *transactionID,
+			Discount:      voucher.Discount,
+			Currency:      currency,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return nil, 0, err
+		}
+	}
+
+	// Reserve stock until order accepted
+	if err := l.InventoryLogic.Reserve(ctx, &inventoryDto.ReserveRequest{
+		TransactionID: *transactionID,
+		Items:         reserveItem,
+	}, tx); err != nil {
+		l.Logger.Error(err)
+		return nil, 0, err
+	}
+
+	return transaction, coupons, nil
 }
 
 // GetCouponsAndHistory
@@ -126,10 +518,8 @@ func (l *TransactionLogic) FindHistory(ctx context.Context, reqData *dto.FindHis
 	}
 
 	var (
-		whereData = model.Transactions{
-			Status: enum.TransactionStatusTypeAccept,
-		}
-		coupons int
+		whereData = model.Transactions{}
+		coupons   int
 	)
 
 	if reqData.RoleID == enum.RoleTypeBuyer {
@@ -138,7 +528,7 @@ func (l *TransactionLogic) FindHistory(ctx context.Context, reqData *dto.FindHis
 		whereData.SellerID = reqData.UserID
 	}
 
-	transactions, err := l.TransactionRepo.FindHistory(ctx, &whereData)
+	transactions, err := l.TransactionRepo.FindHistory(ctx, &whereData, enum.TransactionStatusFulfilled)
 	if err != nil {
 		l.Logger.Error(err)
 		if err == gorm.ErrRecordNotFound {
@@ -222,24 +612,398 @@ func (l *TransactionLogic) AcceptOrder(ctx context.Context, reqData *dto.AcceptO
 		return utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
 
-	var newCoupons int
-	for _, item := range transaction.Items {
-		if item.Price > 50000 && item.Price < 100000 {
-			newCoupons++
-		}
+	if transaction.PaidAt == nil {
+		return utilities.ErrorRequest(errors.New(static.OrderNotPaid), http.StatusConflict)
+	}
+
+	if err := l.transition(ctx, transaction, enum.TransactionStatusTypeAccept, reqData.SellerID, reqData.RoleID, "", tx); err != nil {
+		return err
 	}
 
-	newCoupons += int(transaction.GrandTotal) / 100000
+	// Coupon evaluated when the order was placed, rule changed since then does not apply
+	earned, err := l.earnedCoupons(ctx, transaction)
+	if err != nil {
+		return err
+	}
+
+	// Keep reserved stock for accepted order
+	if err := l.InventoryLogic.Commit(ctx, transaction.ID, tx); err != nil {
+		l.Logger.Error(err)
+		return err
+	}
 
 	if err := l.TransactionRepo.Update(ctx, &model.Transactions{
 		ID:       reqData.TransactionID,
 		SellerID: reqData.SellerID,
-		Status:   enum.TransactionStatusTypeAccept,
-		Coupons:  newCoupons,
+		Coupons:  earned,
 	}, tx); err != nil {
 		l.Logger.Error(err)
 		return utilities.ErrorRequest(err, http.StatusInternalServerError)
 	}
 
+	if earned > 0 {
+		if err := l.CouponLogic.Earn(ctx, &couponDto.LedgerRequest{
+			BuyerID:       transaction.BuyerID,
+			TransactionID: transaction.ID,
+			Coupons:       earned,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// earnedCoupons coupon stored when the order was placed, order placed before it was stored evaluated with rule valid at that time
+func (l *TransactionLogic) earnedCoupons(ctx context.Context, transaction *model.Transactions) (int, error) {
+	if transaction.CouponsEarned != nil {
+		return *transaction.CouponsEarned, nil
+	}
+
+	earned, err := l.CouponLogic.Evaluate(ctx, &coupon.Order{
+		SellerID: transaction.SellerID,
+		Currency: transaction.Currency,
+		Items:    couponItems(transaction.Items),
+		At:       transaction.CreatedAt,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+
+	return earned.Coupons, nil
+}
+
+// RejectOrder reason shown to buyer, reserved stock returned and paid order refunded
+func (l *TransactionLogic) RejectOrder(ctx context.Context, reqData *dto.RejectOrderRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.TransactionID, reqData.SellerID, reqData.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if err := l.transition(ctx, transaction, enum.TransactionStatusTypeRejected, reqData.SellerID, reqData.RoleID, strings.TrimSpace(reqData.Reason), tx); err != nil {
+		return err
+	}
+
+	if err := l.InventoryLogic.Release(ctx, transaction.ID, "order rejected", tx); err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	if err := l.revertCoupons(ctx, transaction, 0, transaction.CouponsRedeemed, tx); err != nil {
+		return err
+	}
+
+	if err := l.releaseVoucher(ctx, transaction, tx); err != nil {
+		return err
+	}
+
+	if err := l.refundPaid(ctx, transaction, strings.TrimSpace(reqData.Reason), tx); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// BulkOrder accept or reject every transaction on its own savepoint, one failed order does not undo the others
+func (l *TransactionLogic) BulkOrder(ctx context.Context, reqData *dto.BulkOrderRequest, tx *gorm.DB) (*dto.BulkOrderResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	response := &dto.BulkOrderResponse{
+		Results: []*dto.BulkOrderResult{},
+	}
+	seen := map[int]bool{}
+	for _, id := range reqData.TransactionIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		savepoint := fmt.Sprintf("bulk_order_%d", id)
+		if err := tx.SavePoint(savepoint).Error; err != nil {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		var err error
+		switch reqData.Action {
+		case dto.BulkActionAccept:
+			err = l.AcceptOrder(ctx, &dto.AcceptOrderRequest{
+				SellerID:      reqData.SellerID,
+				TransactionID: id,
+				RoleID:        reqData.RoleID,
+			}, tx)
+		case dto.BulkActionReject:
+			err = l.RejectOrder(ctx, &dto.RejectOrderRequest{
+				SellerID:      reqData.SellerID,
+				TransactionID: id,
+				Reason:        reqData.Reason,
+				RoleID:        reqData.RoleID,
+			}, tx)
+		}
+
+		result := &dto.BulkOrderResult{
+			TransactionID: id,
+			Success:       err == nil,
+			Code:          http.StatusOK,
+		}
+		if err != nil {
+			if err := tx.RollbackTo(savepoint).Error; err != nil {
+				return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+			}
+
+			result.Code = http.StatusInternalServerError
+			if errInfo := utilities.ParseError(err); errInfo != nil {
+				result.Code = errInfo.StatusCode
+			}
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// MarkPaid every pending unpaid transaction of checkout, paid transaction returned
+func (l *TransactionLogic) MarkPaid(ctx context.Context, checkoutID int, paidAt time.Time, tx *gorm.DB) ([]*model.Transactions, error) {
+	checkout, err := l.TransactionRepo.FindCheckout(ctx, &model.Checkouts{
+		ID: checkoutID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "checkout"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	paid := []*model.Transactions{}
+	for _, transaction := range checkout.Transactions {
+		if transaction.PaidAt != nil || transaction.Status != enum.TransactionStatusTypePending {
+			continue
+		}
+
+		transaction.PaidAt = &paidAt
+		if err := l.TransactionRepo.UpdatePaid(ctx, transaction, tx); err != nil {
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		transaction.StatusTransaction = transaction.Status.String()
+		paid = append(paid, transaction)
+	}
+
+	return paid, nil
+}
+
+// Refund record money to be returned through the gateway which collected it and reduce issued coupons in proportion, fully refunded order moved into refunded
+func (l *TransactionLogic) Refund(ctx context.Context, reqData *dto.RefundRequest, tx *gorm.DB) (*dto.RefundResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refund made by user follow the same rule as moving the order into refunded
+	if reqData.UserID != 0 {
+		if err := allowed(transaction.Status, enum.TransactionStatusTypeRefunded, reqData.UserID, reqData.RoleID); err != nil {
+			return nil, err
+		}
+	}
+
+	amount := reqData.Amount
+	if amount.IsZero() {
+		amount = transaction.GrandTotal.Sub(transaction.RefundedAmount)
+	}
+	if !amount.IsPositive() {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.RefundExceedOrder, amount, transaction.Currency), http.StatusConflict)
+	}
+
+	refund, err := l.refund(ctx, transaction, amount, reqData.ReturnID, reqData.Reason, tx)
+	if err != nil {
+		return nil, err
+	}
+	transaction.StatusTransaction = transaction.Status.String()
+
+	return &dto.RefundResponse{
+		Transaction: transaction,
+		Refund:      refund,
+	}, nil
+}
+
+// refund settle amount in transaction currency with buyer, fully refunded order return its stock, spent coupons and voucher unless already called off
+func (l *TransactionLogic) refund(ctx context.Context, transaction *model.Transactions, amount money.Amount, returnID *int, reason string, tx *gorm.DB) (*model.Refunds, error) {
+	refunded := transaction.RefundedAmount.Add(amount)
+	if refunded.Cmp(transaction.GrandTotal) > 0 {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.RefundExceedOrder, transaction.GrandTotal.Sub(transaction.RefundedAmount), transaction.Currency), http.StatusConflict)
+	}
+
+	// Buyer paid in display currency, convert with rate snapshot of the order
+	paid := amount
+	currency := transaction.Currency
+	if transaction.DisplayCurrency != "" && transaction.DisplayCurrency != transaction.Currency {
+		paid = amount.Convert(transaction.ExchangeRate)
+		currency = transaction.DisplayCurrency
+	}
+
+	// Shipping fee not part of the items, order fully refunded once every item paid back
+	items := transaction.GrandTotal.Sub(transaction.ShippingFee)
+	before := notNegative(items.Sub(transaction.RefundedAmount))
+	remaining := notNegative(items.Sub(refunded))
+	transaction.RefundedAmount = refunded
+	issued := transaction.Coupons
+	if before.IsPositive() {
+		transaction.Coupons = int(int64(transaction.Coupons) * remaining.Minor() / before.Minor())
+	}
+	if err := l.TransactionRepo.UpdateRefund(ctx, transaction, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	calledOff := transaction.Status == enum.TransactionStatusTypeCancelled || transaction.Status == enum.TransactionStatusTypeRejected
+
+	// Coupon spent on the order returned only once it is fully refunded
+	redeemed := 0
+	if remaining.IsZero() && transaction.Status != enum.TransactionStatusTypeRefunded {
+		if err := l.transition(ctx, transaction, enum.TransactionStatusTypeRefunded, 0, 0, reason, tx); err != nil {
+			return nil, err
+		}
+
+		if !calledOff {
+			redeemed = transaction.CouponsRedeemed
+			if err := l.releaseVoucher(ctx, transaction, tx); err != nil {
+				return nil, err
+			}
+
+			if err := l.InventoryLogic.Release(ctx, transaction.ID, "order refunded", tx); err != nil {
+				l.Logger.Error(err)
+				return nil, err
+			}
+		}
+	}
+
+	if err := l.revertCoupons(ctx, transaction, issued-transaction.Coupons, redeemed, tx); err != nil {
+		return nil, err
+	}
+
+	// Recorded as pending last, money only sent to the gateway once every change above committed
+	refund, err := l.RefundLogic.Refund(ctx, &refundDto.RefundRequest{
+		CheckoutID:    transaction.CheckoutID,
+		TransactionID: transaction.ID,
+		ReturnID:      returnID,
+		Amount:        paid,
+		Currency:      currency,
+		Reason:        reason,
+	}, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// refundPaid return whatever buyer paid for the order and not yet refunded, unpaid order has nothing to return
+func (l *TransactionLogic) refundPaid(ctx context.Context, transaction *model.Transactions, reason string, tx *gorm.DB) error {
+	if transaction.PaidAt == nil {
+		return nil
+	}
+
+	outstanding := transaction.GrandTotal.Sub(transaction.RefundedAmount)
+	if !outstanding.IsPositive() {
+		return nil
+	}
+
+	if _, err := l.refund(ctx, transaction, outstanding, nil, reason, tx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notNegative amount floored at zero
+func notNegative(amount money.Amount) money.Amount {
+	if amount.IsNegative() {
+		return 0
+	}
+	return amount
+}
+
+// paymentReference shared by every transaction of one checkout
+func paymentReference() (string, error) {
+	random := make([]byte, 6)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("PAY-%s-%s", time.Now().Format("20060102"), strings.ToUpper(hex.EncodeToString(random))), nil
+}
+
+// revertCoupons take back coupon issued by the order and return coupon spent on it
+func (l *TransactionLogic) revertCoupons(ctx context.Context, transaction *model.Transactions, issued int, redeemed int, tx *gorm.DB) error {
+	if issued > 0 {
+		if err := l.CouponLogic.RevertEarn(ctx, &couponDto.LedgerRequest{
+			BuyerID:       transaction.BuyerID,
+			TransactionID: transaction.ID,
+			Coupons:       issued,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return err
+		}
+	}
+
+	if redeemed > 0 {
+		if err := l.CouponLogic.RevertRedeem(ctx, &couponDto.LedgerRequest{
+			BuyerID:       transaction.BuyerID,
+			TransactionID: transaction.ID,
+			Coupons:       redeemed,
+		}, tx); err != nil {
+			l.Logger.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseVoucher voucher used by the order counted again toward its caps
+func (l *TransactionLogic) releaseVoucher(ctx context.Context, transaction *model.Transactions, tx *gorm.DB) error {
+	if transaction.VoucherID == nil {
+		return nil
+	}
+
+	if err := l.VoucherLogic.Release(ctx, transaction.ID, tx); err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// couponItems item snapshot priced as charged, used for coupon evaluation
+func couponItems(items model.ItemsTransaction) []coupon.Item {
+	result := make([]coupon.Item, 0, len(items))
+	for _, item := range items {
+		result = append(result, coupon.Item{
+			ProductID:  item.ID,
+			CategoryID: item.CategoryID,
+			Price:      item.Price,
+			Quantity:   item.Quantity,
+		})
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/transaction/dto"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// transitions every allowed status change along with role permitted to make it
+var transitions = map[enum.TransactionStatusType]map[enum.TransactionStatusType][]enum.RoleType{
+	enum.TransactionStatusTypePending: {
+		enum.TransactionStatusTypeAccept:    {enum.RoleTypeSeller},
+		enum.TransactionStatusTypeRejected:  {enum.RoleTypeSeller},
+		enum.TransactionStatusTypeCancelled: {enum.RoleTypeBuyer, enum.RoleTypeAdmin},
+	},
+	enum.TransactionStatusTypeAccept: {
+		enum.TransactionStatusTypePacked:    {enum.RoleTypeSeller},
+		enum.TransactionStatusTypeCancelled: {enum.RoleTypeBuyer, enum.RoleTypeSeller, enum.RoleTypeAdmin},
+	},
+	enum.TransactionStatusTypePacked: {
+		enum.TransactionStatusTypeShipped:   {enum.RoleTypeSeller},
+		enum.TransactionStatusTypeCancelled: {enum.RoleTypeSeller, enum.RoleTypeAdmin},
+	},
+	enum.TransactionStatusTypeShipped: {
+		enum.TransactionStatusTypeDelivered: {enum.RoleTypeSeller, enum.RoleTypeAdmin},
+	},
+	enum.TransactionStatusTypeDelivered: {
+		enum.TransactionStatusTypeCompleted: {enum.RoleTypeBuyer, enum.RoleTypeAdmin},
+		enum.TransactionStatusTypeRefunded:  {enum.RoleTypeAdmin},
+	},
+	enum.TransactionStatusTypeCompleted: {
+		enum.TransactionStatusTypeRefunded: {enum.RoleTypeAdmin},
+	},
+	// Paid order called off refunded by system right after
+	enum.TransactionStatusTypeRejected: {
+		enum.TransactionStatusTypeRefunded: {},
+	},
+	enum.TransactionStatusTypeCancelled: {
+		enum.TransactionStatusTypeRefunded: {},
+	},
+}
+
+// UpdateStatus move transaction owned by the user into the requested status
+func (l *TransactionLogic) UpdateStatus(ctx context.Context, reqData *dto.UpdateStatusRequest, tx *gorm.DB) (*model.Transactions, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shipped order carry tracking number so buyer can follow it
+	if reqData.Status == enum.TransactionStatusTypeShipped {
+		if err := l.attachTracking(ctx, transaction, reqData, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.transition(ctx, transaction, reqData.Status, reqData.UserID, reqData.RoleID, reqData.Reason, tx); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// FindStatusHistory every status change of transaction, oldest first
+func (l *TransactionLogic) FindStatusHistory(ctx context.Context, reqData *dto.StatusHistoryRequest) ([]*model.TransactionStatusHistories, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := l.TransactionRepo.FindStatusHistories(ctx, &model.TransactionStatusHistories{
+		TransactionID: transaction.ID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, history := range histories {
+		if history.FromStatus != nil {
+			history.From = history.FromStatus.String()
+		}
+		history.To = history.ToStatus.String()
+		if history.ActorRole != nil {
+			history.Role = history.ActorRole.String()
+		}
+	}
+
+	return histories, nil
+}
+
+// Find transaction owned by the user, admin find any transaction
+func (l *TransactionLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.Transactions, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	transaction.StatusTransaction = transaction.Status.String()
+
+	return transaction, nil
+}
+
+// findOwned seller and buyer only see their own transaction, admin see every transaction
+func (l *TransactionLogic) findOwned(ctx context.Context, id int, userID int, role enum.RoleType) (*model.Transactions, error) {
+	whereData := &model.Transactions{
+		ID: id,
+	}
+	switch role {
+	case enum.RoleTypeSeller:
+		whereData.SellerID = userID
+	case enum.RoleTypeBuyer:
+		whereData.BuyerID = userID
+	}
+
+	transaction, err := l.TransactionRepo.Find(ctx, whereData)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "transaksi"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return transaction, nil
+}
+
+// transition move transaction into next status and record who made the change, zero actor means system
+func (l *TransactionLogic) transition(ctx context.Context, transaction *model.Transactions, to enum.TransactionStatusType, actorID int, role enum.RoleType, reason string, tx *gorm.DB) error {
+	from := transaction.Status
+
+	if err := allowed(from, to, actorID, role); err != nil {
+		return err
+	}
+
+	update := &model.Transactions{
+		ID:     transaction.ID,
+		Status: to,
+	}
+	if reason != "" {
+		update.StatusReason = &reason
+	}
+
+	affected, err := l.TransactionRepo.UpdateStatus(ctx, update, from, tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return utilities.ErrorRequest(errors.New(static.TransactionChanged), http.StatusConflict)
+	}
+
+	history := &model.TransactionStatusHistories{
+		TransactionID: transaction.ID,
+		FromStatus:    &from,
+		ToStatus:      to,
+	}
+	if actorID != 0 {
+		history.ActorID = &actorID
+		history.ActorRole = &role
+	}
+	history.Reason = update.StatusReason
+	if err := l.TransactionRepo.CreateStatusHistory(ctx, history, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	transaction.Status = to
+	transaction.StatusReason = update.StatusReason
+	transaction.StatusTransaction = to.String()
+
+	return nil
+}
+
+// allowed check transition exist and role may make it, zero actor means system
+func allowed(from, to enum.TransactionStatusType, actorID int, role enum.RoleType) error {
+	roles, ok := transitions[from][to]
+	if !ok {
+		return utilities.ErrorRequest(fmt.Errorf(static.InvalidTransition, from, to), http.StatusConflict)
+	}
+	if actorID != 0 && !permitted(roles, role) {
+		return utilities.ErrorRequest(errors.New(static.Authorization), http.StatusForbidden)
+	}
+	return nil
+}
+
+func permitted(roles []enum.RoleType, role enum.RoleType) bool {
+	for _, allowed := range roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
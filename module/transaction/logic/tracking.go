@@ -0,0 +1,251 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/transaction/dto"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/package/tracking"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"gorm.io/gorm"
+)
+
+// trackingStatuses carrier status stored as tracking status
+var trackingStatuses = map[string]enum.TrackingStatusType{
+	tracking.StatusPickedUp:       enum.TrackingStatusTypePickedUp,
+	tracking.StatusInTransit:      enum.TrackingStatusTypeInTransit,
+	tracking.StatusOutForDelivery: enum.TrackingStatusTypeOutForDelivery,
+	tracking.StatusDelivered:      enum.TrackingStatusTypeDelivered,
+	tracking.StatusFailed:         enum.TrackingStatusTypeFailed,
+}
+
+// Tracking timeline of shipment reported by carrier, oldest first
+func (l *TransactionLogic) Tracking(ctx context.Context, reqData *dto.TrackingRequest) (*dto.TrackingResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	transaction, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TrackingResponse{
+		TransactionID:  transaction.ID,
+		Status:         transaction.Status.String(),
+		Carrier:        transaction.ShippingCarrier,
+		TrackingNumber: transaction.TrackingNumber,
+		Events:         []*model.TrackingEvents{},
+	}
+	if transaction.TrackingNumber == "" {
+		return response, nil
+	}
+
+	response.Events, err = l.TransactionRepo.FindTrackingEvents(ctx, &model.TrackingEvents{
+		TransactionID: transaction.ID,
+	})
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, event := range response.Events {
+		event.StatusTracking = event.Status.String()
+	}
+
+	return response, nil
+}
+
+// TrackingWebhook called by carrier, checkpoint of unknown tracking number skipped so the rest of the batch still recorded
+func (l *TransactionLogic) TrackingWebhook(ctx context.Context, reqData *dto.TrackingWebhookRequest, tx *gorm.DB) ([]*model.TrackingEvents, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	events, err := l.Tracker.ParseWebhook(reqData.Payload, reqData.Signature)
+	if err != nil {
+		l.Logger.Error(err)
+		if errors.Is(err, tracking.ErrInvalidSignature) {
+			return nil, utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized)
+		}
+		return nil, utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest)
+	}
+
+	shipments := map[string][]*tracking.Event{}
+	keys := []string{}
+	for _, event := range events {
+		key := event.Carrier + "/" + event.TrackingNumber
+		if _, ok := shipments[key]; !ok {
+			keys = append(keys, key)
+		}
+		shipments[key] = append(shipments[key], event)
+	}
+
+	result := []*model.TrackingEvents{}
+	for _, key := range keys {
+		first := shipments[key][0]
+		transaction, err := l.TransactionRepo.FindByTracking(ctx, &model.Transactions{
+			ShippingCarrier: first.Carrier,
+			TrackingNumber:  first.TrackingNumber,
+		})
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		recorded, err := l.recordTracking(ctx, transaction, shipments[key], tx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, recorded...)
+	}
+
+	return result, nil
+}
+
+// PollTracking ask carrier for checkpoint of every shipped order, each order recorded in its own transaction
+func (l *TransactionLogic) PollTracking(ctx context.Context) error {
+	transactions, err := l.TransactionRepo.FindTracked(ctx, []enum.TransactionStatusType{enum.TransactionStatusTypeShipped})
+	if err != nil {
+		return err
+	}
+
+	for _, transaction := range transactions {
+		events, err := l.Tracker.Track(ctx, transaction.ShippingCarrier, transaction.TrackingNumber)
+		if err != nil {
+			l.Logger.Error(err)
+			continue
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		ctx, outbox := notifier.WithOutbox(ctx)
+		tx := l.Database.Gorm.Begin()
+		if _, err := l.recordTracking(ctx, transaction, events, tx); err != nil {
+			l.Logger.Error(err)
+			tx.Rollback()
+			continue
+		}
+		if err := tx.Commit().Error; err != nil {
+			l.Logger.Error(err)
+			continue
+		}
+
+		if err := outbox.Send(ctx, l.Notifier); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// attachTracking carrier chosen at checkout used when seller leave it empty
+func (l *TransactionLogic) attachTracking(ctx context.Context, transaction *model.Transactions, reqData *dto.UpdateStatusRequest, tx *gorm.DB) error {
+	if err := allowed(transaction.Status, reqData.Status, reqData.UserID, reqData.RoleID); err != nil {
+		return err
+	}
+
+	carrier := strings.ToUpper(strings.TrimSpace(reqData.Carrier))
+	if carrier == "" {
+		carrier = transaction.ShippingCarrier
+	}
+	if carrier == "" {
+		return utilities.ErrorRequest(fmt.Errorf(static.EmptyValue, "Carrier"), http.StatusBadRequest)
+	}
+	trackingNumber := strings.ToUpper(strings.TrimSpace(reqData.TrackingNumber))
+	if trackingNumber == "" {
+		return utilities.ErrorRequest(fmt.Errorf(static.EmptyValue, "TrackingNumber"), http.StatusBadRequest)
+	}
+
+	transaction.ShippingCarrier = carrier
+	transaction.TrackingNumber = trackingNumber
+	if err := l.TransactionRepo.UpdateTracking(ctx, transaction, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// recordTracking store new checkpoint, delivered checkpoint move shipped order into delivered as system
+func (l *TransactionLogic) recordTracking(ctx context.Context, transaction *model.Transactions, events []*tracking.Event, tx *gorm.DB) ([]*model.TrackingEvents, error) {
+	rows := make([]*model.TrackingEvents, 0, len(events))
+	delivered := false
+	for _, event := range events {
+		row := &model.TrackingEvents{
+			TransactionID:  transaction.ID,
+			Carrier:        transaction.ShippingCarrier,
+			TrackingNumber: transaction.TrackingNumber,
+			Status:         trackingStatuses[event.Status],
+			Description:    event.Description,
+			OccurredAt:     event.OccurredAt,
+		}
+		if event.Location != "" {
+			location := event.Location
+			row.Location = &location
+		}
+		row.StatusTracking = row.Status.String()
+		rows = append(rows, row)
+
+		if row.Status == enum.TrackingStatusTypeDelivered {
+			delivered = true
+		}
+	}
+
+	affected, err := l.TransactionRepo.CreateTrackingEvents(ctx, rows, tx)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	// Every checkpoint already stored by earlier webhook or poll
+	if affected == 0 {
+		return rows, nil
+	}
+
+	if delivered && transaction.Status == enum.TransactionStatusTypeShipped {
+		if err := l.transition(ctx, transaction, enum.TransactionStatusTypeDelivered, 0, 0, "", tx); err != nil {
+			return nil, err
+		}
+	}
+
+	latest := rows[0]
+	for _, row := range rows {
+		if row.OccurredAt.After(latest.OccurredAt) {
+			latest = row
+		}
+	}
+	description := latest.Description
+	if description == "" {
+		description = latest.StatusTracking
+	}
+
+	// Sent once the checkpoint committed, failed delivery should not undo it
+	if err := notifier.Defer(ctx, &notifier.Message{
+		UserID: transaction.BuyerID,
+		Event:  notifier.EventShipmentUpdated,
+		Title:  static.ShipmentUpdatedTitle,
+		Body:   fmt.Sprintf(static.ShipmentUpdatedBody, transaction.ID, description),
+		Data: map[string]interface{}{
+			"TransactionID":  transaction.ID,
+			"Carrier":        transaction.ShippingCarrier,
+			"TrackingNumber": transaction.TrackingNumber,
+			"Status":         latest.StatusTracking,
+		},
+	}); err != nil {
+		l.Logger.Error(err)
+	}
+
+	return rows, nil
+}
@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"pcstakehometest/database/postgres"
+	"pcstakehometest/enum"
 	"pcstakehometest/model"
 	"pcstakehometest/package/logger"
 
 	"go.uber.org/fx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepository
@@ -18,7 +20,25 @@ type ITransactionRepository interface {
 	FindAll(context.Context, *model.Transactions) ([]*model.Transactions, error)
 	Find(context.Context, *model.Transactions) (*model.Transactions, error)
 	Update(context.Context, *model.Transactions, *gorm.DB) error
-	FindHistory(context.Context, *model.Transactions) ([]*model.Transactions, error)
+	FindHistory(context.Context, *model.Transactions, []enum.TransactionStatusType) ([]*model.Transactions, error)
+	UpdateStatus(context.Context, *model.Transactions, enum.TransactionStatusType, *gorm.DB) (int64, error)
+	CreateStatusHistory(context.Context, *model.TransactionStatusHistories, *gorm.DB) error
+	FindStatusHistories(context.Context, *model.TransactionStatusHistories) ([]*model.TransactionStatusHistories, error)
+	CreateCheckout(context.Context, *model.Checkouts, *gorm.DB) (*int, error)
+	UpdateCheckout(context.Context, *model.Checkouts, *gorm.DB) error
+	FindCheckout(context.Context, *model.Checkouts) (*model.Checkouts, error)
+	UpdateCoupons(context.Context, *model.Transactions, *gorm.DB) error
+	UpdatePaid(context.Context, *model.Transactions, *gorm.DB) error
+	UpdateRefund(context.Context, *model.Transactions, *gorm.DB) error
+	FindLatestStatusHistory(context.Context, *model.TransactionStatusHistories) (*model.TransactionStatusHistories, error)
+	CreateCancellation(context.Context, *model.Cancellations, *gorm.DB) (*int, error)
+	FindCancellation(context.Context, *model.Cancellations) (*model.Cancellations, error)
+	UpdateCancellation(context.Context, *model.Cancellations, *gorm.DB) error
+	UpdateTracking(context.Context, *model.Transactions, *gorm.DB) error
+	FindByTracking(context.Context, *model.Transactions) (*model.Transactions, error)
+	FindTracked(context.Context, []enum.TransactionStatusType) ([]*model.Transactions, error)
+	CreateTrackingEvents(context.Context, []*model.TrackingEvents, *gorm.DB) (int64, error)
+	FindTrackingEvents(context.Context, *model.TrackingEvents) ([]*model.TrackingEvents, error)
 }
 
 type TransactionRepository struct {
@@ -62,13 +82,14 @@ func (l *TransactionRepository) FindAll(ctx context.Context, reqData *model.Tran
 	return transactions, nil
 }
 
-func (l *TransactionRepository) FindHistory(ctx context.Context, reqData *model.Transactions) ([]*model.Transactions, error) {
+func (l *TransactionRepository) FindHistory(ctx context.Context, reqData *model.Transactions, statuses []enum.TransactionStatusType) ([]*model.Transactions, error) {
 	transactions := []*model.Transactions{}
 
 	query := l.Database.Gorm.WithContext(ctx).Model(&model.Transactions{}).
 		Preload("Seller").
 		Preload("Buyer").
 		Where(reqData).
+		Where("status in ?", statuses).
 		Order("id desc")
 
 	if err := query.Find(&transactions).Error; err != nil {
@@ -86,6 +107,7 @@ func (l *TransactionRepository) Find(ctx context.Context, reqData *model.Transac
 		Where(&model.Transactions{
 			ID:       reqData.ID,
 			SellerID: reqData.SellerID,
+			BuyerID:  reqData.BuyerID,
 		}).
 		First(&transaction).Error; err != nil {
 		l.Logger.Error(err)
@@ -109,3 +131,270 @@ func (l *TransactionRepository) Update(ctx context.Context, reqData *model.Trans
 	}
 	return nil
 }
+
+// UpdateStatus only move transaction still in the given status, return zero when status already changed, reason replaced along with status
+func (l *TransactionRepository) UpdateStatus(ctx context.Context, reqData *model.Transactions, from enum.TransactionStatusType, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).Model(&model.Transactions{}).
+		Where("id = ?", reqData.ID).
+		Where("status = ?", from).
+		Updates(map[string]interface{}{
+			"status":        reqData.Status,
+			"status_reason": reqData.StatusReason,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CreateStatusHistory
+func (l *TransactionRepository) CreateStatusHistory(ctx context.Context, reqData *model.TransactionStatusHistories, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindStatusHistories oldest first
+func (l *TransactionRepository) FindStatusHistories(ctx context.Context, reqData *model.TransactionStatusHistories) ([]*model.TransactionStatusHistories, error) {
+	histories := []*model.TransactionStatusHistories{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.TransactionStatusHistories{}).
+		Where(&model.TransactionStatusHistories{
+			TransactionID: reqData.TransactionID,
+		}).
+		Order("id asc").
+		Find(&histories).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// CreateCheckout
+func (l *TransactionRepository) CreateCheckout(ctx context.Context, reqData *model.Checkouts, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// UpdateCheckout
+func (l *TransactionRepository) UpdateCheckout(ctx context.Context, reqData *model.Checkouts, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Checkouts{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"grand_total": reqData.GrandTotal,
+			"currency":    reqData.Currency,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindCheckout along with every transaction of the checkout
+func (l *TransactionRepository) FindCheckout(ctx context.Context, reqData *model.Checkouts) (*model.Checkouts, error) {
+	checkout := new(model.Checkouts)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id asc")
+		}).
+		Preload("Transactions.Seller").
+		Where(&model.Checkouts{
+			ID:               reqData.ID,
+			BuyerID:          reqData.BuyerID,
+			PaymentReference: reqData.PaymentReference,
+		}).
+		First(&checkout).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return checkout, nil
+}
+
+// UpdateCoupons zero value allowed so issued coupons can be revoked
+func (l *TransactionRepository) UpdateCoupons(ctx context.Context, reqData *model.Transactions, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Transactions{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"coupons":    reqData.Coupons,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePaid
+func (l *TransactionRepository) UpdatePaid(ctx context.Context, reqData *model.Transactions, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Transactions{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"paid_at":    reqData.PaidAt,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateRefund refunded amount along with coupons left after refund
+func (l *TransactionRepository) UpdateRefund(ctx context.Context, reqData *model.Transactions, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Transactions{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"refunded_amount": reqData.RefundedAmount,
+			"coupons":         reqData.Coupons,
+			"updated_at":      time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindLatestStatusHistory most recent change matching the filter
+func (l *TransactionRepository) FindLatestStatusHistory(ctx context.Context, reqData *model.TransactionStatusHistories) (*model.TransactionStatusHistories, error) {
+	history := new(model.TransactionStatusHistories)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.TransactionStatusHistories{
+			TransactionID: reqData.TransactionID,
+			ToStatus:      reqData.ToStatus,
+		}).
+		Order("id desc").
+		First(&history).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return history, nil
+}
+
+// CreateCancellation
+func (l *TransactionRepository) CreateCancellation(ctx context.Context, reqData *model.Cancellations, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindCancellation
+func (l *TransactionRepository) FindCancellation(ctx context.Context, reqData *model.Cancellations) (*model.Cancellations, error) {
+	cancellation := new(model.Cancellations)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Cancellations{
+			ID:            reqData.ID,
+			TransactionID: reqData.TransactionID,
+			SellerID:      reqData.SellerID,
+			BuyerID:       reqData.BuyerID,
+			Status:        reqData.Status,
+		}).
+		Order("id desc").
+		First(&cancellation).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return cancellation, nil
+}
+
+// UpdateCancellation
+func (l *TransactionRepository) UpdateCancellation(ctx context.Context, reqData *model.Cancellations, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Cancellations{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"status":      reqData.Status,
+			"note":        reqData.Note,
+			"resolved_at": reqData.ResolvedAt,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateTracking carrier and tracking number attached when order shipped
+func (l *TransactionRepository) UpdateTracking(ctx context.Context, reqData *model.Transactions, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Transactions{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"shipping_carrier": reqData.ShippingCarrier,
+			"tracking_number":  reqData.TrackingNumber,
+			"updated_at":       time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindByTracking
+func (l *TransactionRepository) FindByTracking(ctx context.Context, reqData *model.Transactions) (*model.Transactions, error) {
+	transaction := new(model.Transactions)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where("shipping_carrier = ?", reqData.ShippingCarrier).
+		Where("tracking_number = ?", reqData.TrackingNumber).
+		Order("id desc").
+		First(&transaction).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// FindTracked transaction in the given status having tracking number
+func (l *TransactionRepository) FindTracked(ctx context.Context, statuses []enum.TransactionStatusType) ([]*model.Transactions, error) {
+	transactions := []*model.Transactions{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Transactions{}).
+		Where("status in ?", statuses).
+		Where("tracking_number <> ''").
+		Order("id asc").
+		Find(&transactions).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CreateTrackingEvents checkpoint already stored skipped, return number of new checkpoint
+func (l *TransactionRepository) CreateTrackingEvents(ctx context.Context, reqData []*model.TrackingEvents, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&reqData)
+	if result.Error != nil {
+		l.Logger.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// FindTrackingEvents oldest first
+func (l *TransactionRepository) FindTrackingEvents(ctx context.Context, reqData *model.TrackingEvents) ([]*model.TrackingEvents, error) {
+	events := []*model.TrackingEvents{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.TrackingEvents{}).
+		Where(&model.TrackingEvents{
+			TransactionID: reqData.TransactionID,
+		}).
+		Order("occurred_at asc, id asc").
+		Find(&events).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return events, nil
+}
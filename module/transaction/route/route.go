@@ -2,6 +2,7 @@ package route
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"pcstakehometest/enum"
 
@@ -10,6 +11,7 @@ import (
 	"pcstakehometest/module/transaction/logic"
 	"pcstakehometest/package/jwt"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/tracking"
 	"pcstakehometest/router"
 	"pcstakehometest/static"
 	"pcstakehometest/utilities"
@@ -34,9 +36,24 @@ func NewRoute(h Handler, m ...echo.MiddlewareFunc) Handler {
 func (h *Handler) Route(m ...echo.MiddlewareFunc) {
 	transaction := h.EchoRoute.Group("/v1/transaction", m...)
 	transaction.GET("", h.FindAll, h.EchoRoute.Authentication)
-	transaction.POST("", h.CreateOrder, h.EchoRoute.Authentication)
-	transaction.POST("", h.AcceptOrder, h.EchoRoute.Authentication)
+	transaction.POST("", h.CreateOrder, h.EchoRoute.Authentication, h.EchoRoute.Idempotency)
 	transaction.GET("/history", h.History, h.EchoRoute.Authentication)
+	transaction.POST("/checkout", h.Checkout, h.EchoRoute.Authentication, h.EchoRoute.Idempotency)
+	transaction.GET("/checkout/:id", h.FindCheckout, h.EchoRoute.Authentication)
+	transaction.POST("/bulk", h.BulkOrder, h.EchoRoute.Authentication)
+	transaction.POST("/:id/accept", h.AcceptOrder, h.EchoRoute.Authentication)
+	transaction.POST("/:id/reject", h.RejectOrder, h.EchoRoute.Authentication)
+	transaction.POST("/:id/cancel", h.CancelOrder, h.EchoRoute.Authentication)
+	transaction.POST("/:id/cancel/approve", h.ApproveCancellation, h.EchoRoute.Authentication)
+	transaction.POST("/:id/cancel/decline", h.DeclineCancellation, h.EchoRoute.Authentication)
+	transaction.POST("/:id/pack", h.Pack, h.EchoRoute.Authentication)
+	transaction.POST("/:id/ship", h.Ship, h.EchoRoute.Authentication)
+	transaction.POST("/:id/deliver", h.Deliver, h.EchoRoute.Authentication)
+	transaction.POST("/:id/complete", h.Complete, h.EchoRoute.Authentication)
+	transaction.POST("/:id/refund", h.Refund, h.EchoRoute.Authentication, h.EchoRoute.Idempotency)
+	transaction.GET("/:id/history", h.StatusHistory, h.EchoRoute.Authentication)
+	transaction.GET("/:id/tracking", h.Tracking, h.EchoRoute.Authentication)
+	transaction.POST("/tracking/webhook", h.TrackingWebhook)
 }
 
 // FindAll
@@ -100,9 +117,8 @@ func (h *Handler) CreateOrder(c echo.Context) error {
 			Error: err,
 		})
 	}
-	tx.Commit()
 
-	return utilities.Response(c, &utilities.ResponseRequest{
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
 		Code:   http.StatusOK,
 		Status: static.Success,
 		Data: map[string]interface{}{
@@ -111,6 +127,84 @@ func (h *Handler) CreateOrder(c echo.Context) error {
 	})
 }
 
+// Checkout
+func (h *Handler) Checkout(c echo.Context) error {
+	var reqData = new(dto.CheckoutRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Checkout(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// FindCheckout
+func (h *Handler) FindCheckout(c echo.Context) error {
+	var reqData = new(dto.FindCheckoutRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindCheckout(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
 // AcceptOrder
 func (h *Handler) AcceptOrder(c echo.Context) error {
 	var reqData = new(dto.AcceptOrderRequest)
@@ -129,6 +223,16 @@ func (h *Handler) AcceptOrder(c echo.Context) error {
 		})
 	}
 
+	// Path id take precedence over TransactionID in body
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.TransactionID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
 	reqData.SellerID = data.UserID
 	reqData.RoleID = data.Role
 
@@ -150,6 +254,203 @@ func (h *Handler) AcceptOrder(c echo.Context) error {
 	})
 }
 
+// RejectOrder
+func (h *Handler) RejectOrder(c echo.Context) error {
+	var reqData = new(dto.RejectOrderRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.TransactionID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	if err := h.Logic.RejectOrder(c.Request().Context(), reqData, tx); err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+	})
+}
+
+// BulkOrder
+func (h *Handler) BulkOrder(c echo.Context) error {
+	var reqData = new(dto.BulkOrderRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.BulkOrder(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// CancelOrder
+func (h *Handler) CancelOrder(c echo.Context) error {
+	var reqData = new(dto.CancelOrderRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.TransactionID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.BuyerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.CancelOrder(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// ApproveCancellation
+func (h *Handler) ApproveCancellation(c echo.Context) error {
+	return h.resolveCancellation(c, true)
+}
+
+// DeclineCancellation
+func (h *Handler) DeclineCancellation(c echo.Context) error {
+	return h.resolveCancellation(c, false)
+}
+
+func (h *Handler) resolveCancellation(c echo.Context, approve bool) error {
+	var reqData = new(dto.ResolveCancellationRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.TransactionID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.Approve = approve
+	reqData.SellerID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.ResolveCancellation(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
 // GetCouponsAndHistory
 func (h *Handler) History(c echo.Context) error {
 	var reqData = new(dto.FindHistory)
@@ -181,3 +482,234 @@ func (h *Handler) History(c echo.Context) error {
 		},
 	})
 }
+
+// Pack
+func (h *Handler) Pack(c echo.Context) error {
+	return h.updateStatus(c, enum.TransactionStatusTypePacked)
+}
+
+// Ship
+func (h *Handler) Ship(c echo.Context) error {
+	return h.updateStatus(c, enum.TransactionStatusTypeShipped)
+}
+
+// Deliver
+func (h *Handler) Deliver(c echo.Context) error {
+	return h.updateStatus(c, enum.TransactionStatusTypeDelivered)
+}
+
+// Complete
+func (h *Handler) Complete(c echo.Context) error {
+	return h.updateStatus(c, enum.TransactionStatusTypeCompleted)
+}
+
+// Refund admin return money to buyer through payment gateway, empty Amount refund everything not yet refunded
+func (h *Handler) Refund(c echo.Context) error {
+	var reqData = new(dto.RefundRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.ReturnID = nil
+	reqData.UserID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.Refund(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// updateStatus shared handler of every transition endpoint, role check done by state machine
+func (h *Handler) updateStatus(c echo.Context, status enum.TransactionStatusType) error {
+	var reqData = new(dto.UpdateStatusRequest)
+
+	if err := c.Bind(reqData); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.Status = status
+	reqData.UserID = data.UserID
+	reqData.RoleID = data.Role
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.UpdateStatus(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+	tx.Commit()
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// StatusHistory
+func (h *Handler) StatusHistory(c echo.Context) error {
+	var reqData = new(dto.StatusHistoryRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.UserID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.FindStatusHistory(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// Tracking
+func (h *Handler) Tracking(c echo.Context) error {
+	var reqData = new(dto.TrackingRequest)
+
+	data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+	if !ok {
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+		})
+	}
+
+	if err := echo.PathParamsBinder(c).
+		Int("id", (&reqData.ID)).
+		BindError(); err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData.UserID = data.UserID
+	reqData.RoleID = data.Role
+
+	resp, err := h.Logic.Tracking(c.Request().Context(), reqData)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return utilities.Response(c, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
+
+// TrackingWebhook called by carrier, authenticated by body signature instead of user token
+func (h *Handler) TrackingWebhook(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.Logger.Error(err)
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+		})
+	}
+
+	reqData := &dto.TrackingWebhookRequest{
+		Payload:   payload,
+		Signature: c.Request().Header.Get(tracking.HeaderSignature),
+	}
+
+	tx := h.Db.Gorm.Begin()
+	resp, err := h.Logic.TrackingWebhook(c.Request().Context(), reqData, tx)
+	if err != nil {
+		h.Logger.Error(err)
+		defer func() {
+			tx.Rollback()
+		}()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	return h.EchoRoute.Commit(c, tx, &utilities.ResponseRequest{
+		Code:   http.StatusOK,
+		Status: static.Success,
+		Data:   resp,
+	})
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/module/transaction/logic"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/scheduler"
+
+	"go.uber.org/fx"
+)
+
+type Worker struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logic     logic.ITransactionLogic
+	Logger    *logger.LogRus
+}
+
+func NewWorker(w Worker) {
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "PollShipmentTracking",
+		Interval: config.Get().Tracking.PollIntervalDuration,
+		Run:      w.Logic.PollTracking,
+	})
+	scheduler.Register(w.Lifecycle, w.Logger, scheduler.Job{
+		Name:     "CancelExpiredOrder",
+		Interval: time.Minute,
+		Run:      w.Logic.ExpireOrders,
+	})
+}
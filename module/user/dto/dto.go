@@ -1,7 +1,31 @@
 package dto
 
 import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
 	"pcstakehometest/model"
+	"pcstakehometest/static"
 )
 
 type FindRequest model.Users
+
+type UpdateStoreRequest struct {
+	SellerID int
+	RoleID   enum.RoleType
+	Closed   bool
+}
+
+func (d *UpdateStoreRequest) Validate() error {
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeSeller {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
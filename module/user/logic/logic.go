@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"pcstakehometest/model"
 	"pcstakehometest/module/user/dto"
@@ -19,6 +20,7 @@ import (
 // UserLogic
 type IUserLogic interface {
 	Find(context.Context, *dto.FindRequest) (*model.Users, error)
+	UpdateStore(context.Context, *dto.UpdateStoreRequest, *gorm.DB) (*model.Users, error)
 }
 
 type UserLogic struct {
@@ -47,3 +49,31 @@ func (l *UserLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.
 	}
 	return product, nil
 }
+
+// UpdateStore open or close seller store
+func (l *UserLogic) UpdateStore(ctx context.Context, reqData *dto.UpdateStoreRequest, tx *gorm.DB) (*model.Users, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	user, err := l.Find(ctx, &dto.FindRequest{
+		ID: reqData.SellerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.StoreClosedAt = nil
+	if reqData.Closed {
+		now := time.Now()
+		user.StoreClosedAt = &now
+	}
+
+	if err := l.UserRepo.UpdateStore(ctx, user, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return user, nil
+}
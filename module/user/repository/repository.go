@@ -2,17 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"pcstakehometest/database/postgres"
 	"pcstakehometest/model"
 	"pcstakehometest/package/logger"
 
 	"go.uber.org/fx"
+	"gorm.io/gorm"
 )
 
 // UserRepository
 type IUserRepository interface {
 	Find(context.Context, *model.Users) (*model.Users, error)
+	UpdateStore(context.Context, *model.Users, *gorm.DB) error
 }
 
 type UserRepository struct {
@@ -40,3 +43,17 @@ func (l *UserRepository) Find(ctx context.Context, reqData *model.Users) (*model
 	}
 	return product, nil
 }
+
+// UpdateStore
+func (l *UserRepository) UpdateStore(ctx context.Context, reqData *model.Users, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Users{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"store_closed_at": reqData.StoreClosedAt,
+			"updated_at":      time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
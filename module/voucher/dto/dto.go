@@ -0,0 +1,186 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+)
+
+// Voucher Value percent for percentage type, amount in Currency for fixed type, SellerID only set by admin
+type Voucher struct {
+	Code          string
+	Name          string
+	SellerID      *int
+	Type          enum.VoucherType
+	Currency      string
+	Value         money.Amount
+	MinSpend      *money.Amount
+	MaxDiscount   *money.Amount
+	UsageLimit    *int
+	PerBuyerLimit *int
+	Active        *bool
+	StartAt       *time.Time
+	EndAt         *time.Time
+}
+
+func (d *Voucher) validate() error {
+	if strings.TrimSpace(d.Code) == "" {
+		return fmt.Errorf(static.EmptyValue, "Code")
+	}
+	if err := d.Type.IsValid(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(d.Currency) == "" {
+		return fmt.Errorf(static.EmptyValue, "Currency")
+	}
+	if !d.Value.IsPositive() {
+		return fmt.Errorf(static.MinValue, "Value", 0)
+	}
+	if d.Type == enum.VoucherTypePercentage && d.Value.Cmp(money.New(100)) > 0 {
+		return fmt.Errorf(static.InvalidRange, "Value", 0, 100)
+	}
+	if d.MinSpend != nil && d.MinSpend.IsNegative() {
+		return fmt.Errorf(static.NegativeValue, "MinSpend")
+	}
+	if d.MaxDiscount != nil && !d.MaxDiscount.IsPositive() {
+		return fmt.Errorf(static.MinValue, "MaxDiscount", 0)
+	}
+	if d.UsageLimit != nil && *d.UsageLimit <= 0 {
+		return fmt.Errorf(static.MinValue, "UsageLimit", 0)
+	}
+	if d.PerBuyerLimit != nil && *d.PerBuyerLimit <= 0 {
+		return fmt.Errorf(static.MinValue, "PerBuyerLimit", 0)
+	}
+	if d.StartAt != nil && d.EndAt != nil && !d.EndAt.After(*d.StartAt) {
+		return errors.New(static.VoucherPeriodInvalid)
+	}
+	return nil
+}
+
+// CreateRequest seller voucher always funded by the seller itself
+type CreateRequest struct {
+	Voucher
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *CreateRequest) Validate() error {
+	if err := d.Voucher.validate(); err != nil {
+		return err
+	}
+	return validateManager(d.UserID, d.RoleID)
+}
+
+type UpdateRequest struct {
+	ID int
+	Voucher
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *UpdateRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if err := d.Voucher.validate(); err != nil {
+		return err
+	}
+	return validateManager(d.UserID, d.RoleID)
+}
+
+type FindRequest struct {
+	ID     int
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *FindRequest) Validate() error {
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	return validateManager(d.UserID, d.RoleID)
+}
+
+type FindAllRequest struct {
+	UserID int
+	RoleID enum.RoleType
+}
+
+func (d *FindAllRequest) Validate() error {
+	return validateManager(d.UserID, d.RoleID)
+}
+
+// validateManager voucher managed by seller for its own store or by admin
+func validateManager(userID int, roleID enum.RoleType) error {
+	if userID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "UserID")
+	}
+	if err := roleID.IsValid(); err != nil {
+		return err
+	}
+	if roleID != enum.RoleTypeSeller && roleID != enum.RoleTypeAdmin {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+// QuoteRequest order of one seller, Subtotal item subtotal in Currency
+type QuoteRequest struct {
+	Code     string
+	BuyerID  int
+	SellerID int
+	Subtotal money.Amount
+	Currency string
+}
+
+func (d *QuoteRequest) Validate() error {
+	if strings.TrimSpace(d.Code) == "" {
+		return fmt.Errorf(static.EmptyValue, "Code")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.SellerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "SellerID")
+	}
+	if strings.TrimSpace(d.Currency) == "" {
+		return fmt.Errorf(static.EmptyValue, "Currency")
+	}
+	return nil
+}
+
+// QuoteResponse Discount in order currency
+type QuoteResponse struct {
+	Voucher  *model.Vouchers
+	Discount money.Amount
+}
+
+type RedeemRequest struct {
+	VoucherID     int
+	BuyerID       int
+	TransactionID int
+	Discount      money.Amount
+	Currency      string
+}
+
+func (d *RedeemRequest) Validate() error {
+	if d.VoucherID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "VoucherID")
+	}
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.TransactionID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "TransactionID")
+	}
+	if d.Discount.IsNegative() {
+		return fmt.Errorf(static.NegativeValue, "Discount")
+	}
+	return nil
+}
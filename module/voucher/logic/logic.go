@@ -0,0 +1,381 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/voucher/dto"
+	"pcstakehometest/module/voucher/repository"
+	"pcstakehometest/package/exchange"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// VoucherLogic
+type IVoucherLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Vouchers, error)
+	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Vouchers, error)
+	Find(context.Context, *dto.FindRequest) (*model.Vouchers, error)
+	Update(context.Context, *dto.UpdateRequest, *gorm.DB) (*model.Vouchers, error)
+	Delete(context.Context, *dto.FindRequest, *gorm.DB) error
+	Quote(context.Context, *dto.QuoteRequest, *gorm.DB) (*dto.QuoteResponse, error)
+	Redeem(context.Context, *dto.RedeemRequest, *gorm.DB) error
+	Release(context.Context, int, *gorm.DB) error
+}
+
+type VoucherLogic struct {
+	fx.In
+	Logger      *logger.LogRus
+	Exchange    exchange.Provider
+	VoucherRepo repository.IVoucherRepository
+}
+
+// NewLogic :
+func NewLogic(voucherLogic VoucherLogic) IVoucherLogic {
+	return &voucherLogic
+}
+
+// Create
+func (l *VoucherLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Vouchers, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	voucher := &model.Vouchers{
+		SellerID: reqData.SellerID,
+	}
+	if reqData.RoleID == enum.RoleTypeSeller {
+		voucher.SellerID = &reqData.UserID
+	}
+	fillVoucher(voucher, &reqData.Voucher)
+
+	if err := l.unique(ctx, voucher.Code, 0); err != nil {
+		return nil, err
+	}
+
+	if _, err := l.VoucherRepo.Create(ctx, voucher, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	voucher.TypeVoucher = voucher.Type.String()
+
+	return voucher, nil
+}
+
+// FindAll seller only see its own voucher
+func (l *VoucherLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest) ([]*model.Vouchers, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	whereData := &model.Vouchers{}
+	if reqData.RoleID == enum.RoleTypeSeller {
+		whereData.SellerID = &reqData.UserID
+	}
+
+	vouchers, err := l.VoucherRepo.FindAll(ctx, whereData)
+	if err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, voucher := range vouchers {
+		voucher.TypeVoucher = voucher.Type.String()
+	}
+
+	return vouchers, nil
+}
+
+// Find
+func (l *VoucherLogic) Find(ctx context.Context, reqData *dto.FindRequest) (*model.Vouchers, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	return l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+}
+
+// Update owner and usage of voucher kept, order already placed keep its discount
+func (l *VoucherLogic) Update(ctx context.Context, reqData *dto.UpdateRequest, tx *gorm.DB) (*model.Vouchers, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	voucher, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	fillVoucher(voucher, &reqData.Voucher)
+	if err := l.unique(ctx, voucher.Code, voucher.ID); err != nil {
+		return nil, err
+	}
+
+	if err := l.VoucherRepo.Update(ctx, voucher, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	voucher.TypeVoucher = voucher.Type.String()
+
+	return voucher, nil
+}
+
+// Delete
+func (l *VoucherLogic) Delete(ctx context.Context, reqData *dto.FindRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	voucher, err := l.findOwned(ctx, reqData.ID, reqData.UserID, reqData.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if err := l.VoucherRepo.Delete(ctx, voucher, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Quote discount of the voucher for one seller order, never more than its item subtotal
+func (l *VoucherLogic) Quote(ctx context.Context, reqData *dto.QuoteRequest, tx *gorm.DB) (*dto.QuoteResponse, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(reqData.Code))
+	voucher, err := l.VoucherRepo.Find(ctx, &model.Vouchers{
+		Code: code,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "voucher"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	voucher.TypeVoucher = voucher.Type.String()
+
+	if !applicable(voucher, reqData.SellerID, time.Now()) {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.VoucherNotApplicable, voucher.Code), http.StatusBadRequest)
+	}
+
+	if err := l.withinLimit(ctx, voucher, reqData.BuyerID, tx); err != nil {
+		return nil, err
+	}
+
+	// Bound and fixed value set in voucher currency, order settled in its own currency
+	currency := strings.ToUpper(reqData.Currency)
+	rate := money.Rate(0)
+	if voucher.Currency != currency {
+		exchangeRate, err := l.Exchange.Rate(ctx, voucher.Currency, currency)
+		if err != nil {
+			l.Logger.Error(err)
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.CurrencyNotSupported, currency), http.StatusBadRequest)
+		}
+		rate = exchangeRate.Value
+	}
+	convert := func(amount money.Amount) money.Amount {
+		if rate == 0 {
+			return amount
+		}
+		return amount.Convert(rate)
+	}
+
+	if voucher.MinSpend != nil {
+		minSpend := convert(*voucher.MinSpend)
+		if reqData.Subtotal.Cmp(minSpend) < 0 {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.VoucherMinSpend, voucher.Code, minSpend, currency), http.StatusBadRequest)
+		}
+	}
+
+	var discount money.Amount
+	switch voucher.Type {
+	case enum.VoucherTypePercentage:
+		discount = money.FromMinor(reqData.Subtotal.Minor() * voucher.Value.Minor() / (100 * money.Scale))
+	case enum.VoucherTypeFixed:
+		discount = convert(voucher.Value)
+	}
+	if voucher.MaxDiscount != nil {
+		if maxDiscount := convert(*voucher.MaxDiscount); discount.Cmp(maxDiscount) > 0 {
+			discount = maxDiscount
+		}
+	}
+	if discount.Cmp(reqData.Subtotal) > 0 {
+		discount = reqData.Subtotal
+	}
+
+	return &dto.QuoteResponse{
+		Voucher:  voucher,
+		Discount: discount,
+	}, nil
+}
+
+// Redeem count usage of the voucher by the transaction, caps checked again under lock
+func (l *VoucherLogic) Redeem(ctx context.Context, reqData *dto.RedeemRequest, tx *gorm.DB) error {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	voucher, err := l.VoucherRepo.FindForUpdate(ctx, reqData.VoucherID, tx)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "voucher"), http.StatusNotFound)
+		}
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.withinLimit(ctx, voucher, reqData.BuyerID, tx); err != nil {
+		return err
+	}
+
+	if err := l.VoucherRepo.UpdateUsed(ctx, voucher.ID, 1, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if _, err := l.VoucherRepo.CreateRedemption(ctx, &model.VoucherRedemptions{
+		VoucherID:     voucher.ID,
+		BuyerID:       reqData.BuyerID,
+		TransactionID: reqData.TransactionID,
+		Discount:      reqData.Discount,
+		Currency:      strings.ToUpper(reqData.Currency),
+	}, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Release usage of transaction that never went through so the voucher can be used again
+func (l *VoucherLogic) Release(ctx context.Context, transactionID int, tx *gorm.DB) error {
+	redemption, err := l.VoucherRepo.FindRedemption(ctx, transactionID, tx)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if _, err := l.VoucherRepo.FindForUpdate(ctx, redemption.VoucherID, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	releasedAt := time.Now()
+	redemption.ReleasedAt = &releasedAt
+	if err := l.VoucherRepo.ReleaseRedemption(ctx, redemption, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.VoucherRepo.UpdateUsed(ctx, redemption.VoucherID, -1, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// findOwned seller only reach its own voucher
+func (l *VoucherLogic) findOwned(ctx context.Context, id int, userID int, role enum.RoleType) (*model.Vouchers, error) {
+	whereData := &model.Vouchers{
+		ID: id,
+	}
+	if role == enum.RoleTypeSeller {
+		whereData.SellerID = &userID
+	}
+
+	voucher, err := l.VoucherRepo.Find(ctx, whereData)
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "voucher"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	voucher.TypeVoucher = voucher.Type.String()
+
+	return voucher, nil
+}
+
+// unique voucher code must be unique ignoring case
+func (l *VoucherLogic) unique(ctx context.Context, code string, id int) error {
+	existing, err := l.VoucherRepo.Find(ctx, &model.Vouchers{
+		Code: code,
+	})
+	if err == nil && existing.ID != id {
+		return utilities.ErrorRequest(fmt.Errorf(static.AlreadyExist, "Code"), http.StatusBadRequest)
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// withinLimit global cap counted on the voucher, buyer cap counted from usage not yet released
+func (l *VoucherLogic) withinLimit(ctx context.Context, voucher *model.Vouchers, buyerID int, tx *gorm.DB) error {
+	if voucher.UsageLimit != nil && voucher.Used >= *voucher.UsageLimit {
+		return utilities.ErrorRequest(fmt.Errorf(static.VoucherUsedUp, voucher.Code), http.StatusBadRequest)
+	}
+
+	if voucher.PerBuyerLimit != nil {
+		used, err := l.VoucherRepo.CountRedemption(ctx, voucher.ID, buyerID, tx)
+		if err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+		if used >= int64(*voucher.PerBuyerLimit) {
+			return utilities.ErrorRequest(fmt.Errorf(static.VoucherBuyerLimit, voucher.Code), http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// applicable active voucher inside its window, seller voucher only for order of that seller
+func applicable(voucher *model.Vouchers, sellerID int, at time.Time) bool {
+	if !voucher.Active {
+		return false
+	}
+	if voucher.StartAt != nil && at.Before(*voucher.StartAt) {
+		return false
+	}
+	if voucher.EndAt != nil && !at.Before(*voucher.EndAt) {
+		return false
+	}
+	return voucher.SellerID == nil || *voucher.SellerID == sellerID
+}
+
+func fillVoucher(voucher *model.Vouchers, data *dto.Voucher) {
+	voucher.Code = strings.ToUpper(strings.TrimSpace(data.Code))
+	voucher.Name = strings.TrimSpace(data.Name)
+	voucher.Type = data.Type
+	voucher.Currency = strings.ToUpper(strings.TrimSpace(data.Currency))
+	voucher.Value = data.Value
+	voucher.MinSpend = data.MinSpend
+	voucher.MaxDiscount = data.MaxDiscount
+	voucher.UsageLimit = data.UsageLimit
+	voucher.PerBuyerLimit = data.PerBuyerLimit
+	voucher.Active = data.Active == nil || *data.Active
+	voucher.StartAt = data.StartAt
+	voucher.EndAt = data.EndAt
+}
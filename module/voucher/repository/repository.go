@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VoucherRepository
+type IVoucherRepository interface {
+	Create(context.Context, *model.Vouchers, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.Vouchers) ([]*model.Vouchers, error)
+	Find(context.Context, *model.Vouchers) (*model.Vouchers, error)
+	FindForUpdate(context.Context, int, *gorm.DB) (*model.Vouchers, error)
+	Update(context.Context, *model.Vouchers, *gorm.DB) error
+	UpdateUsed(context.Context, int, int, *gorm.DB) error
+	Delete(context.Context, *model.Vouchers, *gorm.DB) error
+	CreateRedemption(context.Context, *model.VoucherRedemptions, *gorm.DB) (*int, error)
+	FindRedemption(context.Context, int, *gorm.DB) (*model.VoucherRedemptions, error)
+	CountRedemption(context.Context, int, int, *gorm.DB) (int64, error)
+	ReleaseRedemption(context.Context, *model.VoucherRedemptions, *gorm.DB) error
+}
+
+type VoucherRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(voucherRepository VoucherRepository) IVoucherRepository {
+	return &voucherRepository
+}
+
+// Create
+func (l *VoucherRepository) Create(ctx context.Context, reqData *model.Vouchers, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll voucher of the seller when SellerID set
+func (l *VoucherRepository) FindAll(ctx context.Context, reqData *model.Vouchers) ([]*model.Vouchers, error) {
+	vouchers := []*model.Vouchers{}
+
+	query := l.Database.Gorm.WithContext(ctx).Model(&model.Vouchers{})
+	if reqData.SellerID != nil {
+		query = query.Where("seller_id = ?", *reqData.SellerID)
+	}
+
+	if err := query.
+		Order("id desc").
+		Find(&vouchers).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// Find by ID or Code, limited to the seller when SellerID set
+func (l *VoucherRepository) Find(ctx context.Context, reqData *model.Vouchers) (*model.Vouchers, error) {
+	voucher := new(model.Vouchers)
+
+	query := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Vouchers{
+			ID:   reqData.ID,
+			Code: reqData.Code,
+		})
+	if reqData.SellerID != nil {
+		query = query.Where("seller_id = ?", *reqData.SellerID)
+	}
+
+	if err := query.First(&voucher).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return voucher, nil
+}
+
+// FindForUpdate lock voucher so usage caps checked and counted by one order at a time
+func (l *VoucherRepository) FindForUpdate(ctx context.Context, id int, tx *gorm.DB) (*model.Vouchers, error) {
+	voucher := new(model.Vouchers)
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&voucher).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return voucher, nil
+}
+
+// Update every field replaced except code owner and usage, empty bound stored as null
+func (l *VoucherRepository) Update(ctx context.Context, reqData *model.Vouchers, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Vouchers{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"code":            reqData.Code,
+			"name":            reqData.Name,
+			"type":            reqData.Type,
+			"currency":        reqData.Currency,
+			"value":           reqData.Value,
+			"min_spend":       reqData.MinSpend,
+			"max_discount":    reqData.MaxDiscount,
+			"usage_limit":     reqData.UsageLimit,
+			"per_buyer_limit": reqData.PerBuyerLimit,
+			"active":          reqData.Active,
+			"start_at":        reqData.StartAt,
+			"end_at":          reqData.EndAt,
+			"updated_at":      time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateUsed add delta into usage count
+func (l *VoucherRepository) UpdateUsed(ctx context.Context, id int, delta int, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Vouchers{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"used":       gorm.Expr("greatest(used + ?, 0)", delta),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Delete
+func (l *VoucherRepository) Delete(ctx context.Context, reqData *model.Vouchers, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Delete(&model.Vouchers{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateRedemption
+func (l *VoucherRepository) CreateRedemption(ctx context.Context, reqData *model.VoucherRedemptions, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindRedemption usage of the transaction not yet released
+func (l *VoucherRepository) FindRedemption(ctx context.Context, transactionID int, tx *gorm.DB) (*model.VoucherRedemptions, error) {
+	redemption := new(model.VoucherRedemptions)
+	if err := tx.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Where("released_at is null").
+		First(&redemption).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return redemption, nil
+}
+
+// CountRedemption usage of the voucher by the buyer not yet released
+func (l *VoucherRepository) CountRedemption(ctx context.Context, voucherID int, buyerID int, tx *gorm.DB) (int64, error) {
+	var count int64
+	if err := tx.WithContext(ctx).Model(&model.VoucherRedemptions{}).
+		Where("voucher_id = ?", voucherID).
+		Where("buyer_id = ?", buyerID).
+		Where("released_at is null").
+		Count(&count).Error; err != nil {
+		l.Logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// ReleaseRedemption
+func (l *VoucherRepository) ReleaseRedemption(ctx context.Context, reqData *model.VoucherRedemptions, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.VoucherRedemptions{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"released_at": reqData.ReleasedAt,
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/static"
+)
+
+type CreateRequest struct {
+	ProductID int
+	BuyerID   int
+	RoleID    enum.RoleType
+}
+
+func (d *CreateRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.ProductID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ProductID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type FindAllRequest struct {
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *FindAllRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
+
+type WishlistRequest struct {
+	ID      int
+	BuyerID int
+	RoleID  enum.RoleType
+}
+
+func (d *WishlistRequest) Validate() error {
+	if d.BuyerID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "BuyerID")
+	}
+	if d.ID <= 0 {
+		return fmt.Errorf(static.EmptyValue, "ID")
+	}
+	if err := d.RoleID.IsValid(); err != nil {
+		return err
+	}
+	if d.RoleID != enum.RoleTypeBuyer {
+		return errors.New(static.Authorization)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/model"
+	"pcstakehometest/module/wishlist/dto"
+	"pcstakehometest/module/wishlist/repository"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/notifier"
+	"pcstakehometest/static"
+	"pcstakehometest/utilities"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// WishlistLogic
+type IWishlistLogic interface {
+	Create(context.Context, *dto.CreateRequest, *gorm.DB) (*model.Wishlists, error)
+	FindAll(context.Context, *dto.FindAllRequest) ([]*model.Wishlists, error)
+	Update(context.Context, *dto.WishlistRequest, *gorm.DB) (*model.Wishlists, error)
+	Delete(context.Context, *dto.WishlistRequest, *gorm.DB) error
+	NotifyPriceDrop(context.Context, *model.Products, *gorm.DB) error
+}
+
+type WishlistLogic struct {
+	fx.In
+	Logger       *logger.LogRus
+	WishlistRepo repository.IWishlistRepository
+}
+
+// NewLogic :
+func NewLogic(wishlistLogic WishlistLogic) IWishlistLogic {
+	return &wishlistLogic
+}
+
+// Create save product with the price buyer pay now as snapshot
+func (l *WishlistLogic) Create(ctx context.Context, reqData *dto.CreateRequest, tx *gorm.DB) (*model.Wishlists, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	product, err := l.findProduct(ctx, reqData.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Seller != nil && product.Seller.StoreClosedAt != nil {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.StoreClosed, product.Seller.Username), http.StatusBadRequest)
+	}
+
+	if _, err := l.WishlistRepo.Find(ctx, &model.Wishlists{
+		BuyerID:   reqData.BuyerID,
+		ProductID: product.ID,
+	}); err == nil {
+		return nil, utilities.ErrorRequest(fmt.Errorf(static.AlreadyExist, "wishlist"), http.StatusBadRequest)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	wishlist := &model.Wishlists{
+		BuyerID:       reqData.BuyerID,
+		ProductID:     product.ID,
+		PriceSnapshot: product.EffectivePrice(),
+		Currency:      product.Currency,
+	}
+	if _, err := l.WishlistRepo.Create(ctx, wishlist, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	wishlist.Product = product
+	flag(wishlist)
+
+	return wishlist, nil
+}
+
+// FindAll
+func (l *WishlistLogic) FindAll(ctx context.Context, reqData *dto.FindAllRequest) ([]*model.Wishlists, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	wishlists, err := l.WishlistRepo.FindAll(ctx, &model.Wishlists{
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	products := []*model.Products{}
+	for _, wishlist := range wishlists {
+		if wishlist.Product != nil {
+			products = append(products, wishlist.Product)
+		}
+	}
+	if err := l.applySale(ctx, products...); err != nil {
+		return nil, err
+	}
+
+	for _, wishlist := range wishlists {
+		flag(wishlist)
+	}
+
+	return wishlists, nil
+}
+
+// Update refresh price snapshot to the price buyer pay now
+func (l *WishlistLogic) Update(ctx context.Context, reqData *dto.WishlistRequest, tx *gorm.DB) (*model.Wishlists, error) {
+	wishlist, err := l.find(ctx, reqData)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := l.findProduct(ctx, wishlist.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist.PriceSnapshot = product.EffectivePrice()
+	wishlist.Currency = product.Currency
+	if err := l.WishlistRepo.UpdateSnapshot(ctx, wishlist, tx); err != nil {
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	wishlist.Product = product
+	flag(wishlist)
+
+	return wishlist, nil
+}
+
+// Delete
+func (l *WishlistLogic) Delete(ctx context.Context, reqData *dto.WishlistRequest, tx *gorm.DB) error {
+	wishlist, err := l.find(ctx, reqData)
+	if err != nil {
+		return err
+	}
+
+	if err := l.WishlistRepo.Delete(ctx, wishlist, tx); err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// NotifyPriceDrop notify buyer whose wishlist snapshot is above product price, price is what buyer pay now with sale included
+func (l *WishlistLogic) NotifyPriceDrop(ctx context.Context, product *model.Products, tx *gorm.DB) error {
+	wishlists, err := l.WishlistRepo.FindPriceDrop(ctx, product, tx)
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	for _, wishlist := range wishlists {
+		now := time.Now()
+		price := product.Price
+		wishlist.NotifiedPrice = &price
+		wishlist.NotifiedAt = &now
+		if err := l.WishlistRepo.UpdateNotified(ctx, wishlist, tx); err != nil {
+			return utilities.ErrorRequest(err, http.StatusInternalServerError)
+		}
+
+		// Sent by whoever commit the transaction, failed delivery should not block seller from updating price
+		if err := notifier.Defer(ctx, &notifier.Message{
+			UserID: wishlist.BuyerID,
+			Event:  notifier.EventPriceDrop,
+			Title:  static.PriceDropTitle,
+			Body:   fmt.Sprintf(static.PriceDropBody, product.Name, wishlist.PriceSnapshot, product.Price, product.Currency),
+			Data: map[string]interface{}{
+				"WishlistID":    wishlist.ID,
+				"ProductID":     product.ID,
+				"PriceSnapshot": wishlist.PriceSnapshot,
+				"Price":         product.Price,
+				"Currency":      product.Currency,
+			},
+		}); err != nil {
+			l.Logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+func (l *WishlistLogic) find(ctx context.Context, reqData *dto.WishlistRequest) (*model.Wishlists, error) {
+	// Validate request data
+	if err := reqData.Validate(); err != nil {
+		l.Logger.Error(err)
+		return nil, utilities.ErrorRequest(err, http.StatusBadRequest)
+	}
+
+	wishlist, err := l.WishlistRepo.Find(ctx, &model.Wishlists{
+		ID:      reqData.ID,
+		BuyerID: reqData.BuyerID,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "wishlist"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	return wishlist, nil
+}
+
+func (l *WishlistLogic) findProduct(ctx context.Context, productID int) (*model.Products, error) {
+	product, err := l.WishlistRepo.FindProduct(ctx, &model.Products{
+		ID:     productID,
+		Status: enum.ProductStatusTypePublished,
+	})
+	if err != nil {
+		l.Logger.Error(err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utilities.ErrorRequest(fmt.Errorf(static.DataNotFound, "product"), http.StatusNotFound)
+		}
+		return nil, utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	if err := l.applySale(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// applySale set sale price of product with a running sale
+func (l *WishlistLogic) applySale(ctx context.Context, products ...*model.Products) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	sales, err := l.WishlistRepo.FindActiveSales(ctx, productIDs, time.Now())
+	if err != nil {
+		return utilities.ErrorRequest(err, http.StatusInternalServerError)
+	}
+
+	running := map[int]*model.ProductSales{}
+	for _, sale := range sales {
+		running[sale.ProductID] = sale
+	}
+
+	for _, product := range products {
+		if sale, ok := running[product.ID]; ok {
+			product.SalePrice = &sale.SalePrice
+			product.SaleEndAt = &sale.EndAt
+		}
+	}
+
+	return nil
+}
+
+// flag mark wishlist availability from its product and store state
+func flag(wishlist *model.Wishlists) {
+	product := wishlist.Product
+	if product == nil {
+		wishlist.ProductDeleted = true
+		return
+	}
+
+	wishlist.ProductDeleted = product.DeletedAt.Valid
+	wishlist.StoreClosed = product.Seller != nil && product.Seller.StoreClosedAt != nil
+	wishlist.PriceDropped = !wishlist.ProductDeleted &&
+		product.Currency == wishlist.Currency &&
+		product.EffectivePrice().Cmp(wishlist.PriceSnapshot) < 0
+	wishlist.Available = !wishlist.ProductDeleted && !wishlist.StoreClosed &&
+		product.Status == enum.ProductStatusTypePublished && product.Stock > 0
+}
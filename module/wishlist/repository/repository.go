@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pcstakehometest/database/postgres"
+	"pcstakehometest/model"
+	"pcstakehometest/package/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// WishlistRepository
+type IWishlistRepository interface {
+	Create(context.Context, *model.Wishlists, *gorm.DB) (*int, error)
+	FindAll(context.Context, *model.Wishlists) ([]*model.Wishlists, error)
+	Find(context.Context, *model.Wishlists) (*model.Wishlists, error)
+	FindProduct(context.Context, *model.Products) (*model.Products, error)
+	FindActiveSales(context.Context, []int, time.Time) ([]*model.ProductSales, error)
+	FindPriceDrop(context.Context, *model.Products, *gorm.DB) ([]*model.Wishlists, error)
+	UpdateSnapshot(context.Context, *model.Wishlists, *gorm.DB) error
+	UpdateNotified(context.Context, *model.Wishlists, *gorm.DB) error
+	Delete(context.Context, *model.Wishlists, *gorm.DB) error
+}
+
+type WishlistRepository struct {
+	fx.In
+	Logger   *logger.LogRus
+	Database *postgres.DB
+}
+
+// NewRepository :
+func NewRepository(wishlistRepository WishlistRepository) IWishlistRepository {
+	return &wishlistRepository
+}
+
+// Create
+func (l *WishlistRepository) Create(ctx context.Context, reqData *model.Wishlists, tx *gorm.DB) (*int, error) {
+	if err := tx.WithContext(ctx).Create(&reqData).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return &reqData.ID, nil
+}
+
+// FindAll include deleted product so buyer can see it is no longer available
+func (l *WishlistRepository) FindAll(ctx context.Context, reqData *model.Wishlists) ([]*model.Wishlists, error) {
+	wishlists := []*model.Wishlists{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.Wishlists{}).
+		Preload("Product", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Product.Seller").
+		Where(&model.Wishlists{
+			BuyerID: reqData.BuyerID,
+		}).
+		Order("id desc").
+		Find(&wishlists).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+// Find
+func (l *WishlistRepository) Find(ctx context.Context, reqData *model.Wishlists) (*model.Wishlists, error) {
+	wishlist := new(model.Wishlists)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Where(&model.Wishlists{
+			ID:        reqData.ID,
+			BuyerID:   reqData.BuyerID,
+			ProductID: reqData.ProductID,
+		}).First(&wishlist).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+// FindProduct
+func (l *WishlistRepository) FindProduct(ctx context.Context, reqData *model.Products) (*model.Products, error) {
+	product := new(model.Products)
+	if err := l.Database.Gorm.WithContext(ctx).
+		Preload("Seller").
+		Where(&model.Products{
+			ID:     reqData.ID,
+			Status: reqData.Status,
+		}).First(&product).Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+	return product, nil
+}
+
+// FindActiveSales find sale running at the given time
+func (l *WishlistRepository) FindActiveSales(ctx context.Context, productIDs []int, now time.Time) ([]*model.ProductSales, error) {
+	sales := []*model.ProductSales{}
+
+	if err := l.Database.Gorm.WithContext(ctx).Model(&model.ProductSales{}).
+		Where("product_id in ?", productIDs).
+		Where("start_at <= ?", now).
+		Where("end_at > ?", now).
+		Find(&sales).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// FindPriceDrop find wishlist whose snapshot is above the new price and not yet notified for it
+func (l *WishlistRepository) FindPriceDrop(ctx context.Context, reqData *model.Products, tx *gorm.DB) ([]*model.Wishlists, error) {
+	wishlists := []*model.Wishlists{}
+
+	if err := tx.WithContext(ctx).Model(&model.Wishlists{}).
+		Where("product_id = ?", reqData.ID).
+		Where("currency = ?", reqData.Currency).
+		Where("price_snapshot > ?", reqData.Price).
+		Where("notified_price is null or notified_price > ?", reqData.Price).
+		Order("id asc").
+		Find(&wishlists).
+		Error; err != nil {
+		l.Logger.Error(err)
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+// UpdateSnapshot
+func (l *WishlistRepository) UpdateSnapshot(ctx context.Context, reqData *model.Wishlists, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Wishlists{}).
+		Where("id = ?", reqData.ID).
+		Where("buyer_id = ?", reqData.BuyerID).
+		Updates(map[string]interface{}{
+			"price_snapshot": reqData.PriceSnapshot,
+			"currency":       reqData.Currency,
+			"notified_price": nil,
+			"notified_at":    nil,
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateNotified
+func (l *WishlistRepository) UpdateNotified(ctx context.Context, reqData *model.Wishlists, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).Model(&model.Wishlists{}).
+		Where("id = ?", reqData.ID).
+		Updates(map[string]interface{}{
+			"notified_price": reqData.NotifiedPrice,
+			"notified_at":    reqData.NotifiedAt,
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Delete
+func (l *WishlistRepository) Delete(ctx context.Context, reqData *model.Wishlists, tx *gorm.DB) error {
+	if err := tx.WithContext(ctx).
+		Where("id = ?", reqData.ID).
+		Where("buyer_id = ?", reqData.BuyerID).
+		Delete(&model.Wishlists{}).Error; err != nil {
+		l.Logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package attribute
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Type of value an attribute accept
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeEnum    = "enum"
+)
+
+var (
+	ErrInvalidSchema = errors.New("invalid attribute schema")
+	ErrRequired      = errors.New("attribute is required")
+	ErrUnknown       = errors.New("attribute is not defined")
+	ErrInvalidValue  = errors.New("invalid attribute value")
+)
+
+// Error attribute error along with the attribute key
+type Error struct {
+	Key string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Definition describe one attribute of a category
+type Definition struct {
+	Key      string
+	Label    string
+	Type     string
+	Required bool
+	Options  []string `json:",omitempty"`
+	Unit     string   `json:",omitempty"`
+	Min      *float64 `json:",omitempty"`
+	Max      *float64 `json:",omitempty"`
+}
+
+// Schema list of attribute definition of a category
+type Schema []Definition
+
+// Values attribute value of a product keyed by definition key
+type Values map[string]interface{}
+
+// Item one attribute value along with its label and unit
+type Item struct {
+	Key   string
+	Label string
+	Value interface{}
+	Unit  string `json:",omitempty"`
+}
+
+// Check make sure schema itself well formed
+func (s Schema) Check() error {
+	seen := map[string]bool{}
+	for _, definition := range s {
+		if !validKey(definition.Key) {
+			return &Error{Key: definition.Key, Err: ErrInvalidSchema}
+		}
+		if seen[definition.Key] {
+			return &Error{Key: definition.Key, Err: ErrInvalidSchema}
+		}
+		seen[definition.Key] = true
+
+		switch definition.Type {
+		case TypeString, TypeBoolean:
+		case TypeNumber:
+			if definition.Min != nil && definition.Max != nil && *definition.Min > *definition.Max {
+				return &Error{Key: definition.Key, Err: ErrInvalidSchema}
+			}
+		case TypeEnum:
+			if len(definition.Options) == 0 {
+				return &Error{Key: definition.Key, Err: ErrInvalidSchema}
+			}
+		default:
+			return &Error{Key: definition.Key, Err: ErrInvalidSchema}
+		}
+	}
+	return nil
+}
+
+// Find definition by key
+func (s Schema) Find(key string) (*Definition, bool) {
+	for i := range s {
+		if s[i].Key == key {
+			return &s[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate check values against schema and return normalized values
+func (s Schema) Validate(values Values) (Values, error) {
+	for key := range values {
+		if _, ok := s.Find(key); !ok {
+			return nil, &Error{Key: key, Err: ErrUnknown}
+		}
+	}
+
+	result := Values{}
+	for _, definition := range s {
+		value, ok := values[definition.Key]
+		if !ok || value == nil || value == "" {
+			if definition.Required {
+				return nil, &Error{Key: definition.Key, Err: ErrRequired}
+			}
+			continue
+		}
+
+		normalized, err := definition.normalize(value)
+		if err != nil {
+			return nil, &Error{Key: definition.Key, Err: err}
+		}
+		result[definition.Key] = normalized
+	}
+
+	return result, nil
+}
+
+// Items list values in schema order along with their label and unit
+func (s Schema) Items(values Values) []Item {
+	items := []Item{}
+	for _, definition := range s {
+		value, ok := values[definition.Key]
+		if !ok {
+			continue
+		}
+		items = append(items, Item{
+			Key:   definition.Key,
+			Label: definition.Label,
+			Value: value,
+			Unit:  definition.Unit,
+		})
+	}
+	return items
+}
+
+func (d *Definition) normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case TypeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidValue
+		}
+		return strings.TrimSpace(text), nil
+	case TypeNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, ErrInvalidValue
+		}
+		if (d.Min != nil && number < *d.Min) || (d.Max != nil && number > *d.Max) {
+			return nil, ErrInvalidValue
+		}
+		return number, nil
+	case TypeBoolean:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, ErrInvalidValue
+		}
+		return boolean, nil
+	case TypeEnum:
+		text, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidValue
+		}
+		for _, option := range d.Options {
+			if strings.EqualFold(option, text) {
+				return option, nil
+			}
+		}
+		return nil, ErrInvalidValue
+	}
+	return nil, ErrInvalidSchema
+}
+
+// validKey key also used as listing filter name, keep it to lower case letter, digit and underscore
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Schema) Value() (driver.Value, error) {
+	if s == nil {
+		s = Schema{}
+	}
+	return json.Marshal(s)
+}
+
+func (s *Schema) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return ErrInvalidSchema
+	}
+
+	result := Schema{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+
+	*s = result
+
+	return nil
+}
+
+func (j Values) Value() (driver.Value, error) {
+	if j == nil {
+		j = Values{}
+	}
+	return json.Marshal(j)
+}
+
+func (j *Values) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return ErrInvalidValue
+	}
+
+	result := Values{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
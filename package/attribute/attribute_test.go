@@ -0,0 +1,98 @@
+package attribute
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func schema() Schema {
+	min := 0.0
+	return Schema{
+		{Key: "weight", Label: "Berat", Type: TypeNumber, Required: true, Unit: "gram", Min: &min},
+		{Key: "color", Label: "Warna", Type: TypeEnum, Options: []string{"red", "blue"}},
+		{Key: "material", Label: "Bahan", Type: TypeString},
+		{Key: "fragile", Label: "Mudah pecah", Type: TypeBoolean},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, schema().Check())
+
+	for name, invalid := range map[string]Schema{
+		"EmptyKey":      {{Key: "", Type: TypeString}},
+		"UpperKey":      {{Key: "Color", Type: TypeString}},
+		"DuplicateKey":  {{Key: "color", Type: TypeString}, {Key: "color", Type: TypeString}},
+		"UnknownType":   {{Key: "color", Type: "date"}},
+		"EnumNoOptions": {{Key: "color", Type: TypeEnum}},
+	} {
+		assert.ErrorIs(t, invalid.Check(), ErrInvalidSchema, name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("SuccessNormalize", func(t *testing.T) {
+		values := Values{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"weight":250,"color":"RED","material":" kayu ","fragile":true}`), &values))
+
+		result, err := schema().Validate(values)
+		if assert.NoError(t, err) {
+			assert.Equal(t, Values{"weight": 250.0, "color": "red", "material": "kayu", "fragile": true}, result)
+		}
+	})
+
+	t.Run("SuccessOptionalOmitted", func(t *testing.T) {
+		result, err := schema().Validate(Values{"weight": 1.5})
+		if assert.NoError(t, err) {
+			assert.Equal(t, Values{"weight": 1.5}, result)
+		}
+	})
+
+	t.Run("FailedRequired", func(t *testing.T) {
+		_, err := schema().Validate(Values{"color": "red"})
+		assert.ErrorIs(t, err, ErrRequired)
+
+		var attrErr *Error
+		if assert.ErrorAs(t, err, &attrErr) {
+			assert.Equal(t, "weight", attrErr.Key)
+		}
+	})
+
+	t.Run("FailedUnknown", func(t *testing.T) {
+		_, err := schema().Validate(Values{"weight": 1.0, "size": "XL"})
+		assert.ErrorIs(t, err, ErrUnknown)
+	})
+
+	t.Run("FailedInvalidValue", func(t *testing.T) {
+		for _, values := range []Values{
+			{"weight": "berat"},
+			{"weight": -1.0},
+			{"weight": 1.0, "color": "green"},
+			{"weight": 1.0, "fragile": "yes"},
+			{"weight": 1.0, "material": 10.0},
+		} {
+			_, err := schema().Validate(values)
+			assert.ErrorIs(t, err, ErrInvalidValue, values)
+		}
+	})
+}
+
+func TestItems(t *testing.T) {
+	items := schema().Items(Values{"color": "red", "weight": 250.0})
+	assert.Equal(t, []Item{
+		{Key: "weight", Label: "Berat", Value: 250.0, Unit: "gram"},
+		{Key: "color", Label: "Warna", Value: "red"},
+	}, items)
+}
+
+func TestScan(t *testing.T) {
+	values := Values{}
+	assert.NoError(t, values.Scan([]byte(`{"color":"red"}`)))
+	assert.Equal(t, Values{"color": "red"}, values)
+
+	value, err := Values(nil).Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte(`{}`), value)
+	}
+}
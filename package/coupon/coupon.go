@@ -0,0 +1,192 @@
+package coupon
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+)
+
+// Rule award coupon for matching order
+//
+// Price band award Coupons for every unit priced from MinAmount up to but excluding MaxAmount,
+// total threshold award Coupons for every Step of item subtotal once subtotal reach MinAmount,
+// multiplier scale coupon of matching seller order or category item, the highest matching multiplier win
+type Rule struct {
+	ID         int
+	Name       string
+	Type       enum.CouponRuleType
+	Currency   string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Step       *money.Amount
+	Coupons    int
+	Multiplier float64
+	SellerID   *int
+	CategoryID *int
+	StartAt    *time.Time
+	EndAt      *time.Time
+}
+
+// Order one seller order, price in order currency
+type Order struct {
+	SellerID int
+	Currency string
+	Items    []Item
+	At       time.Time
+}
+
+type Item struct {
+	ProductID  int
+	CategoryID *int
+	Price      money.Amount
+	Quantity   int
+}
+
+// Fired rule matching the order along with coupon it contributed
+type Fired struct {
+	RuleID     int
+	Name       string
+	Type       string
+	Coupons    int
+	Multiplier float64 `json:",omitempty"`
+}
+
+type Result struct {
+	Coupons int
+	Fired   []*Fired
+}
+
+// Active rule valid at the given time, start inclusive and end exclusive
+func (r *Rule) Active(at time.Time) bool {
+	if r.StartAt != nil && at.Before(*r.StartAt) {
+		return false
+	}
+	if r.EndAt != nil && !at.Before(*r.EndAt) {
+		return false
+	}
+	return true
+}
+
+// Evaluate coupon earned by the order
+func Evaluate(rules []*Rule, order *Order) *Result {
+	result := &Result{}
+	fired := map[int]*Fired{}
+	fire := func(rule *Rule, coupons int, multiplier float64) {
+		entry, ok := fired[rule.ID]
+		if !ok {
+			entry = &Fired{
+				RuleID:     rule.ID,
+				Name:       rule.Name,
+				Type:       rule.Type.String(),
+				Multiplier: multiplier,
+			}
+			fired[rule.ID] = entry
+			result.Fired = append(result.Fired, entry)
+		}
+		entry.Coupons += coupons
+	}
+
+	active := []*Rule{}
+	for _, rule := range rules {
+		if rule.Active(order.At) {
+			active = append(active, rule)
+		}
+	}
+
+	// Price band per unit, scaled by the best category multiplier of the item
+	var (
+		base     int
+		subtotal money.Amount
+	)
+	for _, item := range order.Items {
+		subtotal = subtotal.Add(item.Price.Mul(int64(item.Quantity)))
+
+		itemCoupons := 0
+		for _, rule := range active {
+			if rule.Type != enum.CouponRuleTypeItemPriceBand || !sameCurrency(rule, order) || !inBand(rule, item.Price) {
+				continue
+			}
+			coupons := rule.Coupons * item.Quantity
+			itemCoupons += coupons
+			fire(rule, coupons, 0)
+		}
+
+		var best *Rule
+		for _, rule := range active {
+			if rule.Type != enum.CouponRuleTypeCategoryMultiplier || rule.CategoryID == nil || item.CategoryID == nil || *rule.CategoryID != *item.CategoryID {
+				continue
+			}
+			if best == nil || rule.Multiplier > best.Multiplier {
+				best = rule
+			}
+		}
+		if best != nil && itemCoupons > 0 {
+			scaled := scale(itemCoupons, best.Multiplier)
+			fire(best, scaled-itemCoupons, best.Multiplier)
+			itemCoupons = scaled
+		}
+
+		base += itemCoupons
+	}
+
+	// Threshold on item subtotal, shipping fee not counted
+	for _, rule := range active {
+		if rule.Type != enum.CouponRuleTypeTotalThreshold || !sameCurrency(rule, order) || rule.Step == nil || !rule.Step.IsPositive() {
+			continue
+		}
+		if rule.MinAmount != nil && subtotal.Cmp(*rule.MinAmount) < 0 {
+			continue
+		}
+		coupons := int(subtotal.Minor()/rule.Step.Minor()) * rule.Coupons
+		if coupons == 0 {
+			continue
+		}
+		base += coupons
+		fire(rule, coupons, 0)
+	}
+
+	// Seller multiplier apply to the whole order
+	var best *Rule
+	for _, rule := range active {
+		if rule.Type != enum.CouponRuleTypeSellerMultiplier || rule.SellerID == nil || *rule.SellerID != order.SellerID {
+			continue
+		}
+		if best == nil || rule.Multiplier > best.Multiplier {
+			best = rule
+		}
+	}
+	if best != nil && base > 0 {
+		scaled := scale(base, best.Multiplier)
+		fire(best, scaled-base, best.Multiplier)
+		base = scaled
+	}
+
+	result.Coupons = base
+	if result.Fired == nil {
+		result.Fired = []*Fired{}
+	}
+
+	return result
+}
+
+func sameCurrency(rule *Rule, order *Order) bool {
+	return strings.EqualFold(rule.Currency, order.Currency)
+}
+
+func inBand(rule *Rule, price money.Amount) bool {
+	if rule.MinAmount != nil && price.Cmp(*rule.MinAmount) < 0 {
+		return false
+	}
+	if rule.MaxAmount != nil && price.Cmp(*rule.MaxAmount) >= 0 {
+		return false
+	}
+	return true
+}
+
+// scale round down so multiplier never award partial coupon
+func scale(coupons int, multiplier float64) int {
+	return int(math.Floor(float64(coupons)*multiplier + 1e-9))
+}
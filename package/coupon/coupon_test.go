@@ -0,0 +1,104 @@
+package coupon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pcstakehometest/enum"
+	"pcstakehometest/package/money"
+)
+
+func amount(units int64) *money.Amount {
+	value := money.New(units)
+	return &value
+}
+
+func id(value int) *int {
+	return &value
+}
+
+func defaultRules() []*Rule {
+	return []*Rule{
+		{ID: 1, Name: "Band", Type: enum.CouponRuleTypeItemPriceBand, Currency: "IDR", MinAmount: amount(50000), MaxAmount: amount(100000), Coupons: 1},
+		{ID: 2, Name: "Threshold", Type: enum.CouponRuleTypeTotalThreshold, Currency: "IDR", Step: amount(100000), Coupons: 1},
+	}
+}
+
+func TestEvaluateDefault(t *testing.T) {
+	now := time.Now()
+
+	t.Run("BandBoundInclusiveMinExclusiveMax", func(t *testing.T) {
+		result := Evaluate(defaultRules(), &Order{Currency: "IDR", At: now, Items: []Item{
+			{ProductID: 1, Price: money.New(50000), Quantity: 2},
+			{ProductID: 2, Price: money.New(100000), Quantity: 1},
+			{ProductID: 3, Price: money.New(49999), Quantity: 1},
+		}})
+		// 2 from band, 249999 subtotal give 2 from threshold
+		assert.Equal(t, 4, result.Coupons)
+		if assert.Len(t, result.Fired, 2) {
+			assert.Equal(t, 2, result.Fired[0].Coupons)
+			assert.Equal(t, 2, result.Fired[1].Coupons)
+		}
+	})
+
+	t.Run("NoRuleFired", func(t *testing.T) {
+		result := Evaluate(defaultRules(), &Order{Currency: "IDR", At: now, Items: []Item{
+			{ProductID: 1, Price: money.New(1000), Quantity: 1},
+		}})
+		assert.Equal(t, 0, result.Coupons)
+		assert.Empty(t, result.Fired)
+	})
+
+	t.Run("OtherCurrencyIgnored", func(t *testing.T) {
+		result := Evaluate(defaultRules(), &Order{Currency: "USD", At: now, Items: []Item{
+			{ProductID: 1, Price: money.New(60000), Quantity: 3},
+		}})
+		assert.Equal(t, 0, result.Coupons)
+	})
+}
+
+func TestEvaluateMultiplier(t *testing.T) {
+	now := time.Now()
+	rules := append(defaultRules(),
+		&Rule{ID: 3, Name: "Category", Type: enum.CouponRuleTypeCategoryMultiplier, CategoryID: id(7), Multiplier: 2},
+		&Rule{ID: 4, Name: "Category Low", Type: enum.CouponRuleTypeCategoryMultiplier, CategoryID: id(7), Multiplier: 1.5},
+		&Rule{ID: 5, Name: "Seller", Type: enum.CouponRuleTypeSellerMultiplier, SellerID: id(9), Multiplier: 1.5},
+	)
+
+	result := Evaluate(rules, &Order{SellerID: 9, Currency: "IDR", At: now, Items: []Item{
+		{ProductID: 1, CategoryID: id(7), Price: money.New(60000), Quantity: 1},
+		{ProductID: 2, Price: money.New(70000), Quantity: 1},
+	}})
+
+	// Band 2, category double the first item into 3, threshold 1, seller 4 * 1.5 = 6
+	assert.Equal(t, 6, result.Coupons)
+	fired := map[int]int{}
+	for _, entry := range result.Fired {
+		fired[entry.RuleID] = entry.Coupons
+	}
+	assert.Equal(t, map[int]int{1: 2, 2: 1, 3: 1, 5: 2}, fired)
+}
+
+func TestEvaluateValidity(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	start := now.Add(time.Hour)
+	end := now
+
+	rules := []*Rule{
+		{ID: 1, Type: enum.CouponRuleTypeTotalThreshold, Currency: "IDR", Step: amount(100), Coupons: 1, StartAt: &start},
+		{ID: 2, Type: enum.CouponRuleTypeTotalThreshold, Currency: "IDR", Step: amount(100), Coupons: 1, EndAt: &end},
+		{ID: 3, Type: enum.CouponRuleTypeTotalThreshold, Currency: "IDR", Step: amount(100), Coupons: 1, MinAmount: amount(1000)},
+	}
+
+	result := Evaluate(rules, &Order{Currency: "IDR", At: now, Items: []Item{
+		{ProductID: 1, Price: money.New(500), Quantity: 1},
+	}})
+	assert.Equal(t, 0, result.Coupons)
+
+	result = Evaluate(rules, &Order{Currency: "IDR", At: start, Items: []Item{
+		{ProductID: 1, Price: money.New(500), Quantity: 2},
+	}})
+	// Rule 1 started, rule 2 ended, rule 3 minimum reached
+	assert.Equal(t, 20, result.Coupons)
+}
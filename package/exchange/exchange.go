@@ -0,0 +1,40 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+)
+
+const ProviderFile = "file"
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+type Rate struct {
+	From  string
+	To    string
+	Value money.Rate
+	AsOf  time.Time
+}
+
+// Provider give exchange rate to convert amount from one currency to another
+type Provider interface {
+	Rate(ctx context.Context, from, to string) (*Rate, error)
+}
+
+// NewProvider create rate provider based on configuration
+func NewProvider(log *logger.LogRus) Provider {
+	cfg := config.Get().Currency
+
+	switch cfg.Provider {
+	case ProviderFile, "":
+		return NewFileProvider(cfg.RatesFile)
+	}
+
+	log.Fatalf("NewProvider: exchange rate provider %s not supported", cfg.Provider)
+	return nil
+}
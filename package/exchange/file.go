@@ -0,0 +1,106 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"pcstakehometest/package/money"
+)
+
+// ratesFile rates of one base currency unit in every other currency
+type ratesFile struct {
+	Base  string
+	AsOf  time.Time
+	Rates map[string]money.Rate
+}
+
+// FileProvider read rates from json file for offline use, file reloaded when modified
+type FileProvider struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	rates   *ratesFile
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Rate calculate cross rate through base currency
+func (p *FileProvider) Rate(ctx context.Context, from, to string) (*Rate, error) {
+	rates, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+
+	value := money.OneRate
+	if from != to {
+		value = money.RateFromRat(new(big.Rat).Quo(toRate.Rat(), fromRate.Rat()))
+	}
+
+	return &Rate{
+		From:  from,
+		To:    to,
+		Value: value,
+		AsOf:  rates.AsOf,
+	}, nil
+}
+
+func (r *ratesFile) rate(currency string) (money.Rate, bool) {
+	if currency == r.Base {
+		return money.OneRate, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate.IsPositive()
+}
+
+func (p *FileProvider) load() (*ratesFile, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.rates != nil && info.ModTime().Equal(p.modTime) {
+		return p.rates, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := new(ratesFile)
+	if err := json.Unmarshal(data, rates); err != nil {
+		return nil, err
+	}
+	rates.Base = strings.ToUpper(rates.Base)
+
+	normalized := make(map[string]money.Rate, len(rates.Rates))
+	for currency, rate := range rates.Rates {
+		normalized[strings.ToUpper(currency)] = rate
+	}
+	rates.Rates = normalized
+
+	p.rates = rates
+	p.modTime = info.ModTime()
+
+	return rates, nil
+}
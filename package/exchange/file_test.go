@@ -0,0 +1,56 @@
+package exchange
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pcstakehometest/package/money"
+)
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"Base":"IDR","AsOf":"2026-10-01T00:00:00Z","Rates":{"USD":"0.000064","sgd":0.000086}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileProvider(path)
+	ctx := context.Background()
+
+	t.Run("SuccessBaseToCurrency", func(t *testing.T) {
+		rate, err := provider.Rate(ctx, "IDR", "USD")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "0.0000640000", rate.Value.String())
+			assert.Equal(t, 2026, rate.AsOf.Year())
+		}
+	})
+
+	t.Run("SuccessCurrencyToBase", func(t *testing.T) {
+		rate, err := provider.Rate(ctx, "usd", "idr")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "15625.0000000000", rate.Value.String())
+			assert.Equal(t, money.New(15625), money.New(1).Convert(rate.Value))
+		}
+	})
+
+	t.Run("SuccessCrossRate", func(t *testing.T) {
+		rate, err := provider.Rate(ctx, "USD", "SGD")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1.3437500000", rate.Value.String())
+		}
+	})
+
+	t.Run("SuccessSameCurrency", func(t *testing.T) {
+		rate, err := provider.Rate(ctx, "SGD", "SGD")
+		if assert.NoError(t, err) {
+			assert.Equal(t, money.OneRate, rate.Value)
+		}
+	})
+
+	t.Run("FailedUnknownCurrency", func(t *testing.T) {
+		_, err := provider.Rate(ctx, "IDR", "JPY")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+}
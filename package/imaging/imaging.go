@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedType = errors.New("image type not supported")
+	ErrTooManyPixels   = errors.New("image resolution too large")
+)
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Sniff detect content type from file content instead of trusting client header
+func Sniff(data []byte) (contentType, extension string, err error) {
+	contentType = http.DetectContentType(data)
+	extension, ok := extensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedType
+	}
+	return contentType, extension, nil
+}
+
+// Extension
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Decode header read first so image exceeding max pixels rejected before its pixels allocated
+func Decode(data []byte, contentType string, maxPixels int) (image.Image, error) {
+	var decode func(r io.Reader) (image.Image, error)
+	var decodeConfig func(r io.Reader) (image.Config, error)
+	switch contentType {
+	case "image/jpeg":
+		decode, decodeConfig = jpeg.Decode, jpeg.DecodeConfig
+	case "image/png":
+		decode, decodeConfig = png.Decode, png.DecodeConfig
+	case "image/gif":
+		decode, decodeConfig = gif.Decode, gif.DecodeConfig
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > int64(maxPixels) {
+		return nil, ErrTooManyPixels
+	}
+
+	return decode(bytes.NewReader(data))
+}
+
+// Thumbnail downscale image to fit inside max size while keeping aspect ratio
+func Thumbnail(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= maxSize && srcHeight <= maxSize {
+		return src
+	}
+
+	width, height := maxSize, maxSize
+	if srcWidth > srcHeight {
+		height = max(1, srcHeight*maxSize/srcWidth)
+	} else {
+		width = max(1, srcWidth*maxSize/srcHeight)
+	}
+
+	// Read pixel straight from premultiplied buffer, draw convert common decoded type through its fast path
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, srcWidth, srcHeight))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+	origin := rgba.Bounds().Min
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := origin.Y + y*srcHeight/height
+		y1 := max(y0+1, origin.Y+(y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := origin.X + x*srcWidth/width
+			x1 := max(x0+1, origin.X+(x+1)*srcWidth/width)
+			dst.SetNRGBA(x, y, average(rgba, x0, y0, x1, y1))
+		}
+	}
+
+	return dst
+}
+
+// average calculate mean color of source box, averaged premultiplied so transparent pixel does not bleed its color
+func average(src *image.RGBA, x0, y0, x1, y1 int) color.NRGBA {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		pix := src.Pix[src.PixOffset(x0, y):src.PixOffset(x1, y)]
+		for i := 0; i < len(pix); i += 4 {
+			r += uint64(pix[i])
+			g += uint64(pix[i+1])
+			b += uint64(pix[i+2])
+			a += uint64(pix[i+3])
+			n++
+		}
+	}
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{R: uint8(r * 255 / a), G: uint8(g * 255 / a), B: uint8(b * 255 / a), A: uint8(a / n)}
+}
+
+// Encode thumbnail as png for transparent source and jpeg otherwise
+func Encode(img image.Image, contentType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if contentType == "image/png" || contentType == "image/gif" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
@@ -0,0 +1,70 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImaging(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 800; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("SuccessSniffPNG", func(t *testing.T) {
+		contentType, extension, err := Sniff(buf.Bytes())
+		if assert.NoError(t, err) {
+			assert.Equal(t, "image/png", contentType)
+			assert.Equal(t, "png", extension)
+		}
+	})
+
+	t.Run("FailedSniffText", func(t *testing.T) {
+		_, _, err := Sniff([]byte("<html>not an image</html>"))
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+
+	t.Run("SuccessThumbnailKeepRatio", func(t *testing.T) {
+		thumbnail := Thumbnail(src, 320)
+		assert.Equal(t, 320, thumbnail.Bounds().Dx())
+		assert.Equal(t, 160, thumbnail.Bounds().Dy())
+		assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 255}, thumbnail.At(10, 10))
+	})
+
+	t.Run("SuccessDecodeWithinMaxPixels", func(t *testing.T) {
+		img, err := Decode(buf.Bytes(), "image/png", 800*400)
+		if assert.NoError(t, err) {
+			assert.Equal(t, src.Bounds(), img.Bounds())
+		}
+	})
+
+	t.Run("FailedDecodeTooManyPixels", func(t *testing.T) {
+		_, err := Decode(buf.Bytes(), "image/png", 800*400-1)
+		assert.ErrorIs(t, err, ErrTooManyPixels)
+	})
+
+	t.Run("SuccessThumbnailTransparentEdge", func(t *testing.T) {
+		edge := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+		edge.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+		edge.SetNRGBA(1, 0, color.NRGBA{G: 255, A: 0})
+		thumbnail := Thumbnail(edge, 2)
+		assert.Equal(t, color.NRGBA{R: 255, A: 127}, thumbnail.At(0, 0))
+	})
+
+	t.Run("SuccessThumbnailSmallImage", func(t *testing.T) {
+		small := image.NewNRGBA(image.Rect(0, 0, 100, 50))
+		assert.Equal(t, small.Bounds(), Thumbnail(small, 320).Bounds())
+	})
+}
@@ -0,0 +1,272 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scale number of minor unit in one major unit, amount keep two decimal places
+const (
+	Scale    = 100
+	Decimals = 2
+)
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// Amount exact decimal money value stored as integer minor unit
+type Amount int64
+
+// New create amount from major unit
+func New(units int64) Amount {
+	return Amount(units * Scale)
+}
+
+// FromMinor create amount from minor unit
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse decimal text such as "15000", "-12.5" or "15000.00" without going through float
+func Parse(value string) (Amount, error) {
+	amount, err := parseDecimal(value, Decimals)
+	return Amount(amount), err
+}
+
+// parseDecimal convert decimal text into integer scaled by given decimal places
+func parseDecimal(value string, decimals int) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	// Extra decimal digits only allowed when they are zero, e.g. numeric(18,4)
+	if len(fraction) > decimals {
+		if strings.Trim(fraction[decimals:], "0") != "" {
+			return 0, ErrInvalidAmount
+		}
+		fraction = fraction[:decimals]
+	}
+	fraction += strings.Repeat("0", decimals-len(fraction))
+
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return 0, ErrInvalidAmount
+	}
+
+	scale := pow10(decimals)
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<63-1)/scale {
+		return 0, ErrInvalidAmount
+	}
+
+	var minor int64
+	if fraction != "" {
+		minor, _ = strconv.ParseInt(fraction, 10, 64)
+	}
+
+	result := units*scale + minor
+	if negative {
+		result = -result
+	}
+
+	return result, nil
+}
+
+// formatDecimal format scaled integer with fixed decimal places
+func formatDecimal(value int64, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	scale := pow10(decimals)
+	return fmt.Sprintf("%s%d.%0*d", sign, value/scale, decimals, value%scale)
+}
+
+// trimDecimal remove trailing zero so whole value encoded as integer
+func trimDecimal(value string) string {
+	value = strings.TrimRight(value, "0")
+	return strings.TrimSuffix(value, ".")
+}
+
+// unmarshalDecimal accept JSON number or string
+func unmarshalDecimal(data []byte, decimals int) (int64, bool, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return 0, false, nil
+	}
+	if len(data) > 1 && data[0] == '"' {
+		unquoted, err := strconv.Unquote(string(data))
+		if err != nil {
+			return 0, false, err
+		}
+		data = []byte(unquoted)
+	}
+	if bytes.ContainsAny(data, "eE") {
+		return 0, false, ErrInvalidAmount
+	}
+
+	value, err := parseDecimal(string(data), decimals)
+	if err != nil {
+		return 0, false, err
+	}
+	return value, true, nil
+}
+
+// scanDecimal read numeric column
+func scanDecimal(value interface{}, decimals int) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		return parseDecimal(string(v), decimals)
+	case string:
+		return parseDecimal(v, decimals)
+	case int64:
+		return v * pow10(decimals), nil
+	}
+	return 0, fmt.Errorf("money: cannot scan %T", value)
+}
+
+func pow10(decimals int) int64 {
+	result := int64(1)
+	for i := 0; i < decimals; i++ {
+		result *= 10
+	}
+	return result
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MustParse
+func MustParse(value string) Amount {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// Add
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul multiply amount by quantity
+func (a Amount) Mul(quantity int64) Amount {
+	return a * Amount(quantity)
+}
+
+// Cmp return -1, 0 or 1 when a is less, equal or greater than b
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsPositive
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// IsNegative
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// IsZero
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// Units return major unit part, fraction truncated toward zero
+func (a Amount) Units() int64 {
+	return int64(a) / Scale
+}
+
+// Minor return amount in minor unit
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Sum
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// String format amount with fixed two decimal places
+func (a Amount) String() string {
+	return formatDecimal(int64(a), Decimals)
+}
+
+// MarshalJSON encode amount as JSON number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(trimDecimal(a.String())), nil
+}
+
+// UnmarshalJSON accept JSON number or string
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalDecimal(data, Decimals)
+	if err != nil {
+		return err
+	}
+	if ok {
+		*a = Amount(value)
+	}
+	return nil
+}
+
+// Value store amount as numeric text
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan read numeric column
+func (a *Amount) Scan(value interface{}) error {
+	amount, err := scanDecimal(value, Decimals)
+	if err != nil {
+		return err
+	}
+	*a = Amount(amount)
+	return nil
+}
@@ -0,0 +1,133 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]Amount{
+		"15000":       1500000,
+		"15000.5":     1500050,
+		"15000.50":    1500050,
+		"15000.0000":  1500000,
+		"-12.05":      -1205,
+		".5":          50,
+		"0":           0,
+		" 99999.99 ":  9999999,
+		"+1":          100,
+		"49999.00000": 4999900,
+	}
+
+	for value, expected := range cases {
+		amount, err := Parse(value)
+		if assert.NoError(t, err, value) {
+			assert.Equal(t, expected, amount, value)
+		}
+	}
+
+	for _, value := range []string{"", "-", ".", "1.005", "abc", "1e5", "1,5", "99999999999999999999"} {
+		_, err := Parse(value)
+		assert.ErrorIs(t, err, ErrInvalidAmount, value)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	t.Run("SuccessSumWithoutDrift", func(t *testing.T) {
+		var total Amount
+		for i := 0; i < 10; i++ {
+			total = total.Add(MustParse("0.10"))
+		}
+		assert.Equal(t, New(1), total)
+	})
+
+	t.Run("SuccessCompare", func(t *testing.T) {
+		assert.Equal(t, 1, MustParse("50000").Cmp(MustParse("49999.99")))
+		assert.Equal(t, 0, MustParse("50000.00").Cmp(New(50000)))
+		assert.Equal(t, -1, New(1).Cmp(New(2)))
+	})
+
+	t.Run("SuccessUnits", func(t *testing.T) {
+		assert.Equal(t, int64(199999), MustParse("199999.99").Units())
+		assert.Equal(t, int64(3), MustParse("1.50").Mul(2).Units())
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("SuccessMarshal", func(t *testing.T) {
+		data, err := json.Marshal(map[string]Amount{"A": New(999999), "B": MustParse("12.5"), "C": MustParse("-0.05")})
+		if assert.NoError(t, err) {
+			assert.Equal(t, `{"A":999999,"B":12.5,"C":-0.05}`, string(data))
+		}
+	})
+
+	t.Run("SuccessUnmarshalNumberAndString", func(t *testing.T) {
+		var value struct{ A, B Amount }
+		if assert.NoError(t, json.Unmarshal([]byte(`{"A":15000.25,"B":"99999"}`), &value)) {
+			assert.Equal(t, MustParse("15000.25"), value.A)
+			assert.Equal(t, New(99999), value.B)
+		}
+	})
+
+	t.Run("FailedUnmarshalTooManyDecimals", func(t *testing.T) {
+		var value Amount
+		assert.Error(t, json.Unmarshal([]byte(`0.30000000000000004`), &value))
+	})
+}
+
+func TestSQL(t *testing.T) {
+	t.Run("SuccessValue", func(t *testing.T) {
+		value, err := MustParse("15000.5").Value()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "15000.50", value)
+		}
+	})
+
+	t.Run("SuccessScanNumeric", func(t *testing.T) {
+		var amount Amount
+		if assert.NoError(t, amount.Scan([]byte("999999.00"))) {
+			assert.Equal(t, New(999999), amount)
+		}
+	})
+}
+
+func TestRate(t *testing.T) {
+	t.Run("SuccessConvertRoundHalfUp", func(t *testing.T) {
+		rate, err := ParseRate("0.000064")
+		if assert.NoError(t, err) {
+			// 999999 * 0.000064 = 63.999936
+			assert.Equal(t, MustParse("64.00"), New(999999).Convert(rate))
+			// 7812.5 * 0.000064 = 0.5, rounded half away from zero to 0.50
+			assert.Equal(t, MustParse("0.50"), MustParse("7812.5").Convert(rate))
+		}
+	})
+
+	t.Run("SuccessConvertSameCurrency", func(t *testing.T) {
+		assert.Equal(t, MustParse("15000.55"), MustParse("15000.55").Convert(OneRate))
+	})
+
+	t.Run("SuccessRateFromRat", func(t *testing.T) {
+		usd, _ := ParseRate("0.000064")
+		sgd, _ := ParseRate("0.000086")
+		cross := RateFromRat(new(big.Rat).Quo(sgd.Rat(), usd.Rat()))
+		assert.Equal(t, "1.3437500000", cross.String())
+	})
+
+	t.Run("SuccessJSON", func(t *testing.T) {
+		data, err := json.Marshal(MustParseRate("15625"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "15625", string(data))
+		}
+	})
+}
+
+func MustParseRate(value string) Rate {
+	rate, err := ParseRate(value)
+	if err != nil {
+		panic(err)
+	}
+	return rate
+}
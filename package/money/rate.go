@@ -0,0 +1,94 @@
+package money
+
+import (
+	"database/sql/driver"
+	"math/big"
+)
+
+// RateDecimals precision of exchange rate, enough for IDR to USD
+const RateDecimals = 10
+
+// Rate exact exchange rate stored as integer scaled by RateDecimals
+type Rate int64
+
+// OneRate rate between same currency
+var OneRate = Rate(pow10(RateDecimals))
+
+// ParseRate
+func ParseRate(value string) (Rate, error) {
+	rate, err := parseDecimal(value, RateDecimals)
+	return Rate(rate), err
+}
+
+// RateFromRat round rational number half away from zero into rate
+func RateFromRat(value *big.Rat) Rate {
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt64(pow10(RateDecimals)))
+	return Rate(roundRat(scaled).Int64())
+}
+
+// Rat
+func (r Rate) Rat() *big.Rat {
+	return big.NewRat(int64(r), pow10(RateDecimals))
+}
+
+// IsPositive
+func (r Rate) IsPositive() bool {
+	return r > 0
+}
+
+// Convert amount using rate, result rounded half away from zero to minor unit
+func (a Amount) Convert(rate Rate) Amount {
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(a)), rate.Rat())
+	return Amount(roundRat(converted).Int64())
+}
+
+// roundRat round half away from zero into integer
+func roundRat(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+// String format rate with fixed decimal places
+func (r Rate) String() string {
+	return formatDecimal(int64(r), RateDecimals)
+}
+
+// MarshalJSON encode rate as JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(trimDecimal(r.String())), nil
+}
+
+// UnmarshalJSON accept JSON number or string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalDecimal(data, RateDecimals)
+	if err != nil {
+		return err
+	}
+	if ok {
+		*r = Rate(value)
+	}
+	return nil
+}
+
+// Value store rate as numeric text
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan read numeric column
+func (r *Rate) Scan(value interface{}) error {
+	rate, err := scanDecimal(value, RateDecimals)
+	if err != nil {
+		return err
+	}
+	*r = Rate(rate)
+	return nil
+}
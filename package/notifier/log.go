@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"context"
+
+	"pcstakehometest/package/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Log write message into application log, used when no delivery channel configured
+type Log struct {
+	log *logger.LogRus
+}
+
+func NewLog(log *logger.LogRus) *Log {
+	return &Log{log: log}
+}
+
+func (n *Log) Notify(ctx context.Context, message *Message) error {
+	n.log.WithFields(logrus.Fields{
+		"user":  message.UserID,
+		"event": message.Event,
+		"data":  message.Data,
+	}).Infof("%s: %s", message.Title, message.Body)
+	return nil
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/logger"
+)
+
+const (
+	DriverLog     = "log"
+	DriverWebhook = "webhook"
+)
+
+const (
+	EventPriceDrop             = "price_drop"
+	EventOrderCancelled        = "order_cancelled"
+	EventCancellationRequested = "cancellation_requested"
+	EventCancellationResolved  = "cancellation_resolved"
+	EventOrderPaid             = "order_paid"
+	EventReturnUpdated         = "return_updated"
+	EventShipmentUpdated       = "shipment_updated"
+)
+
+// Message delivered to a user
+type Message struct {
+	UserID int
+	Event  string
+	Title  string
+	Body   string
+	Data   map[string]interface{}
+}
+
+// Notifier deliver message to user through configured channel
+type Notifier interface {
+	Notify(ctx context.Context, message *Message) error
+}
+
+// NewNotifier create notifier based on configured driver
+func NewNotifier(log *logger.LogRus) Notifier {
+	cfg := config.Get().Notifier
+
+	switch cfg.Driver {
+	case DriverWebhook:
+		return NewWebhook(cfg.Webhook.URL, cfg.Webhook.TimeoutDuration)
+	case DriverLog, "":
+		return NewLog(log)
+	}
+
+	log.Fatalf("NewNotifier: notifier driver %s not supported", cfg.Driver)
+	return nil
+}
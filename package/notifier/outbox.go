@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrNoOutbox = errors.New("notifier: context has no outbox")
+
+type outboxKey struct{}
+
+// Outbox hold message until the transaction producing it committed, so change rolled back is never announced
+type Outbox struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+// WithOutbox attach new outbox to context
+func WithOutbox(ctx context.Context) (context.Context, *Outbox) {
+	outbox := new(Outbox)
+	return context.WithValue(ctx, outboxKey{}, outbox), outbox
+}
+
+// FromContext outbox attached to context, nil when there is none
+func FromContext(ctx context.Context) *Outbox {
+	outbox, _ := ctx.Value(outboxKey{}).(*Outbox)
+	return outbox
+}
+
+// Defer hold message in outbox of context, delivered by whoever commit the transaction
+func Defer(ctx context.Context, message *Message) error {
+	outbox := FromContext(ctx)
+	if outbox == nil {
+		return ErrNoOutbox
+	}
+
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	outbox.messages = append(outbox.messages, message)
+
+	return nil
+}
+
+// Send deliver held message and empty the outbox, one failed delivery does not stop the rest
+func (o *Outbox) Send(ctx context.Context, notifier Notifier) error {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.Lock()
+	messages := o.messages
+	o.messages = nil
+	o.mu.Unlock()
+
+	var errs []error
+	for _, message := range messages {
+		if err := notifier.Notify(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	messages []*Message
+	err      error
+}
+
+func (n *recorder) Notify(ctx context.Context, message *Message) error {
+	n.messages = append(n.messages, message)
+	return n.err
+}
+
+func TestOutboxSendAfterDefer(t *testing.T) {
+	ctx, outbox := WithOutbox(context.Background())
+	n := &recorder{}
+
+	assert.NoError(t, Defer(ctx, &Message{UserID: 1, Event: EventOrderPaid}))
+	assert.NoError(t, Defer(ctx, &Message{UserID: 2, Event: EventOrderPaid}))
+	assert.Empty(t, n.messages)
+
+	assert.NoError(t, outbox.Send(ctx, n))
+	assert.Len(t, n.messages, 2)
+	assert.Equal(t, 1, n.messages[0].UserID)
+
+	// Outbox emptied so a second send deliver nothing
+	assert.NoError(t, outbox.Send(ctx, n))
+	assert.Len(t, n.messages, 2)
+}
+
+func TestOutboxSendFailed(t *testing.T) {
+	ctx, outbox := WithOutbox(context.Background())
+	n := &recorder{err: errors.New("unreachable")}
+
+	assert.NoError(t, Defer(ctx, &Message{UserID: 1}))
+	assert.NoError(t, Defer(ctx, &Message{UserID: 2}))
+
+	assert.Error(t, outbox.Send(ctx, n))
+	assert.Len(t, n.messages, 2)
+}
+
+func TestDeferWithoutOutbox(t *testing.T) {
+	assert.ErrorIs(t, Defer(context.Background(), &Message{UserID: 1}), ErrNoOutbox)
+	assert.NoError(t, FromContext(context.Background()).Send(context.Background(), &recorder{}))
+}
@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, time.Second)
+	err := webhook.Notify(context.Background(), &Message{
+		UserID: 2,
+		Event:  EventPriceDrop,
+		Title:  "Harga turun",
+		Body:   "Produk A turun harga",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, received.UserID)
+	assert.Equal(t, EventPriceDrop, received.Event)
+}
+
+func TestWebhookNotifyFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, time.Second)
+	err := webhook.Notify(context.Background(), &Message{UserID: 2})
+
+	assert.Error(t, err)
+}
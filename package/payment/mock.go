@@ -0,0 +1,129 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Outcome mock gateway produce for new intent
+const (
+	OutcomeSucceed = "succeed"
+	OutcomeFail    = "fail"
+	OutcomeTimeout = "timeout"
+)
+
+// Mock local gateway, every intent settled by calling Webhook with the configured outcome
+type Mock struct {
+	secret   string
+	mu       sync.Mutex
+	outcome  string
+	outcomes map[string]string
+	intents  map[string]*Intent
+	refunds  map[string]string
+}
+
+func NewMock(secret string, outcome string) *Mock {
+	if outcome == "" {
+		outcome = OutcomeSucceed
+	}
+	return &Mock{
+		secret:   secret,
+		outcome:  outcome,
+		outcomes: map[string]string{},
+		intents:  map[string]*Intent{},
+		refunds:  map[string]string{},
+	}
+}
+
+func (g *Mock) Name() string {
+	return ProviderMock
+}
+
+// SetOutcome outcome of intent created afterward
+func (g *Mock) SetOutcome(outcome string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.outcome = outcome
+}
+
+func (g *Mock) CreateIntent(ctx context.Context, intent *Intent) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.outcome == OutcomeTimeout {
+		return nil, ErrTimeout
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	reference := "mock_" + hex.EncodeToString(random)
+	g.outcomes[reference] = g.outcome
+	g.intents[reference] = intent
+
+	return &Charge{
+		ProviderReference: reference,
+		Status:            StatusPending,
+	}, nil
+}
+
+// Refund settled right away, outcome fail or timeout apply to refund too, retry of the same reference return the refund already made
+func (g *Mock) Refund(ctx context.Context, refund *Refund) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if reference, ok := g.refunds[refund.Reference]; ok {
+		return reference, nil
+	}
+
+	switch g.outcome {
+	case OutcomeTimeout:
+		return "", ErrTimeout
+	case OutcomeFail:
+		return "", ErrDeclined
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	reference := "mock_refund_" + hex.EncodeToString(random)
+	g.refunds[refund.Reference] = reference
+	return reference, nil
+}
+
+func (g *Mock) ParseWebhook(payload []byte, signature string) (*Event, error) {
+	return parseEvent(g.secret, payload, signature)
+}
+
+// Webhook signed callback the gateway would send once buyer finish paying the intent
+func (g *Mock) Webhook(providerReference string) ([]byte, string, error) {
+	g.mu.Lock()
+	outcome := g.outcomes[providerReference]
+	intent := g.intents[providerReference]
+	g.mu.Unlock()
+
+	event := &Event{
+		ProviderReference: providerReference,
+		Status:            StatusSucceeded,
+	}
+	if intent != nil {
+		event.Amount = intent.Amount
+		event.Currency = intent.Currency
+	}
+	if outcome == OutcomeFail {
+		event.Status = StatusFailed
+		event.FailureReason = ErrDeclined.Error()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, Sign(g.secret, payload), nil
+}
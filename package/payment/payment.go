@@ -0,0 +1,119 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+)
+
+const ProviderMock = "mock"
+
+// HeaderSignature carry hex HMAC-SHA256 of webhook body signed with webhook secret
+const HeaderSignature = "X-Payment-Signature"
+
+// Status reported by gateway
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrTimeout          = errors.New("payment gateway timeout")
+	ErrDeclined         = errors.New("payment declined by gateway")
+	ErrInvalidSignature = errors.New("invalid payment webhook signature")
+	ErrInvalidEvent     = errors.New("invalid payment webhook event")
+)
+
+// Intent amount to be collected from buyer
+type Intent struct {
+	Reference string
+	Amount    money.Amount
+	Currency  string
+}
+
+// Charge gateway side of an intent
+type Charge struct {
+	ProviderReference string
+	Status            string
+	PaymentURL        string `json:",omitempty"`
+}
+
+// Event webhook callback about a charge, Amount and Currency what the gateway actually collected
+type Event struct {
+	ProviderReference string
+	Status            string
+	Amount            money.Amount
+	Currency          string `json:",omitempty"`
+	FailureReason     string `json:",omitempty"`
+}
+
+// Refund return part or all of a succeeded charge, Reference unique per refund so gateway ignore a retried one
+type Refund struct {
+	ProviderReference string
+	Reference         string
+	Amount            money.Amount
+	Currency          string
+}
+
+// PaymentGateway collect payment from buyer and report the result through signed webhook
+type PaymentGateway interface {
+	Name() string
+	CreateIntent(ctx context.Context, intent *Intent) (*Charge, error)
+	ParseWebhook(payload []byte, signature string) (*Event, error)
+	Refund(ctx context.Context, refund *Refund) (string, error)
+}
+
+// NewGateway create payment gateway based on configuration
+func NewGateway(log *logger.LogRus) PaymentGateway {
+	cfg := config.Get().Payment
+
+	switch cfg.Provider {
+	case ProviderMock, "":
+		return NewMock(cfg.WebhookSecret, cfg.Mock.Outcome)
+	}
+
+	log.Fatalf("NewGateway: payment provider %s not supported", cfg.Provider)
+	return nil
+}
+
+// Sign webhook payload
+func Sign(secret string, payload []byte) string {
+	return hex.EncodeToString(sum(secret, payload))
+}
+
+func sum(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseEvent verify signature before trusting the payload
+func parseEvent(secret string, payload []byte, signature string) (*Event, error) {
+	received, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(received, sum(secret, payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	event := new(Event)
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, ErrInvalidEvent
+	}
+	if event.ProviderReference == "" {
+		return nil, ErrInvalidEvent
+	}
+	switch event.Status {
+	case StatusSucceeded, StatusFailed:
+	default:
+		return nil, ErrInvalidEvent
+	}
+
+	return event, nil
+}
@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"pcstakehometest/package/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockSucceed(t *testing.T) {
+	gateway := NewMock("secret", OutcomeSucceed)
+
+	charge, err := gateway.CreateIntent(context.Background(), &Intent{Reference: "PAY-1", Amount: money.New(1000), Currency: "IDR"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, StatusPending, charge.Status)
+	}
+
+	payload, signature, err := gateway.Webhook(charge.ProviderReference)
+	assert.NoError(t, err)
+
+	event, err := gateway.ParseWebhook(payload, signature)
+	if assert.NoError(t, err) {
+		assert.Equal(t, charge.ProviderReference, event.ProviderReference)
+		assert.Equal(t, StatusSucceeded, event.Status)
+		assert.Equal(t, money.New(1000), event.Amount)
+		assert.Equal(t, "IDR", event.Currency)
+	}
+}
+
+func TestMockFail(t *testing.T) {
+	gateway := NewMock("secret", OutcomeSucceed)
+	gateway.SetOutcome(OutcomeFail)
+
+	charge, err := gateway.CreateIntent(context.Background(), &Intent{Reference: "PAY-2", Amount: money.New(1000), Currency: "IDR"})
+	assert.NoError(t, err)
+
+	payload, signature, err := gateway.Webhook(charge.ProviderReference)
+	assert.NoError(t, err)
+
+	event, err := gateway.ParseWebhook(payload, signature)
+	if assert.NoError(t, err) {
+		assert.Equal(t, StatusFailed, event.Status)
+		assert.NotEmpty(t, event.FailureReason)
+	}
+}
+
+func TestMockTimeout(t *testing.T) {
+	gateway := NewMock("secret", OutcomeTimeout)
+
+	_, err := gateway.CreateIntent(context.Background(), &Intent{Reference: "PAY-3", Amount: money.New(1000), Currency: "IDR"})
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestParseWebhook(t *testing.T) {
+	gateway := NewMock("secret", OutcomeSucceed)
+	payload := []byte(`{"ProviderReference":"mock_1","Status":"succeeded"}`)
+
+	_, err := gateway.ParseWebhook(payload, Sign("other", payload))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = gateway.ParseWebhook(payload, "not-hex")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	invalid := []byte(`{"ProviderReference":"mock_1","Status":"refunded"}`)
+	_, err = gateway.ParseWebhook(invalid, Sign("secret", invalid))
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+
+	event, err := gateway.ParseWebhook(payload, Sign("secret", payload))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "mock_1", event.ProviderReference)
+	}
+}
+
+func TestMockRefund(t *testing.T) {
+	gateway := NewMock("secret", OutcomeSucceed)
+	refund := &Refund{ProviderReference: "mock_1", Reference: "REFUND-1", Amount: money.New(500), Currency: "IDR"}
+
+	reference, err := gateway.Refund(context.Background(), refund)
+	if assert.NoError(t, err) {
+		assert.NotEmpty(t, reference)
+	}
+
+	gateway.SetOutcome(OutcomeFail)
+	retried, err := gateway.Refund(context.Background(), refund)
+	if assert.NoError(t, err) {
+		assert.Equal(t, reference, retried)
+	}
+
+	_, err = gateway.Refund(context.Background(), &Refund{ProviderReference: "mock_1", Reference: "REFUND-2", Amount: money.New(500), Currency: "IDR"})
+	assert.ErrorIs(t, err, ErrDeclined)
+
+	gateway.SetOutcome(OutcomeTimeout)
+	_, err = gateway.Refund(context.Background(), &Refund{ProviderReference: "mock_1", Reference: "REFUND-3", Amount: money.New(500), Currency: "IDR"})
+	assert.ErrorIs(t, err, ErrTimeout)
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"pcstakehometest/package/logger"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(context.Context) error
+}
+
+// Register run job periodically while application is running
+func Register(lifecycle fx.Lifecycle, log *logger.LogRus, job Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lifecycle.Append(
+		fx.Hook{
+			OnStart: func(context.Context) error {
+				go run(ctx, log, job)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		},
+	)
+}
+
+func run(ctx context.Context, log *logger.LogRus, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil {
+				log.WithField("job", job.Name).Error(err)
+			}
+		}
+	}
+}
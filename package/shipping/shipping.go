@@ -0,0 +1,102 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/logger"
+	"pcstakehometest/package/money"
+)
+
+const (
+	ProviderTable = "table"
+
+	// AttributeWeight product attribute key holding weight of one unit
+	AttributeWeight = "weight"
+)
+
+var (
+	ErrRouteNotFound   = errors.New("shipping route not found")
+	ErrServiceNotFound = errors.New("shipping service not found")
+	ErrInvalidRate     = errors.New("invalid shipping rate")
+)
+
+// Parcel package sent from origin to destination, weight in gram
+type Parcel struct {
+	Origin      string
+	Destination string
+	Weight      int
+}
+
+// Quote fee of one carrier service to deliver a parcel
+type Quote struct {
+	Carrier       string
+	Service       string
+	Fee           money.Amount
+	Currency      string
+	EstimatedDays int
+}
+
+// Carrier give quote of every service able to deliver the parcel
+type Carrier interface {
+	Quote(ctx context.Context, parcel *Parcel) ([]*Quote, error)
+}
+
+// NewCarrier create shipping carrier based on configuration
+func NewCarrier(log *logger.LogRus) Carrier {
+	cfg := config.Get().Shipping
+
+	switch cfg.Provider {
+	case ProviderTable, "":
+		return NewTableCarrier(cfg.RatesFile)
+	}
+
+	log.Fatalf("NewCarrier: shipping provider %s not supported", cfg.Provider)
+	return nil
+}
+
+// Select quote of the chosen carrier service
+func Select(quotes []*Quote, carrier, service string) (*Quote, error) {
+	for _, quote := range quotes {
+		if strings.EqualFold(quote.Carrier, carrier) && strings.EqualFold(quote.Service, service) {
+			return quote, nil
+		}
+	}
+	return nil, ErrServiceNotFound
+}
+
+// Weight of one unit in gram read from product attribute, fallback used when product has no weight
+func Weight(items []attribute.Item, fallback int) int {
+	for _, item := range items {
+		if item.Key != AttributeWeight {
+			continue
+		}
+		value, ok := item.Value.(float64)
+		if !ok || value <= 0 {
+			break
+		}
+		if strings.EqualFold(item.Unit, "kg") {
+			value *= 1000
+		}
+		return int(math.Ceil(value))
+	}
+	return fallback
+}
+
+// sortQuotes cheapest first, then by carrier and service name
+func sortQuotes(quotes []*Quote) {
+	sort.SliceStable(quotes, func(i, j int) bool {
+		if cmp := quotes[i].Fee.Cmp(quotes[j].Fee); cmp != 0 {
+			return cmp < 0
+		}
+		if quotes[i].Carrier != quotes[j].Carrier {
+			return quotes[i].Carrier < quotes[j].Carrier
+		}
+		return quotes[i].Service < quotes[j].Service
+	})
+}
@@ -0,0 +1,92 @@
+package shipping
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pcstakehometest/package/attribute"
+	"pcstakehometest/package/money"
+)
+
+func TestTableCarrier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shipping_rates.csv")
+	if err := os.WriteFile(path, []byte(`origin,destination,carrier,service,max_weight,fee,currency,estimated_days
+# comment ignored
+jakarta,bandung,jne,reg,1000,11000,IDR,2
+jakarta,bandung,JNE,REG,5000,30000,IDR,2
+jakarta,bandung,JNE,YES,1000,20000,IDR,1
+*,*,JNE,REG,1000,25000,IDR,5
+*,*,SICEPAT,REG,5000,24000,IDR,5`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	carrier := NewTableCarrier(path)
+	ctx := context.Background()
+
+	t.Run("SuccessSpecificZone", func(t *testing.T) {
+		quotes, err := carrier.Quote(ctx, &Parcel{Origin: " Jakarta", Destination: "BANDUNG", Weight: 800})
+		if assert.NoError(t, err) && assert.Len(t, quotes, 3) {
+			assert.Equal(t, "JNE", quotes[0].Carrier)
+			assert.Equal(t, "REG", quotes[0].Service)
+			assert.Equal(t, money.New(11000), quotes[0].Fee)
+			assert.Equal(t, "YES", quotes[1].Service)
+			assert.Equal(t, "SICEPAT", quotes[2].Carrier)
+		}
+	})
+
+	t.Run("SuccessHeavierBracket", func(t *testing.T) {
+		quotes, err := carrier.Quote(ctx, &Parcel{Origin: "jakarta", Destination: "bandung", Weight: 1200})
+		if assert.NoError(t, err) && assert.Len(t, quotes, 2) {
+			assert.Equal(t, "SICEPAT", quotes[0].Carrier)
+			assert.Equal(t, money.New(30000), quotes[1].Fee)
+		}
+	})
+
+	t.Run("SuccessWildcardZone", func(t *testing.T) {
+		quotes, err := carrier.Quote(ctx, &Parcel{Origin: "medan", Destination: "bandung", Weight: 500})
+		if assert.NoError(t, err) && assert.Len(t, quotes, 2) {
+			assert.Equal(t, money.New(24000), quotes[0].Fee)
+			assert.Equal(t, 5, quotes[0].EstimatedDays)
+		}
+	})
+
+	t.Run("FailedTooHeavy", func(t *testing.T) {
+		_, err := carrier.Quote(ctx, &Parcel{Origin: "jakarta", Destination: "bandung", Weight: 6000})
+		assert.ErrorIs(t, err, ErrRouteNotFound)
+	})
+
+	t.Run("FailedInvalidFile", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.csv")
+		if err := os.WriteFile(invalid, []byte("origin,destination,carrier,service,max_weight,fee,currency,estimated_days\njakarta,bandung,JNE,REG,heavy,1000,IDR,1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := NewTableCarrier(invalid).Quote(ctx, &Parcel{Origin: "jakarta", Destination: "bandung", Weight: 1})
+		assert.ErrorIs(t, err, ErrInvalidRate)
+	})
+}
+
+func TestSelect(t *testing.T) {
+	quotes := []*Quote{
+		{Carrier: "JNE", Service: "REG"},
+		{Carrier: "JNE", Service: "YES"},
+	}
+
+	quote, err := Select(quotes, "jne", "yes")
+	if assert.NoError(t, err) {
+		assert.Equal(t, quotes[1], quote)
+	}
+
+	_, err = Select(quotes, "SICEPAT", "REG")
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
+
+func TestWeight(t *testing.T) {
+	assert.Equal(t, 250, Weight([]attribute.Item{{Key: "weight", Value: float64(250), Unit: "g"}}, 1000))
+	assert.Equal(t, 1500, Weight([]attribute.Item{{Key: "weight", Value: 1.5, Unit: "kg"}}, 1000))
+	assert.Equal(t, 1, Weight([]attribute.Item{{Key: "weight", Value: 0.2}}, 1000))
+	assert.Equal(t, 1000, Weight([]attribute.Item{{Key: "color", Value: "red"}}, 1000))
+	assert.Equal(t, 1000, Weight(nil, 1000))
+}
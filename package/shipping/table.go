@@ -0,0 +1,204 @@
+package shipping
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pcstakehometest/package/money"
+)
+
+// Wildcard zone matching every origin or destination
+const Wildcard = "*"
+
+// tableColumns header of rates file
+var tableColumns = []string{"origin", "destination", "carrier", "service", "max_weight", "fee", "currency", "estimated_days"}
+
+// tableRate fee of a service between two zones for parcel up to MaxWeight gram
+type tableRate struct {
+	Origin        string
+	Destination   string
+	Carrier       string
+	Service       string
+	MaxWeight     int
+	Fee           money.Amount
+	Currency      string
+	EstimatedDays int
+}
+
+// TableCarrier read zone and weight rates from csv file for offline use, file reloaded when modified
+type TableCarrier struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	rates   []*tableRate
+}
+
+func NewTableCarrier(path string) *TableCarrier {
+	return &TableCarrier{path: path}
+}
+
+// Quote every service using the most specific zone matching the parcel and the lightest weight bracket able to carry it
+func (c *TableCarrier) Quote(ctx context.Context, parcel *Parcel) ([]*Quote, error) {
+	rates, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	origin, destination := normalize(parcel.Origin), normalize(parcel.Destination)
+
+	type service struct {
+		score int
+		rate  *tableRate
+	}
+	services := map[string]*service{}
+	keys := []string{}
+	for _, rate := range rates {
+		score, ok := match(rate, origin, destination)
+		if !ok || rate.MaxWeight < parcel.Weight {
+			continue
+		}
+
+		key := rate.Carrier + "/" + rate.Service
+		current, ok := services[key]
+		if !ok {
+			services[key] = &service{score: score, rate: rate}
+			keys = append(keys, key)
+			continue
+		}
+		if score > current.score || (score == current.score && rate.MaxWeight < current.rate.MaxWeight) {
+			current.score = score
+			current.rate = rate
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrRouteNotFound
+	}
+
+	quotes := make([]*Quote, 0, len(keys))
+	for _, key := range keys {
+		rate := services[key].rate
+		quotes = append(quotes, &Quote{
+			Carrier:       rate.Carrier,
+			Service:       rate.Service,
+			Fee:           rate.Fee,
+			Currency:      rate.Currency,
+			EstimatedDays: rate.EstimatedDays,
+		})
+	}
+	sortQuotes(quotes)
+
+	return quotes, nil
+}
+
+// match exact zone score higher than wildcard, origin weigh more than destination
+func match(rate *tableRate, origin, destination string) (int, bool) {
+	score := 0
+	switch rate.Origin {
+	case origin:
+		score += 2
+	case Wildcard:
+	default:
+		return 0, false
+	}
+	switch rate.Destination {
+	case destination:
+		score++
+	case Wildcard:
+	default:
+		return 0, false
+	}
+	return score, true
+}
+
+func normalize(zone string) string {
+	return strings.ToLower(strings.TrimSpace(zone))
+}
+
+func (c *TableCarrier) load() ([]*tableRate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.rates != nil && info.ModTime().Equal(c.modTime) {
+		return c.rates, nil
+	}
+
+	file, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(tableColumns)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrInvalidRate
+	}
+	for i, column := range tableColumns {
+		if normalize(records[0][i]) != column {
+			return nil, ErrInvalidRate
+		}
+	}
+
+	rates := make([]*tableRate, 0, len(records)-1)
+	for _, record := range records[1:] {
+		rate, err := parseRate(record)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	c.rates = rates
+	c.modTime = info.ModTime()
+
+	return rates, nil
+}
+
+func parseRate(record []string) (*tableRate, error) {
+	maxWeight, err := strconv.Atoi(strings.TrimSpace(record[4]))
+	if err != nil || maxWeight <= 0 {
+		return nil, ErrInvalidRate
+	}
+	fee, err := money.Parse(strings.TrimSpace(record[5]))
+	if err != nil || fee.IsNegative() {
+		return nil, ErrInvalidRate
+	}
+	estimatedDays, err := strconv.Atoi(strings.TrimSpace(record[7]))
+	if err != nil || estimatedDays < 0 {
+		return nil, ErrInvalidRate
+	}
+
+	rate := &tableRate{
+		Origin:        normalize(record[0]),
+		Destination:   normalize(record[1]),
+		Carrier:       strings.ToUpper(strings.TrimSpace(record[2])),
+		Service:       strings.ToUpper(strings.TrimSpace(record[3])),
+		MaxWeight:     maxWeight,
+		Fee:           fee,
+		Currency:      strings.ToUpper(strings.TrimSpace(record[6])),
+		EstimatedDays: estimatedDays,
+	}
+	if rate.Origin == "" || rate.Destination == "" || rate.Carrier == "" || rate.Service == "" || rate.Currency == "" {
+		return nil, ErrInvalidRate
+	}
+
+	return rate, nil
+}
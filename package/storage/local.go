@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Local struct {
+	path    string
+	baseURL string
+}
+
+func NewLocal(path, baseURL string) *Local {
+	return &Local{
+		path:    path,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put
+func (s *Local) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// Get
+func (s *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Delete
+func (s *Local) Delete(ctx context.Context, key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// URL
+func (s *Local) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// filePath prevent key from escaping storage directory
+func (s *Local) filePath(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", errors.New("storage key is empty")
+	}
+	return filepath.Join(s.path, clean), nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/logger"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage save and serve uploaded object by key
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage create storage based on configured driver
+func NewStorage(log *logger.LogRus) Storage {
+	cfg := config.Get().Storage
+
+	switch cfg.Driver {
+	case DriverS3:
+		return NewS3(S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			BaseURL:   cfg.S3.BaseURL,
+		})
+	case DriverLocal, "":
+		return NewLocal(cfg.Local.Path, cfg.Local.BaseURL)
+	}
+
+	log.Fatalf("NewStorage: storage driver %s not supported", cfg.Driver)
+	return nil
+}
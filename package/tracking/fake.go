@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fake local carrier, checkpoint added with Push returned on Track and Webhook build signed payload like real carrier would send
+type Fake struct {
+	secret string
+	mu     sync.Mutex
+	events map[string][]*Event
+}
+
+func NewFake(secret string) *Fake {
+	return &Fake{
+		secret: secret,
+		events: map[string][]*Event{},
+	}
+}
+
+func (t *Fake) Name() string {
+	return ProviderFake
+}
+
+// Push checkpoint returned by following Track
+func (t *Fake) Push(events ...*Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, event := range events {
+		if err := normalize(event); err != nil {
+			return err
+		}
+		key := event.Carrier + "/" + event.TrackingNumber
+		t.events[key] = append(t.events[key], event)
+	}
+	return nil
+}
+
+// Track every checkpoint of the shipment, oldest first
+func (t *Fake) Track(ctx context.Context, carrier, trackingNumber string) ([]*Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := strings.ToUpper(strings.TrimSpace(carrier)) + "/" + strings.ToUpper(strings.TrimSpace(trackingNumber))
+	events := append([]*Event{}, t.events[key]...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	return events, nil
+}
+
+func (t *Fake) ParseWebhook(payload []byte, signature string) ([]*Event, error) {
+	return parseUpdate(t.secret, payload, signature)
+}
+
+// Webhook signed payload carrying the checkpoint
+func (t *Fake) Webhook(events ...*Event) ([]byte, string, error) {
+	payload, err := json.Marshal(&Update{Events: events})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, Sign(t.secret, payload), nil
+}
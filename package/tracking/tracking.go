@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"pcstakehometest/config"
+	"pcstakehometest/package/logger"
+)
+
+const ProviderFake = "fake"
+
+// HeaderSignature carry hex HMAC-SHA256 of webhook body signed with webhook secret
+const HeaderSignature = "X-Tracking-Signature"
+
+// Status reported by carrier
+const (
+	StatusPickedUp       = "picked_up"
+	StatusInTransit      = "in_transit"
+	StatusOutForDelivery = "out_for_delivery"
+	StatusDelivered      = "delivered"
+	StatusFailed         = "failed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid tracking webhook signature")
+	ErrInvalidEvent     = errors.New("invalid tracking event")
+)
+
+// Event one checkpoint of a shipment
+type Event struct {
+	Carrier        string
+	TrackingNumber string
+	Status         string
+	Description    string
+	Location       string `json:",omitempty"`
+	OccurredAt     time.Time
+}
+
+// Update webhook body, carrier may push several checkpoint at once
+type Update struct {
+	Events []*Event
+}
+
+// Tracker follow shipment through carrier, either polled with Track or pushed through signed webhook
+type Tracker interface {
+	Name() string
+	Track(ctx context.Context, carrier, trackingNumber string) ([]*Event, error)
+	ParseWebhook(payload []byte, signature string) ([]*Event, error)
+}
+
+// NewTracker create shipment tracker based on configuration
+func NewTracker(log *logger.LogRus) Tracker {
+	cfg := config.Get().Tracking
+
+	switch cfg.Provider {
+	case ProviderFake, "":
+		return NewFake(cfg.WebhookSecret)
+	}
+
+	log.Fatalf("NewTracker: tracking provider %s not supported", cfg.Provider)
+	return nil
+}
+
+// Sign webhook payload
+func Sign(secret string, payload []byte) string {
+	return hex.EncodeToString(sum(secret, payload))
+}
+
+func sum(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseUpdate verify signature before trusting the payload
+func parseUpdate(secret string, payload []byte, signature string) ([]*Event, error) {
+	received, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(received, sum(secret, payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	update := new(Update)
+	if err := json.Unmarshal(payload, update); err != nil {
+		return nil, ErrInvalidEvent
+	}
+	if len(update.Events) == 0 {
+		return nil, ErrInvalidEvent
+	}
+	for _, event := range update.Events {
+		if err := normalize(event); err != nil {
+			return nil, err
+		}
+	}
+
+	return update.Events, nil
+}
+
+// normalize carrier and tracking number compared case insensitive, unknown status rejected
+func normalize(event *Event) error {
+	if event == nil {
+		return ErrInvalidEvent
+	}
+	event.Carrier = strings.ToUpper(strings.TrimSpace(event.Carrier))
+	event.TrackingNumber = strings.ToUpper(strings.TrimSpace(event.TrackingNumber))
+	if event.Carrier == "" || event.TrackingNumber == "" || event.OccurredAt.IsZero() {
+		return ErrInvalidEvent
+	}
+	switch event.Status {
+	case StatusPickedUp, StatusInTransit, StatusOutForDelivery, StatusDelivered, StatusFailed:
+	default:
+		return ErrInvalidEvent
+	}
+	return nil
+}
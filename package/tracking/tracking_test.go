@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeTrack(t *testing.T) {
+	tracker := NewFake("secret")
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	err := tracker.Push(
+		&Event{Carrier: "jne", TrackingNumber: "jp123", Status: StatusInTransit, Description: "Transit Bandung", OccurredAt: now.Add(time.Hour)},
+		&Event{Carrier: "JNE", TrackingNumber: "JP123", Status: StatusPickedUp, Description: "Diambil kurir", OccurredAt: now},
+	)
+	assert.NoError(t, err)
+
+	events, err := tracker.Track(context.Background(), "Jne", " jp123")
+	if assert.NoError(t, err) && assert.Len(t, events, 2) {
+		assert.Equal(t, StatusPickedUp, events[0].Status)
+		assert.Equal(t, "JP123", events[1].TrackingNumber)
+	}
+
+	events, err = tracker.Track(context.Background(), "JNE", "UNKNOWN")
+	if assert.NoError(t, err) {
+		assert.Empty(t, events)
+	}
+}
+
+func TestFakePushInvalid(t *testing.T) {
+	tracker := NewFake("secret")
+
+	err := tracker.Push(&Event{Carrier: "JNE", TrackingNumber: "JP123", Status: "lost", OccurredAt: time.Now()})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+
+	err = tracker.Push(&Event{Carrier: "JNE", Status: StatusDelivered, OccurredAt: time.Now()})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestFakeWebhook(t *testing.T) {
+	tracker := NewFake("secret")
+
+	payload, signature, err := tracker.Webhook(&Event{Carrier: "sicepat", TrackingNumber: "00123", Status: StatusDelivered, OccurredAt: time.Now()})
+	assert.NoError(t, err)
+
+	events, err := tracker.ParseWebhook(payload, signature)
+	if assert.NoError(t, err) && assert.Len(t, events, 1) {
+		assert.Equal(t, "SICEPAT", events[0].Carrier)
+		assert.Equal(t, StatusDelivered, events[0].Status)
+	}
+
+	_, err = tracker.ParseWebhook(payload, Sign("other", payload))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = tracker.ParseWebhook([]byte(`{"Events":[]}`), Sign("secret", []byte(`{"Events":[]}`)))
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
@@ -1,15 +1,21 @@
 package router
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 	"pcstakehometest/config"
 	"pcstakehometest/model"
+	idempotencyDto "pcstakehometest/module/idempotency/dto"
 	"pcstakehometest/package/jwt"
+	"pcstakehometest/package/notifier"
 	"pcstakehometest/static"
 	"pcstakehometest/utilities"
 )
@@ -57,3 +63,153 @@ func (r *Router) Authentication(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Idempotency replay stored response of request retried with the same Idempotency-Key, must run after Authentication
+func (r *Router) Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return next(c)
+		}
+
+		data, ok := c.Request().Context().Value(jwt.InternalClaimData{}).(jwt.InternalClaimData)
+		if !ok {
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(errors.New(static.Authorization), http.StatusUnauthorized),
+			})
+		}
+
+		ctx := c.Request().Context()
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			r.Logger.Error(err.Error())
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(errors.New(static.BadRequest), http.StatusBadRequest),
+			})
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		idempotencyKey, err := r.idempotencyLogic.Begin(ctx, &idempotencyDto.BeginRequest{
+			UserID: data.UserID,
+			Key:    key,
+			Method: c.Request().Method,
+			Path:   c.Request().URL.Path,
+			Body:   body,
+		})
+		if err != nil {
+			r.Logger.Error(err.Error())
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: err,
+			})
+		}
+
+		if idempotencyKey.StatusCode != nil {
+			c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+			return c.JSONBlob(*idempotencyKey.StatusCode, idempotencyKey.Response)
+		}
+
+		claim := &idempotencyDto.Claim{ID: idempotencyKey.ID}
+		c.SetRequest(c.Request().WithContext(context.WithValue(ctx, idempotencyDto.Claim{}, claim)))
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		err = next(c)
+		// Response already stored by Commit together with the request transaction
+		if claim.Completed {
+			return err
+		}
+
+		if err != nil || c.Response().Status >= http.StatusInternalServerError {
+			if err := r.idempotencyLogic.Abandon(ctx, idempotencyKey.ID); err != nil {
+				r.Logger.Error(err.Error())
+			}
+			return err
+		}
+
+		if err := r.idempotencyLogic.Complete(ctx, &idempotencyDto.CompleteRequest{
+			ID:         idempotencyKey.ID,
+			StatusCode: c.Response().Status,
+			Response:   recorder.body.Bytes(),
+		}, r.db.Gorm); err != nil {
+			r.Logger.Error(err.Error())
+		}
+
+		return nil
+	}
+}
+
+// Commit commit request transaction, response of idempotent request stored within the same transaction
+// so a retry replay it instead of running the request again, notification of the request sent once committed
+func (r *Router) Commit(c echo.Context, tx *gorm.DB, resp *utilities.ResponseRequest) error {
+	claim, ok := c.Request().Context().Value(idempotencyDto.Claim{}).(*idempotencyDto.Claim)
+	if !ok {
+		if err := tx.Commit().Error; err != nil {
+			r.Logger.Error(err.Error())
+			return utilities.Response(c, &utilities.ResponseRequest{
+				Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+			})
+		}
+		r.notify(c)
+
+		return utilities.Response(c, resp)
+	}
+
+	code, body := utilities.Body(resp)
+	response, err := json.Marshal(body)
+	if err != nil {
+		r.Logger.Error(err.Error())
+		tx.Rollback()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+		})
+	}
+
+	if err := r.idempotencyLogic.Complete(c.Request().Context(), &idempotencyDto.CompleteRequest{
+		ID:         claim.ID,
+		StatusCode: code,
+		Response:   response,
+	}, tx); err != nil {
+		r.Logger.Error(err.Error())
+		tx.Rollback()
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: err,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		r.Logger.Error(err.Error())
+		return utilities.Response(c, &utilities.ResponseRequest{
+			Error: utilities.ErrorRequest(err, http.StatusInternalServerError),
+		})
+	}
+	claim.Completed = true
+	r.notify(c)
+
+	return c.JSONBlob(code, response)
+}
+
+// notify failed delivery should not fail request already committed
+func (r *Router) notify(c echo.Context) {
+	ctx := c.Request().Context()
+	if err := notifier.FromContext(ctx).Send(ctx, r.notifier); err != nil {
+		r.Logger.Error(err.Error())
+	}
+}
+
+// responseRecorder keep copy of response body written by handler
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
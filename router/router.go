@@ -3,7 +3,7 @@ package router
 import (
 	"bytes"
 	"errors"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"time"
 
@@ -11,21 +11,31 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
 	"github.com/sirupsen/logrus"
+	"pcstakehometest/config"
+	"pcstakehometest/database/postgres"
+	idempotencyLogic "pcstakehometest/module/idempotency/logic"
 	userRepo "pcstakehometest/module/user/repository"
 	"pcstakehometest/package/logger"
+	"pcstakehometest/package/notifier"
 	"pcstakehometest/static"
 	"pcstakehometest/utilities"
 )
 
 type Router struct {
 	*echo.Echo
-	userRepo userRepo.IUserRepository
+	db               *postgres.DB
+	userRepo         userRepo.IUserRepository
+	idempotencyLogic idempotencyLogic.IIdempotencyLogic
+	notifier         notifier.Notifier
 }
 
 var RouteLog *logger.LogRus
 
 func NewRouter(logger *logger.LogRus,
-	userRepo userRepo.IUserRepository) *Router {
+	db *postgres.DB,
+	userRepo userRepo.IUserRepository,
+	idempotencyLogic idempotencyLogic.IIdempotencyLogic,
+	notify notifier.Notifier) *Router {
 
 	e := echo.New()
 
@@ -49,7 +59,7 @@ func NewRouter(logger *logger.LogRus,
 			}
 
 			//Read reqData from body
-			reqData, err := ioutil.ReadAll(c.Request().Body)
+			reqData, err := io.ReadAll(c.Request().Body)
 			if err != nil {
 				RouteLog.Error(err.Error())
 				return utilities.Response(c, &utilities.ResponseRequest{
@@ -65,11 +75,11 @@ func NewRouter(logger *logger.LogRus,
 				requestLog.Info(string(reqData))
 			}
 
-			c.Request().Body = ioutil.NopCloser(bytes.NewReader(reqData))
+			c.Request().Body = io.NopCloser(bytes.NewReader(reqData))
 			return next(c)
 		}
 	})
-	e.Use(middleware.BodyLimit("10M"))
+	e.Use(middleware.BodyLimit(config.BodyLimit))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowHeaders: []string{
@@ -78,6 +88,7 @@ func NewRouter(logger *logger.LogRus,
 			echo.HeaderAccept,
 			"Authorization",
 			"Version",
+			HeaderIdempotencyKey,
 		},
 	}))
 	e.Use(middleware.RateLimiterWithConfig(rateLimitConfig()))
@@ -86,8 +97,16 @@ func NewRouter(logger *logger.LogRus,
 		StackSize: 1 << 10,
 		LogLevel:  log.ERROR,
 	}))
+	// Notification raised while handling request held until its transaction committed, see Router.Commit
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, _ := notifier.WithOutbox(c.Request().Context())
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	})
 
-	return &Router{e, userRepo}
+	return &Router{e, db, userRepo, idempotencyLogic, notify}
 }
 
 func rateLimitConfig() middleware.RateLimiterConfig {
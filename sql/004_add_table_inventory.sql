@@ -0,0 +1,40 @@
+-- +goose Up
+alter table products add column stock int not null default 0;
+
+create table inventory_movements (
+    id             bigserial primary key,
+    product_id     int not null,
+    seller_id      int not null,
+    transaction_id int default null,
+    type           int not null,
+    quantity       int not null,
+    stock_after    int not null,
+    note           varchar(255) not null default '',
+    created_at     timestamptz default now(),
+    foreign key    (product_id) references products (id),
+    foreign key    (seller_id) references users (id),
+    foreign key    (transaction_id) references transactions (id)
+);
+
+create index inventory_movements_product_id_idx on inventory_movements (product_id);
+
+create table stock_reservations (
+    id             bigserial primary key,
+    transaction_id int not null,
+    product_id     int not null,
+    quantity       int not null,
+    status         int not null default 1,
+    expired_at     timestamptz not null,
+    updated_at     timestamptz default now(),
+    created_at     timestamptz default now(),
+    foreign key    (transaction_id) references transactions (id),
+    foreign key    (product_id) references products (id)
+);
+
+create index stock_reservations_transaction_id_idx on stock_reservations (transaction_id);
+create index stock_reservations_status_expired_at_idx on stock_reservations (status, expired_at);
+
+-- +goose Down
+drop table stock_reservations;
+drop table inventory_movements;
+alter table products drop column stock;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- +goose Up
+create table product_images (
+    id             bigserial primary key,
+    product_id     int not null,
+    storage_key    varchar(255) not null,
+    thumbnail_key  varchar(255) not null,
+    url            varchar(500) not null,
+    thumbnail_url  varchar(500) not null,
+    content_type   varchar(50) not null,
+    size           bigint not null,
+    width          int not null,
+    height         int not null,
+    position       int not null default 0,
+    is_primary     boolean not null default false,
+    updated_at     timestamptz default now(),
+    created_at     timestamptz default now(),
+    deleted_at     timestamptz default null,
+    foreign key    (product_id) references products (id)
+);
+
+create index product_images_product_id_idx on product_images (product_id);
+
+-- +goose Down
+drop table product_images;
\ No newline at end of file
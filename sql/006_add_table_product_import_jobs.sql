@@ -0,0 +1,25 @@
+-- +goose Up
+alter table products add column sku varchar(100) not null default '';
+
+create unique index products_seller_id_sku_idx on products (seller_id, sku) where sku <> '' and deleted_at is null;
+
+create table product_import_jobs (
+    id            bigserial primary key,
+    seller_id     int not null,
+    status        int not null default 1,
+    dry_run       boolean not null default false,
+    total_rows    int not null default 0,
+    created_rows  int not null default 0,
+    updated_rows  int not null default 0,
+    failed_rows   int not null default 0,
+    errors        json not null default '[]',
+    finished_at   timestamptz default null,
+    updated_at    timestamptz default now(),
+    created_at    timestamptz default now(),
+    foreign key   (seller_id) references users (id)
+);
+
+-- +goose Down
+drop table product_import_jobs;
+drop index products_seller_id_sku_idx;
+alter table products drop column sku;
\ No newline at end of file
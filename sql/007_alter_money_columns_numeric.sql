@@ -0,0 +1,7 @@
+-- +goose Up
+alter table products alter column price type numeric(18,2) using round(price::numeric, 2);
+alter table transactions alter column grand_total type numeric(18,2) using round(grand_total::numeric, 2);
+
+-- +goose Down
+alter table transactions alter column grand_total type float using grand_total::float;
+alter table products alter column price type float using price::float;
\ No newline at end of file
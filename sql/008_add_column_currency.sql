@@ -0,0 +1,19 @@
+-- +goose Up
+alter table products add column currency char(3) not null default 'IDR';
+
+alter table transactions add column currency char(3) not null default 'IDR';
+alter table transactions add column display_currency char(3) not null default 'IDR';
+alter table transactions add column exchange_rate numeric(20,10) not null default 1;
+alter table transactions add column display_grand_total numeric(18,2) not null default 0;
+alter table transactions add column rate_at timestamptz default null;
+
+update transactions set display_grand_total = grand_total;
+
+-- +goose Down
+alter table transactions drop column rate_at;
+alter table transactions drop column display_grand_total;
+alter table transactions drop column exchange_rate;
+alter table transactions drop column display_currency;
+alter table transactions drop column currency;
+
+alter table products drop column currency;
\ No newline at end of file
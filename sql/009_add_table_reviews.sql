@@ -0,0 +1,59 @@
+-- +goose Up
+alter table products add column rating_average numeric(3,2) not null default 0;
+alter table products add column rating_count int not null default 0;
+alter table users add column rating_average numeric(3,2) not null default 0;
+alter table users add column rating_count int not null default 0;
+
+insert into users(username, password, role) values ('Admin','$2a$12$yT.dJTZnu4FRJq9zXw0mBOA/xmZHJPVi5ni13Zk9Pn6E0QmwKkZTu',3);
+
+create table reviews (
+    id              bigserial primary key,
+    product_id      int not null,
+    seller_id       int not null,
+    buyer_id        int not null,
+    transaction_id  int not null,
+    rating          int not null check (rating between 1 and 5),
+    content         text not null default '',
+    photos          jsonb not null default '[]',
+    reply           text default null,
+    replied_at      timestamptz default null,
+    status          int not null default 1,
+    updated_at      timestamptz default now(),
+    created_at      timestamptz default now(),
+    deleted_at      timestamptz default null,
+    foreign key     (product_id) references products (id),
+    foreign key     (seller_id) references users (id),
+    foreign key     (buyer_id) references users (id),
+    foreign key     (transaction_id) references transactions (id)
+);
+
+create unique index reviews_buyer_id_product_id_key on reviews (buyer_id, product_id) where deleted_at is null;
+create index reviews_product_id_idx on reviews (product_id);
+create index reviews_seller_id_idx on reviews (seller_id);
+
+create table review_reports (
+    id           bigserial primary key,
+    review_id    int not null,
+    reporter_id  int not null,
+    reason       text not null,
+    status       int not null default 1,
+    note         text default null,
+    resolved_by  int default null,
+    resolved_at  timestamptz default null,
+    updated_at   timestamptz default now(),
+    created_at   timestamptz default now(),
+    foreign key  (review_id) references reviews (id),
+    foreign key  (reporter_id) references users (id)
+);
+
+create unique index review_reports_review_id_reporter_id_key on review_reports (review_id, reporter_id);
+create index review_reports_status_idx on review_reports (status);
+
+-- +goose Down
+drop table review_reports;
+drop table reviews;
+delete from users where username = 'Admin';
+alter table users drop column rating_count;
+alter table users drop column rating_average;
+alter table products drop column rating_count;
+alter table products drop column rating_average;
\ No newline at end of file
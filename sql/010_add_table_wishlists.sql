@@ -0,0 +1,24 @@
+-- +goose Up
+alter table users add column store_closed_at timestamptz default null;
+
+create table wishlists (
+    id              bigserial primary key,
+    buyer_id        int not null,
+    product_id      int not null,
+    price_snapshot  numeric(18,2) not null,
+    currency        varchar(3) not null,
+    notified_price  numeric(18,2) default null,
+    notified_at     timestamptz default null,
+    updated_at      timestamptz default now(),
+    created_at      timestamptz default now(),
+    deleted_at      timestamptz default null,
+    foreign key     (buyer_id) references users (id),
+    foreign key     (product_id) references products (id)
+);
+
+create unique index wishlists_buyer_id_product_id_key on wishlists (buyer_id, product_id) where deleted_at is null;
+create index wishlists_product_id_idx on wishlists (product_id);
+
+-- +goose Down
+drop table wishlists;
+alter table users drop column store_closed_at;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+alter table products add column status int not null default 3;
+alter table products add column publish_at timestamptz default null;
+alter table products add column published_at timestamptz default null;
+
+update products set published_at = created_at;
+
+create index products_status_publish_at_idx on products (status, publish_at);
+
+-- +goose Down
+drop index products_status_publish_at_idx;
+alter table products drop column published_at;
+alter table products drop column publish_at;
+alter table products drop column status;
\ No newline at end of file
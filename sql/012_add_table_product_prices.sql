@@ -0,0 +1,37 @@
+-- +goose Up
+create table product_price_histories (
+    id          bigserial primary key,
+    product_id  int not null,
+    price       numeric(18,2) not null,
+    currency    varchar(3) not null,
+    changed_by  int not null,
+    created_at  timestamptz default now(),
+    foreign key (product_id) references products (id),
+    foreign key (changed_by) references users (id)
+);
+
+create index product_price_histories_product_id_idx on product_price_histories (product_id, created_at);
+
+insert into product_price_histories (product_id, price, currency, changed_by, created_at)
+select id, price, currency, seller_id, created_at from products;
+
+create table product_sales (
+    id          bigserial primary key,
+    product_id  int not null,
+    seller_id   int not null,
+    sale_price  numeric(18,2) not null,
+    start_at    timestamptz not null,
+    end_at      timestamptz not null,
+    updated_at  timestamptz default now(),
+    created_at  timestamptz default now(),
+    deleted_at  timestamptz default null,
+    foreign key (product_id) references products (id),
+    foreign key (seller_id) references users (id),
+    check       (end_at > start_at)
+);
+
+create index product_sales_product_id_idx on product_sales (product_id, start_at, end_at);
+
+-- +goose Down
+drop table product_sales;
+drop table product_price_histories;
\ No newline at end of file
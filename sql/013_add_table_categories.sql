@@ -0,0 +1,24 @@
+-- +goose Up
+create table categories (
+    id          bigserial primary key,
+    name        varchar(255) not null,
+    attributes  jsonb not null default '[]',
+    updated_at  timestamptz default now(),
+    created_at  timestamptz default now(),
+    deleted_at  timestamptz default null
+);
+
+create unique index categories_name_key on categories (lower(name)) where deleted_at is null;
+
+alter table products add column category_id int default null references categories (id);
+alter table products add column attributes jsonb not null default '{}';
+
+create index products_category_id_idx on products (category_id);
+create index products_attributes_idx on products using gin (attributes);
+
+-- +goose Down
+drop index products_attributes_idx;
+drop index products_category_id_idx;
+alter table products drop column attributes;
+alter table products drop column category_id;
+drop table categories;
\ No newline at end of file
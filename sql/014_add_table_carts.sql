@@ -0,0 +1,19 @@
+-- +goose Up
+create table carts (
+    id              bigserial primary key,
+    buyer_id        int not null,
+    product_id      int not null,
+    quantity        int not null check (quantity > 0),
+    price_snapshot  numeric(18,2) not null,
+    currency        varchar(3) not null,
+    updated_at      timestamptz default now(),
+    created_at      timestamptz default now(),
+    deleted_at      timestamptz default null,
+    foreign key     (buyer_id) references users (id),
+    foreign key     (product_id) references products (id)
+);
+
+create unique index carts_buyer_id_product_id_key on carts (buyer_id, product_id) where deleted_at is null;
+
+-- +goose Down
+drop table carts;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+create table checkouts (
+    id                 bigserial primary key,
+    buyer_id           int not null,
+    payment_reference  varchar(64) not null,
+    grand_total        numeric(18,2) not null default 0,
+    currency           varchar(3) not null,
+    updated_at         timestamptz default now(),
+    created_at         timestamptz default now(),
+    deleted_at         timestamptz default null,
+    foreign key        (buyer_id) references users (id)
+);
+
+create unique index checkouts_payment_reference_key on checkouts (payment_reference);
+
+alter table transactions add column checkout_id int default null references checkouts (id);
+
+create index transactions_checkout_id_idx on transactions (checkout_id);
+
+-- +goose Down
+drop index transactions_checkout_id_idx;
+alter table transactions drop column checkout_id;
+drop table checkouts;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+create table transaction_status_histories (
+    id              bigserial primary key,
+    transaction_id  int not null,
+    from_status     int default null,
+    to_status       int not null,
+    actor_id        int default null,
+    actor_role      int default null,
+    reason          text default null,
+    created_at      timestamptz default now(),
+    foreign key     (transaction_id) references transactions (id)
+);
+
+create index transaction_status_histories_transaction_id_idx on transaction_status_histories (transaction_id);
+
+insert into transaction_status_histories (transaction_id, to_status, actor_id, actor_role, created_at)
+select id, 1, buyer_id, 2, created_at from transactions;
+
+insert into transaction_status_histories (transaction_id, from_status, to_status, actor_id, actor_role, created_at)
+select id, 1, status, seller_id, 1, updated_at from transactions where status = 2;
+
+-- +goose Down
+drop table transaction_status_histories;
\ No newline at end of file
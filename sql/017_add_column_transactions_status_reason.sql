@@ -0,0 +1,5 @@
+-- +goose Up
+alter table transactions add column status_reason text default null;
+
+-- +goose Down
+alter table transactions drop column status_reason;
\ No newline at end of file
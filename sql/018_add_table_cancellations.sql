@@ -0,0 +1,22 @@
+-- +goose Up
+create table cancellations (
+    id              bigserial primary key,
+    transaction_id  int not null,
+    buyer_id        int not null,
+    seller_id       int not null,
+    reason          text not null,
+    status          int not null default 1,
+    note            text default null,
+    resolved_at     timestamptz default null,
+    created_at      timestamptz default now(),
+    updated_at      timestamptz default now(),
+    foreign key     (transaction_id) references transactions (id),
+    foreign key     (buyer_id) references users (id),
+    foreign key     (seller_id) references users (id)
+);
+
+create unique index cancellations_transaction_id_requested_key on cancellations (transaction_id) where status = 1;
+create index cancellations_seller_id_status_idx on cancellations (seller_id, status);
+
+-- +goose Down
+drop table cancellations;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- +goose Up
+create table idempotency_keys (
+    id              bigserial primary key,
+    user_id         int not null,
+    key             varchar(255) not null,
+    fingerprint     varchar(64) not null,
+    status_code     int default null,
+    response        bytea default null,
+    expires_at      timestamptz not null,
+    created_at      timestamptz default now(),
+    updated_at      timestamptz default now(),
+    foreign key     (user_id) references users (id)
+);
+
+create unique index idempotency_keys_user_id_key_key on idempotency_keys (user_id, key);
+create index idempotency_keys_expires_at_idx on idempotency_keys (expires_at);
+
+-- +goose Down
+drop table idempotency_keys;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- +goose Up
+create table payments (
+    id                  bigserial primary key,
+    checkout_id         int not null,
+    buyer_id            int not null,
+    amount              numeric(18,2) not null,
+    currency            varchar(3) not null,
+    provider            varchar(50) not null,
+    provider_reference  varchar(255) not null,
+    status              int not null default 1,
+    failure_reason      text default null,
+    paid_at             timestamptz default null,
+    created_at          timestamptz default now(),
+    updated_at          timestamptz default now(),
+    foreign key         (checkout_id) references checkouts (id),
+    foreign key         (buyer_id) references users (id)
+);
+
+create unique index payments_provider_provider_reference_key on payments (provider, provider_reference);
+create index payments_checkout_id_idx on payments (checkout_id);
+
+alter table transactions add column paid_at timestamptz default null;
+
+-- Order placed before payment existed treated as paid so seller can still accept it
+update transactions set paid_at = created_at;
+
+-- +goose Down
+alter table transactions drop column paid_at;
+drop table payments;
\ No newline at end of file
@@ -0,0 +1,69 @@
+-- +goose Up
+create table returns (
+    id               bigserial primary key,
+    transaction_id   int not null,
+    buyer_id         int not null,
+    seller_id        int not null,
+    reason           text not null,
+    items            jsonb not null,
+    status           int not null default 1,
+    reject_reason    text default null,
+    courier          varchar(100) default null,
+    tracking_number  varchar(100) default null,
+    refund_amount    numeric(18,2) not null,
+    currency         varchar(3) not null,
+    refund_id        int default null,
+    created_at       timestamptz default now(),
+    updated_at       timestamptz default now(),
+    foreign key      (transaction_id) references transactions (id),
+    foreign key      (buyer_id) references users (id),
+    foreign key      (seller_id) references users (id)
+);
+
+create index returns_transaction_id_idx on returns (transaction_id);
+create index returns_seller_id_status_idx on returns (seller_id, status);
+create index returns_buyer_id_idx on returns (buyer_id);
+
+create table return_histories (
+    id           bigserial primary key,
+    return_id    int not null,
+    from_status  int default null,
+    to_status    int not null,
+    actor_id     int default null,
+    actor_role   int default null,
+    note         text default null,
+    created_at   timestamptz default now(),
+    foreign key  (return_id) references returns (id)
+);
+
+create index return_histories_return_id_idx on return_histories (return_id);
+
+create table refunds (
+    id                  bigserial primary key,
+    payment_id          int default null,
+    transaction_id      int not null,
+    return_id           int default null,
+    amount              numeric(18,2) not null,
+    currency            varchar(3) not null,
+    provider            varchar(50) not null,
+    provider_reference  varchar(255) default null,
+    reason              text default null,
+    created_at          timestamptz default now(),
+    foreign key         (payment_id) references payments (id),
+    foreign key         (transaction_id) references transactions (id),
+    foreign key         (return_id) references returns (id)
+);
+
+create index refunds_payment_id_idx on refunds (payment_id);
+create index refunds_transaction_id_idx on refunds (transaction_id);
+
+alter table returns add foreign key (refund_id) references refunds (id);
+
+alter table transactions add column refunded_amount numeric(18,2) not null default 0;
+
+-- +goose Down
+alter table transactions drop column refunded_amount;
+alter table returns drop column refund_id;
+drop table refunds;
+drop table return_histories;
+drop table returns;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- +goose Up
+alter table transactions add column shipping_carrier varchar(50) not null default '';
+alter table transactions add column shipping_service varchar(50) not null default '';
+alter table transactions add column shipping_fee numeric(18,2) not null default 0;
+alter table transactions add column shipping_weight int not null default 0;
+
+-- +goose Down
+alter table transactions drop column shipping_weight;
+alter table transactions drop column shipping_fee;
+alter table transactions drop column shipping_service;
+alter table transactions drop column shipping_carrier;
\ No newline at end of file
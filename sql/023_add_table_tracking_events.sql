@@ -0,0 +1,25 @@
+-- +goose Up
+alter table transactions add column tracking_number varchar(100) not null default '';
+
+create index transactions_shipping_carrier_tracking_number_idx on transactions (shipping_carrier, tracking_number) where tracking_number <> '';
+
+create table tracking_events (
+    id               bigserial primary key,
+    transaction_id   int not null,
+    carrier          varchar(50) not null,
+    tracking_number  varchar(100) not null,
+    status           int not null,
+    description      text not null default '',
+    location         varchar(255) default null,
+    occurred_at      timestamptz not null,
+    created_at       timestamptz default now(),
+    foreign key      (transaction_id) references transactions (id)
+);
+
+-- Same checkpoint delivered by webhook and polling stored once
+create unique index tracking_events_transaction_id_status_occurred_at_key on tracking_events (transaction_id, status, occurred_at);
+
+-- +goose Down
+drop table tracking_events;
+drop index transactions_shipping_carrier_tracking_number_idx;
+alter table transactions drop column tracking_number;
\ No newline at end of file
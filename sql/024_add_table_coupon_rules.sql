@@ -0,0 +1,29 @@
+-- +goose Up
+create table coupon_rules (
+    id           bigserial primary key,
+    name         varchar(255) not null,
+    type         int not null,
+    currency     varchar(3) default null,
+    min_amount   numeric(18,2) default null,
+    max_amount   numeric(18,2) default null,
+    step         numeric(18,2) default null,
+    coupons      int not null default 0,
+    multiplier   numeric(6,2) not null default 1,
+    seller_id    int default null,
+    category_id  int default null,
+    active       boolean not null default true,
+    start_at     timestamptz default null,
+    end_at       timestamptz default null,
+    updated_at   timestamptz default now(),
+    created_at   timestamptz default now(),
+    deleted_at   timestamptz default null,
+    foreign key  (seller_id) references users (id),
+    foreign key  (category_id) references categories (id)
+);
+
+-- Rule replacing coupon previously hardcoded in order creation and acceptance
+insert into coupon_rules (name, type, currency, min_amount, max_amount, coupons) values ('Produk 50.000 - 100.000', 1, 'IDR', 50000, 100000, 1);
+insert into coupon_rules (name, type, currency, step, coupons) values ('Setiap belanja 100.000', 2, 'IDR', 100000, 1);
+
+-- +goose Down
+drop table coupon_rules;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- +goose Up
+alter table transactions add column coupons_redeemed int not null default 0;
+alter table transactions add column coupon_discount numeric(18,2) not null default 0;
+
+create table coupon_ledgers (
+    id              bigserial primary key,
+    buyer_id        int not null,
+    transaction_id  int default null,
+    type            int not null,
+    amount          int not null,
+    remaining       int not null default 0,
+    expires_at      timestamptz default null,
+    created_at      timestamptz default now(),
+    foreign key     (buyer_id) references users (id),
+    foreign key     (transaction_id) references transactions (id)
+);
+
+-- Lot still holding coupon, spent and expired oldest expiry first
+create index coupon_ledgers_buyer_id_expires_at_idx on coupon_ledgers (buyer_id, expires_at) where remaining > 0;
+
+-- Coupon of order already fulfilled carried into the wallet
+insert into coupon_ledgers (buyer_id, transaction_id, type, amount, remaining, expires_at)
+select buyer_id, id, 1, coupons, coupons, now() + interval '365 days'
+from transactions
+where coupons > 0 and status in (2, 4, 5, 6, 7) and deleted_at is null;
+
+-- +goose Down
+drop table coupon_ledgers;
+alter table transactions drop column coupon_discount;
+alter table transactions drop column coupons_redeemed;
\ No newline at end of file
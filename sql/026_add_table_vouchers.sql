@@ -0,0 +1,56 @@
+-- +goose Up
+create table vouchers (
+    id               bigserial primary key,
+    code             varchar(50) not null,
+    name             varchar(255) not null default '',
+    seller_id        int default null,
+    type             int not null,
+    currency         varchar(3) not null,
+    value            numeric(18,2) not null,
+    min_spend        numeric(18,2) default null,
+    max_discount     numeric(18,2) default null,
+    usage_limit      int default null,
+    per_buyer_limit  int default null,
+    used             int not null default 0,
+    active           boolean not null default true,
+    start_at         timestamptz default null,
+    end_at           timestamptz default null,
+    updated_at       timestamptz default now(),
+    created_at       timestamptz default now(),
+    deleted_at       timestamptz default null,
+    foreign key      (seller_id) references users (id)
+);
+
+create unique index vouchers_code_key on vouchers (code) where deleted_at is null;
+
+create table voucher_redemptions (
+    id              bigserial primary key,
+    voucher_id      int not null,
+    buyer_id        int not null,
+    transaction_id  int not null,
+    discount        numeric(18,2) not null,
+    currency        varchar(3) not null,
+    released_at     timestamptz default null,
+    created_at      timestamptz default now(),
+    foreign key     (voucher_id) references vouchers (id),
+    foreign key     (buyer_id) references users (id),
+    foreign key     (transaction_id) references transactions (id)
+);
+
+create index voucher_redemptions_voucher_id_buyer_id_idx on voucher_redemptions (voucher_id, buyer_id) where released_at is null;
+
+alter table transactions add column subtotal numeric(18,2) not null default 0;
+alter table transactions add column voucher_id int default null references vouchers (id);
+alter table transactions add column voucher_code varchar(50) not null default '';
+alter table transactions add column voucher_discount numeric(18,2) not null default 0;
+
+-- Item subtotal of existing order taken back out of its grand total
+update transactions set subtotal = grand_total - shipping_fee + coupon_discount;
+
+-- +goose Down
+alter table transactions drop column voucher_discount;
+alter table transactions drop column voucher_code;
+alter table transactions drop column voucher_id;
+alter table transactions drop column subtotal;
+drop table voucher_redemptions;
+drop table vouchers;
\ No newline at end of file
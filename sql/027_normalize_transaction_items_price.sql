@@ -0,0 +1,23 @@
+-- +goose Up
+-- Item snapshot written while price was float may carry more than 2 decimals, round it the same way 007 rounded the columns
+update transactions t
+set items = (
+    select json_agg(
+        case
+            when jsonb_typeof(e.item->'Price') = 'number'
+                then jsonb_set(e.item, '{Price}', to_jsonb(round((e.item->>'Price')::numeric, 2)))
+            else e.item
+        end
+        order by e.position)
+    from jsonb_array_elements(t.items::jsonb) with ordinality as e(item, position)
+)
+where json_typeof(t.items) = 'array'
+  and exists (
+    select 1
+    from json_array_elements(t.items) as i(item)
+    where json_typeof(i.item->'Price') = 'number'
+      and (i.item->>'Price')::numeric <> round((i.item->>'Price')::numeric, 2)
+  );
+
+-- +goose Down
+-- Rounded price can not be restored
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Refund recorded as pending within the order transaction and sent to the gateway after commit,
+-- refund made before the column existed already went through the gateway
+alter table refunds add column status int not null default 2;
+alter table refunds alter column status set default 1;
+alter table refunds add column failure_reason text default null;
+alter table refunds add column updated_at timestamptz default now();
+
+create index refunds_status_idx on refunds (status);
+
+-- +goose Down
+drop index refunds_status_idx;
+alter table refunds drop column updated_at;
+alter table refunds drop column failure_reason;
+alter table refunds drop column status;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Coupon evaluated when the order was placed, issued once seller accept it, empty for order placed before the column existed
+alter table transactions add column coupons_earned int default null;
+
+-- +goose Down
+alter table transactions drop column coupons_earned;
\ No newline at end of file
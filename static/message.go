@@ -10,7 +10,127 @@ const (
 	BadRequest         = "data payload tidak benar"
 
 	// General Message
-	DataNotFound = "%v tidak ditemukan"
-	MinValue     = "%v harus lebih dari %v"
-	EmptyValue   = "%v tidak boleh kosong"
+	DataNotFound  = "%v tidak ditemukan"
+	MinValue      = "%v harus lebih dari %v"
+	EmptyValue    = "%v tidak boleh kosong"
+	NegativeValue = "%v tidak boleh negatif"
+	AlreadyExist  = "%v sudah digunakan"
+	InvalidRange  = "%v harus antara %v dan %v"
+
+	// Inventory Message
+	OutOfStock          = "stok %v tidak mencukupi"
+	ReservationReleased = "reservasi stok transaksi sudah dilepas"
+	ReservationExpired  = "reservasi stok kedaluwarsa sebelum pesanan diterima"
+
+	// Image Message
+	ImageTooLarge     = "ukuran gambar maksimal %v"
+	ImageTooManyPixel = "resolusi gambar maksimal %v piksel"
+	ImageNotSupported = "tipe gambar tidak didukung"
+
+	// Import Message
+	InvalidCSVHeader = "kolom CSV %v tidak ditemukan"
+	InvalidCSVValue  = "nilai %v tidak valid"
+	DuplicateCSVRow  = "%v duplikat pada baris %v"
+
+	// Currency Message
+	CurrencyNotSupported = "mata uang %v tidak didukung"
+	CurrencyMismatch     = "mata uang produk dalam satu transaksi harus sama"
+
+	// Review Message
+	ReviewNotEligible     = "ulasan hanya dapat diberikan untuk produk dari transaksi yang sudah diterima"
+	ReviewAlreadyExist    = "ulasan untuk produk ini sudah diberikan"
+	ReviewPhotoLimit      = "foto ulasan maksimal %v"
+	ReviewAlreadyReported = "ulasan sudah dilaporkan"
+	ReportAlreadyResolved = "laporan sudah diproses"
+
+	// Product Message
+	PublishAtPast = "waktu publikasi harus setelah waktu sekarang"
+
+	// Attribute Message
+	InvalidAttributeSchema = "skema atribut %v tidak valid"
+	InvalidAttribute       = "nilai atribut %v tidak valid"
+	UnknownAttribute       = "atribut %v tidak terdaftar pada kategori"
+
+	// Price Message
+	SalePriceTooHigh  = "harga promo harus lebih rendah dari harga normal"
+	SalePeriodInvalid = "waktu berakhir promo harus setelah waktu mulai dan waktu sekarang"
+	SaleOverlap       = "periode promo bertabrakan dengan promo lain"
+	SaleEnded         = "promo sudah berakhir"
+	SaleCurrencyLock  = "mata uang tidak dapat diubah selama masih ada promo berjalan atau terjadwal"
+
+	// Coupon Message
+	CouponBandInvalid   = "harga minimum harus lebih rendah dari harga maksimum"
+	CouponPeriodInvalid = "waktu berakhir aturan harus setelah waktu mulai"
+	CouponInsufficient  = "kupon tidak mencukupi, tersisa %v"
+	CouponExceedTotal   = "potongan kupon melebihi total belanja %v %v"
+
+	// Voucher Message
+	VoucherNotApplicable = "voucher %v tidak berlaku untuk pesanan ini"
+	VoucherMinSpend      = "minimal belanja voucher %v adalah %v %v"
+	VoucherUsedUp        = "kuota voucher %v sudah habis"
+	VoucherBuyerLimit    = "batas penggunaan voucher %v sudah tercapai"
+	VoucherPeriodInvalid = "waktu berakhir voucher harus setelah waktu mulai"
+
+	// Store Message
+	StoreClosed = "toko %v sedang tutup"
+
+	// Transaction Message
+	InvalidTransition  = "status transaksi tidak dapat diubah dari %v ke %v"
+	TransactionChanged = "status transaksi sudah berubah, muat ulang transaksi"
+	InvalidBulkAction  = "aksi %v tidak dikenal, gunakan accept atau reject"
+	MaxBulkOrder       = "maksimal %v transaksi dalam satu permintaan"
+
+	// Idempotency Message
+	IdempotencyKeyTooLong = "Idempotency-Key maksimal %v karakter"
+	IdempotencyKeyReused  = "Idempotency-Key sudah digunakan untuk permintaan dengan data berbeda"
+	IdempotencyInProgress = "permintaan dengan Idempotency-Key yang sama sedang diproses"
+
+	// Payment Message
+	OrderNotPaid          = "pesanan belum dibayar"
+	PaymentAlreadyPaid    = "checkout sudah dibayar"
+	PaymentNothingToPay   = "tidak ada pesanan yang perlu dibayar pada checkout ini"
+	PaymentAlreadySettled = "status pembayaran sudah %v"
+	PaymentTimeout        = "gateway pembayaran tidak merespon, coba beberapa saat lagi"
+	PaymentGatewayFailed  = "gateway pembayaran menolak permintaan"
+	PaymentIntentStale    = "total checkout berubah, tagihan diganti"
+	PaymentAmountMismatch = "pembayaran %v %v tidak sesuai tagihan %v %v"
+	PaymentExcessRefund   = "pesanan tidak lagi perlu dibayar, kelebihan pembayaran dikembalikan"
+	RefundExceedPayment   = "pengembalian dana melebihi sisa pembayaran %v %v"
+	RefundExceedOrder     = "pengembalian dana melebihi sisa total pesanan %v %v"
+	OrderPaidTitle        = "Pesanan dibayar"
+	OrderPaidBody         = "Pesanan #%v sudah dibayar dan siap diproses"
+
+	// Shipping Message
+	ShippingRouteNotFound   = "pengiriman dari %v ke %v tidak tersedia"
+	ShippingServiceNotFound = "layanan pengiriman %v %v tidak tersedia untuk rute ini"
+
+	ShipmentUpdatedTitle = "Pengiriman diperbarui"
+	ShipmentUpdatedBody  = "Pesanan #%v: %v"
+
+	// Return Message
+	ReturnNotEligible       = "retur hanya dapat diajukan untuk pesanan yang sudah diterima"
+	ReturnQuantityExceed    = "jumlah retur %v melebihi sisa jumlah yang dapat diretur"
+	InvalidReturnTransition = "status retur tidak dapat diubah dari %v ke %v"
+	ReturnChanged           = "status retur sudah berubah, muat ulang retur"
+	ReturnUpdatedTitle      = "Retur diperbarui"
+	ReturnUpdatedBody       = "Retur #%v untuk pesanan #%v %v"
+
+	// Cancellation Message
+	CancellationRequested   = "permintaan pembatalan sudah diajukan, menunggu persetujuan penjual"
+	CancellationNotFound    = "permintaan pembatalan"
+	OrderCancelledTitle     = "Pesanan dibatalkan"
+	OrderCancelledBody      = "Pesanan #%v dibatalkan oleh pembeli: %v"
+	CancellationTitle       = "Permintaan pembatalan"
+	CancellationBody        = "Pembeli meminta pembatalan pesanan #%v: %v"
+	CancellationResultTitle = "Permintaan pembatalan diproses"
+	CancellationResultBody  = "Permintaan pembatalan pesanan #%v %v"
+
+	// Cart Message
+	CartUnavailable  = "produk %v tidak tersedia"
+	CartPriceChanged = "harga %v berubah dari %v menjadi %v %v"
+	CartConfirmPrice = "harga produk di keranjang berubah, konfirmasi perubahan harga sebelum checkout"
+
+	// Wishlist Message
+	PriceDropTitle = "Harga turun"
+	PriceDropBody  = "Harga %v turun dari %v menjadi %v %v"
 )
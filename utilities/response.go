@@ -13,13 +13,19 @@ type ResponseRequest struct {
 
 // Response :
 func Response(c echo.Context, r *ResponseRequest) error {
+	code, resp := Body(r)
+	return c.JSON(code, resp)
+}
+
+// Body status code and body written by Response
+func Body(r *ResponseRequest) (int, map[string]interface{}) {
 	resp := make(map[string]interface{})
 	if r.Error != nil {
 		errInfo, ok := r.Error.(*Error)
 		if ok {
 			resp["Code"] = errInfo.StatusCode
 			resp["Status"] = errInfo.Error()
-			return c.JSON(errInfo.StatusCode, resp)
+			return errInfo.StatusCode, resp
 		}
 	}
 	resp["Code"] = r.Code
@@ -27,5 +33,5 @@ func Response(c echo.Context, r *ResponseRequest) error {
 	if r.Data != nil {
 		resp["Data"] = r.Data
 	}
-	return c.JSON(r.Code, resp)
+	return r.Code, resp
 }